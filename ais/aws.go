@@ -404,14 +404,17 @@ func (awsp *awsProvider) getObj(ctx context.Context, workFQN string, lom *cluste
 // PUT OBJECT //
 ////////////////
 
-func (awsp *awsProvider) putObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+func (awsp *awsProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
 	var (
-		uploadOutput          *s3manager.UploadOutput
-		cksumType, cksumValue = lom.Cksum().Get()
-		md                    = make(map[string]*string)
+		uploadOutput *s3manager.UploadOutput
+		md           = make(map[string]*string)
 	)
-	md[awsChecksumType] = aws.String(cksumType)
-	md[awsChecksumVal] = aws.String(cksumValue)
+	// NOTE: checksum is not known in advance when the object is streamed
+	if cksum := lom.Cksum(); cksum != nil {
+		cksumType, cksumValue := cksum.Get()
+		md[awsChecksumType] = aws.String(cksumType)
+		md[awsChecksumVal] = aws.String(cksumValue)
+	}
 
 	uploader := s3manager.NewUploader(createSession(ctx))
 	uploadOutput, err = uploader.Upload(&s3manager.UploadInput{
@@ -41,7 +41,7 @@ func (m *emptyCloudProvider) headObj(ctx context.Context, lom *cluster.LOM) (obj
 func (m *emptyCloudProvider) getObj(ctx context.Context, fqn string, lom *cluster.LOM) (err error, errCode int) {
 	return cmn.NewErrorCloudBucketDoesNotExist(lom.Bucket()), http.StatusNotFound
 }
-func (m *emptyCloudProvider) putObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+func (m *emptyCloudProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
 	return "", cmn.NewErrorCloudBucketDoesNotExist(lom.Bucket()), http.StatusNotFound
 }
 func (m *emptyCloudProvider) deleteObj(ctx context.Context, lom *cluster.LOM) (err error, errCode int) {
@@ -323,7 +323,7 @@ func (r *smapowner) persist(newSmap *smapX) error {
 // new cluster.Snode
 //
 //=====================================================================
-func newSnode(id, proto, daeType string, publicAddr, intraControlAddr, intraDataAddr *net.TCPAddr) (snode *cluster.Snode) {
+func newSnode(id, proto, intraProto, daeType string, publicAddr, intraControlAddr, intraDataAddr *net.TCPAddr) (snode *cluster.Snode) {
 	publicNet := cluster.NetInfo{
 		NodeIPAddr: publicAddr.IP.String(),
 		DaemonPort: strconv.Itoa(publicAddr.Port),
@@ -334,7 +334,7 @@ func newSnode(id, proto, daeType string, publicAddr, intraControlAddr, intraData
 		intraControlNet = cluster.NetInfo{
 			NodeIPAddr: intraControlAddr.IP.String(),
 			DaemonPort: strconv.Itoa(intraControlAddr.Port),
-			DirectURL:  proto + "://" + intraControlAddr.String(),
+			DirectURL:  intraProto + "://" + intraControlAddr.String(),
 		}
 	}
 	intraDataNet := publicNet
@@ -342,7 +342,7 @@ func newSnode(id, proto, daeType string, publicAddr, intraControlAddr, intraData
 		intraDataNet = cluster.NetInfo{
 			NodeIPAddr: intraDataAddr.IP.String(),
 			DaemonPort: strconv.Itoa(intraDataAddr.Port),
-			DirectURL:  proto + "://" + intraDataAddr.String(),
+			DirectURL:  intraProto + "://" + intraDataAddr.String(),
 		}
 	}
 	snode = &cluster.Snode{DaemonID: id, DaemonType: daeType, PublicNet: publicNet, IntraControlNet: intraControlNet, IntraDataNet: intraDataNet}
@@ -373,14 +373,17 @@ func (gcpp *gcpProvider) getObj(ctx context.Context, workFQN string, lom *cluste
 // PUT OBJECT //
 ////////////////
 
-func (gcpp *gcpProvider) putObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+func (gcpp *gcpProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
 	gcpClient, gctx, _, err := createClient(ctx)
 	if err != nil {
 		return
 	}
 
 	md := make(cmn.SimpleKVs)
-	md[gcpChecksumType], md[gcpChecksumVal] = lom.Cksum().Get()
+	// NOTE: checksum is not known in advance when the object is streamed
+	if cksum := lom.Cksum(); cksum != nil {
+		md[gcpChecksumType], md[gcpChecksumVal] = cksum.Get()
+	}
 
 	gcpObj := gcpClient.Bucket(lom.Bucket()).Object(lom.Objname)
 	wc := gcpObj.NewWriter(gctx)
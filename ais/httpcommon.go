@@ -6,6 +6,7 @@ package ais
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -32,6 +33,8 @@ import (
 	"github.com/NVIDIA/aistore/xaction"
 	"github.com/OneOfOne/xxhash"
 	jsoniter "github.com/json-iterator/go"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 const ( //  h.call(timeout)
@@ -95,7 +98,9 @@ type (
 	netServer struct {
 		s             *http.Server
 		mux           *mux.ServeMux
+		tlsConf       *tls.Config // intra-cluster TLS, see cmn.TLSConf
 		sndRcvBufSize int
+		http2         bool // serves multiplexed intra-cluster data streams, see cmn.NewHTTP2Transport
 	}
 	httprunner struct {
 		cmn.Named
@@ -121,7 +126,6 @@ type (
 
 		headObj(ctx context.Context, lom *cluster.LOM) (objMeta cmn.SimpleKVs, err error, errCode int)
 		getObj(ctx context.Context, fqn string, lom *cluster.LOM) (err error, errCode int)
-		putObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int)
 		deleteObj(ctx context.Context, lom *cluster.LOM) (err error, errCode int)
 	}
 
@@ -237,7 +241,30 @@ func (server *netServer) listenAndServe(addr string, logger *log.Logger) error {
 	if server.sndRcvBufSize > 0 {
 		server.s.ConnState = server.connStateListener // setsockopt; see also cmn.NewTransport
 	}
-	if config.Net.HTTP.UseHTTPS {
+	if server.tlsConf != nil {
+		server.s.TLSConfig = server.tlsConf
+	}
+	if server.http2 {
+		h2s := cmn.NewHTTP2Server()
+		if server.tlsConf != nil || config.Net.HTTP.UseHTTPS {
+			if server.tlsConf != nil {
+				server.s.TLSConfig = server.tlsConf.Clone() // NOTE: shared with the intra-control server
+			}
+			if err := http2.ConfigureServer(server.s, h2s); err != nil {
+				return err
+			}
+		} else {
+			server.s.Handler = h2c.NewHandler(httpHandler, h2s) // HTTP/1.x requests pass through
+		}
+	}
+	if server.tlsConf != nil {
+		if err := server.s.ListenAndServeTLS("", ""); err != nil {
+			if err != http.ErrServerClosed {
+				glog.Errorf("Terminated server with err: %v", err)
+				return err
+			}
+		}
+	} else if config.Net.HTTP.UseHTTPS {
 		if err := server.s.ListenAndServeTLS(config.Net.HTTP.Certificate, config.Net.HTTP.Key); err != nil {
 			if err != http.ErrServerClosed {
 				glog.Errorf("Terminated server with err: %v", err)
@@ -343,16 +370,26 @@ func (h *httprunner) registerIntraDataNetHandler(path string, handler func(http.
 }
 
 func (h *httprunner) init(s stats.Tracker, config *cmn.Config) {
+	var serverTLS *tls.Config
+	clientTLS, err := config.Net.TLS.ClientConfig()
+	if err == nil && config.Net.TLS.Enabled {
+		serverTLS, err = config.Net.TLS.ServerConfig()
+	}
+	if err != nil {
+		cmn.ExitLogf("Failed to load intra-cluster TLS configuration: %v", err)
+	}
+
 	h.statsif = s
 	h.httpclient = cmn.NewClient(cmn.TransportArgs{
 		Timeout:  config.Timeout.Default,
+		TLS:      clientTLS,
 		UseHTTPS: config.Net.HTTP.UseHTTPS,
 	})
 	h.httpclientGetPut = cmn.NewClient(cmn.TransportArgs{
 		Timeout:         config.Timeout.DefaultLong,
 		WriteBufferSize: config.Net.HTTP.WriteBufferSize,
 		ReadBufferSize:  config.Net.HTTP.ReadBufferSize,
-		UseHTTPS:        false, // always plain intra-cluster http for data
+		TLS:             clientTLS, // plain intra-cluster http for data unless net.tls is enabled
 	})
 
 	bufsize := config.Net.L4.SndRcvBufSize
@@ -367,6 +404,7 @@ func (h *httprunner) init(s stats.Tracker, config *cmn.Config) {
 	if config.Net.UseIntraControl {
 		h.intraControlServer = &netServer{
 			mux:           mux.NewServeMux(),
+			tlsConf:       serverTLS,
 			sndRcvBufSize: 0,
 		}
 	}
@@ -374,7 +412,9 @@ func (h *httprunner) init(s stats.Tracker, config *cmn.Config) {
 	if config.Net.UseIntraData {
 		h.intraDataServer = &netServer{
 			mux:           mux.NewServeMux(),
+			tlsConf:       serverTLS,
 			sndRcvBufSize: bufsize,
+			http2:         config.Net.HTTP.HTTP2, // never h2c on the public network
 		}
 	}
 
@@ -472,7 +512,11 @@ func (h *httprunner) initSI(daemonType string) {
 		}
 	}
 
-	h.si = newSnode(daemonID, config.Net.HTTP.Proto, daemonType, publicAddr, intraControlAddr, intraDataAddr)
+	intraProto := config.Net.HTTP.Proto
+	if config.Net.TLS.Enabled {
+		intraProto = "https"
+	}
+	h.si = newSnode(daemonID, config.Net.HTTP.Proto, intraProto, daemonType, publicAddr, intraControlAddr, intraDataAddr)
 }
 
 func (h *httprunner) run() error {
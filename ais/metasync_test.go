@@ -78,7 +78,7 @@ func serverTCPAddr(u string) *net.TCPAddr {
 func newPrimary() *proxyrunner {
 	p := proxyrunner{}
 	p.smapowner = newSmapowner()
-	p.si = newSnode("primary", httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
+	p.si = newSnode("primary", httpProto, httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
 	smap := newSmap()
 	smap.addProxy(p.si)
 	smap.ProxySI = p.si
@@ -126,9 +126,9 @@ func newTransportServer(primary *proxyrunner, s *metaSyncServer, ch chan<- trans
 	addrInfo := serverTCPAddr(ts.URL)
 	clone := primary.smapowner.get().clone()
 	if s.isProxy {
-		clone.Pmap[id] = newSnode(id, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		clone.Pmap[id] = newSnode(id, httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 	} else {
-		clone.Tmap[id] = newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		clone.Tmap[id] = newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 	}
 	clone.Version++
 	primary.smapowner.put(clone)
@@ -418,7 +418,7 @@ func refused(t *testing.T, primary *proxyrunner, syncer *metasyncer) ([]transpor
 	})
 
 	clone := primary.smapowner.get().clone()
-	clone.Pmap[id] = newSnode(id, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+	clone.Pmap[id] = newSnode(id, httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 	clone.Version++
 	primary.smapowner.put(clone)
 
@@ -496,9 +496,9 @@ func TestMetaSyncData(t *testing.T) {
 		addrInfo := serverTCPAddr(ts.URL)
 		clone := primary.smapowner.get().clone()
 		if s.isProxy {
-			clone.Pmap[id] = newSnode(id, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+			clone.Pmap[id] = newSnode(id, httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 		} else {
-			clone.Tmap[id] = newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+			clone.Tmap[id] = newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 		}
 		clone.Version++
 		primary.smapowner.put(clone)
@@ -641,7 +641,7 @@ func TestMetaSyncMembership(t *testing.T) {
 		id := "t"
 		addrInfo := serverTCPAddr(s.URL)
 		clone := primary.smapowner.get().clone()
-		clone.addTarget(newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
+		clone.addTarget(newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
 		primary.smapowner.put(clone)
 		msgInt := primary.newActionMsgInternalStr("", clone, nil)
 		syncer.sync(true, revspair{clone, msgInt})
@@ -684,7 +684,7 @@ func TestMetaSyncMembership(t *testing.T) {
 
 		id := "t1111"
 		addrInfo := serverTCPAddr(s1.URL)
-		di := newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		di := newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 		clone := primary.smapowner.get().clone()
 		clone.addTarget(di)
 		primary.smapowner.put(clone)
@@ -708,7 +708,7 @@ func TestMetaSyncMembership(t *testing.T) {
 
 		id := "t22222"
 		addrInfo := serverTCPAddr(s2.URL)
-		di := newSnode(id, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
+		di := newSnode(id, httpProto, httpProto, cmn.Target, addrInfo, &net.TCPAddr{}, &net.TCPAddr{})
 		clone := primary.smapowner.get().clone()
 		clone.addTarget(di)
 		primary.smapowner.put(clone)
@@ -771,11 +771,11 @@ func TestMetaSyncReceive(t *testing.T) {
 		defer s.Close()
 		addrInfo := serverTCPAddr(s.URL)
 		clone := primary.smapowner.get().clone()
-		clone.addProxy(newSnode("proxy1", httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
+		clone.addProxy(newSnode("proxy1", httpProto, httpProto, cmn.Proxy, addrInfo, &net.TCPAddr{}, &net.TCPAddr{}))
 		primary.smapowner.put(clone)
 
 		proxy1 := proxyrunner{}
-		proxy1.si = newSnode("p1", httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
+		proxy1.si = newSnode("p1", httpProto, httpProto, cmn.Proxy, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{})
 		proxy1.smapowner = newSmapowner()
 		proxy1.smapowner.put(newSmap())
 		proxy1.bmdowner = newBMDOwnerPrx(cmn.GCO.Get())
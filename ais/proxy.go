@@ -29,6 +29,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/dsort"
+	"github.com/NVIDIA/aistore/notif"
 	"github.com/NVIDIA/aistore/objwalk"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xaction"
@@ -327,6 +328,10 @@ func (p *proxyrunner) httpbckget(w http.ResponseWriter, r *http.Request) {
 
 		p.getbucketnames(w, r, normalizedProvider)
 	default:
+		if r.URL.Query().Get(cmn.URLParamWhat) == cmn.GetWhatChanges {
+			p.bucketChanges(w, r, apiItems[0])
+			return
+		}
 		s := fmt.Sprintf("Invalid route /buckets/%s", apiItems[0])
 		p.invalmsghdlr(w, r, s)
 	}
@@ -1127,6 +1132,9 @@ func (p *proxyrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 	case cmn.ActPromote:
 		p.promoteFQN(w, r, bck, &msg)
 		return
+	case cmn.ActListArchive, cmn.ActExtract:
+		p.objArchive(w, r, bck)
+		return
 	default:
 		s := fmt.Sprintf(fmtUnknownAct, msg)
 		p.invalmsghdlr(w, r, s)
@@ -1210,6 +1218,15 @@ func (p *proxyrunner) applyNewProps(bck *cluster.Bck, propsToUpdate cmn.BucketPr
 		nprops.Mirror.Enabled = false
 	}
 
+	if !bprops.Notif.Enabled && nprops.Notif.Enabled {
+		if nprops.Notif.BatchSize == 0 {
+			nprops.Notif.BatchSize = notif.DefaultBatchSize
+		}
+		if nprops.Notif.BatchTimeStr == "" {
+			nprops.Notif.BatchTimeStr = notif.DefaultBatchTime.String()
+		}
+	}
+
 	if nprops.Cksum.Type == cmn.PropInherit {
 		nprops.Cksum.Type = cfg.Cksum.Type
 	}
@@ -2096,6 +2113,28 @@ func (p *proxyrunner) objRename(w http.ResponseWriter, r *http.Request, bck *clu
 	p.statsif.Add(stats.RenameCount, 1)
 }
 
+// objArchive redirects the request to list or extract the archive to the
+// target which stores the archive.
+func (p *proxyrunner) objArchive(w http.ResponseWriter, r *http.Request, bck *cluster.Bck) {
+	started := time.Now()
+	apitems, err := p.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	objname := apitems[1]
+	smap := p.smapowner.get()
+	si, err := cluster.HrwTarget(bck.MakeUname(objname), &smap.Smap)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s %s/%s => %s", r.Method, bck.Name, objname, si)
+	}
+	redirectURL := p.redirectURL(r, si, started, cmn.NetworkIntraControl)
+	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+}
+
 func (p *proxyrunner) promoteFQN(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, msg *cmn.ActionMsg) {
 	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Objects)
 	if err != nil {
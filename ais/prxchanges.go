@@ -0,0 +1,119 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	changePollInterval = time.Second     // long-poll: interval between queries of the targets
+	changeMaxWait      = 5 * time.Minute // long-poll: upper bound of the wait
+)
+
+// GET /v1/buckets/bucket-name?what=changes
+//
+// Aggregates the change feeds of all targets (see tgtchanges.go). If there are
+// no changes yet, waits for them up to the duration given by URLParamWait.
+func (p *proxyrunner) bucketChanges(w http.ResponseWriter, r *http.Request, bucket string) {
+	var (
+		wait     time.Duration
+		query    = r.URL.Query()
+		provider = query.Get(cmn.URLParamProvider)
+		bck      = &cluster.Bck{Name: bucket, Provider: provider}
+	)
+	if err := bck.Init(p.bmdowner); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	token, err := cmn.DecodeChangeToken(query.Get(cmn.URLParamChangeToken))
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if s := query.Get(cmn.URLParamWait); s != "" {
+		if wait, err = time.ParseDuration(s); err != nil || wait < 0 {
+			p.invalmsghdlr(w, r, fmt.Sprintf("invalid %s value: %q", cmn.URLParamWait, s))
+			return
+		}
+		wait = cmn.MinDur(wait, changeMaxWait)
+	}
+
+	q := url.Values{}
+	q.Set(cmn.URLParamWhat, cmn.GetWhatChanges)
+	q.Set(cmn.URLParamProvider, bck.Provider)
+	q.Set(cmn.URLParamChangeToken, query.Get(cmn.URLParamChangeToken))
+	deadline := time.Now().Add(wait)
+	for {
+		changes, err := p.gatherBucketChanges(bck, q, token)
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		remaining := time.Until(deadline)
+		if len(changes.Changes) > 0 || changes.Reset || remaining <= 0 {
+			body := cmn.MustMarshal(changes)
+			p.writeJSON(w, r, body, "bucket_changes")
+			return
+		}
+		time.Sleep(cmn.MinDur(remaining, changePollInterval))
+		// the position in the feeds of the targets has not changed but the
+		// targets could have been added or removed
+		q.Set(cmn.URLParamChangeToken, changes.Token)
+		if token, err = cmn.DecodeChangeToken(changes.Token); err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return
+		}
+	}
+}
+
+func (p *proxyrunner) gatherBucketChanges(bck *cluster.Bck, q url.Values,
+	prevToken cmn.ChangeToken) (*cmn.BucketChanges, error) {
+	var (
+		changes = &cmn.BucketChanges{Changes: []cmn.BucketChange{}}
+		token   = make(cmn.ChangeToken)
+		args    = bcastArgs{
+			req: cmn.ReqArgs{
+				Path:  cmn.URLPath(cmn.Version, cmn.Buckets, bck.Name),
+				Query: q,
+			},
+			to: cluster.Targets,
+		}
+	)
+	for res := range p.bcastGet(args) {
+		if res.err != nil {
+			return nil, fmt.Errorf("%s: failed to get changes of %s, err: %v", res.si, bck, res.err)
+		}
+		var tchanges tgtChanges
+		if err := jsoniter.Unmarshal(res.outjson, &tchanges); err != nil {
+			return nil, err
+		}
+		changes.Changes = append(changes.Changes, tchanges.Changes...)
+		changes.Reset = changes.Reset || tchanges.Reset
+		token[res.si.DaemonID] = tchanges.Cursor
+	}
+	// The changes of the target that has left the cluster are lost.
+	for sid := range prevToken {
+		if _, ok := token[sid]; !ok {
+			changes.Reset = true
+		}
+	}
+	if changes.Reset {
+		changes.Changes = changes.Changes[:0]
+	}
+	sort.SliceStable(changes.Changes, func(i, j int) bool {
+		return changes.Changes[i].Time < changes.Changes[j].Time
+	})
+	changes.Token = token.Encode()
+	return changes, nil
+}
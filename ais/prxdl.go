@@ -25,7 +25,7 @@ type dlResponse struct {
 	err        error
 }
 
-func (p *proxyrunner) targetDownloadRequest(method string, path string, body []byte, query url.Values, si *cluster.Snode) dlResponse {
+func (p *proxyrunner) targetDownloadRequest(method string, path string, body []byte, query url.Values, header http.Header, si *cluster.Snode) dlResponse {
 	fullQuery := url.Values{}
 	for k, vs := range query {
 		for _, v := range vs {
@@ -41,6 +41,7 @@ func (p *proxyrunner) targetDownloadRequest(method string, path string, body []b
 			Method: method,
 			Path:   cmn.URLPath(cmn.Version, cmn.Download, path),
 			Query:  fullQuery,
+			Header: header,
 			Body:   body,
 		},
 		timeout: cmn.DefaultTimeout,
@@ -53,7 +54,7 @@ func (p *proxyrunner) targetDownloadRequest(method string, path string, body []b
 	}
 }
 
-func (p *proxyrunner) broadcastDownloadRequest(method string, path string, body []byte, query url.Values) []dlResponse {
+func (p *proxyrunner) broadcastDownloadRequest(method string, path string, body []byte, query url.Values, header http.Header) []dlResponse {
 	var (
 		smap        = p.smapowner.get()
 		wg          = &sync.WaitGroup{}
@@ -64,7 +65,7 @@ func (p *proxyrunner) broadcastDownloadRequest(method string, path string, body
 	for _, si := range smap.Tmap {
 		wg.Add(1)
 		go func(si *cluster.Snode) {
-			responsesCh <- p.targetDownloadRequest(method, path, body, query, si)
+			responsesCh <- p.targetDownloadRequest(method, path, body, query, header, si)
 			wg.Done()
 		}(si)
 	}
@@ -83,7 +84,7 @@ func (p *proxyrunner) broadcastDownloadRequest(method string, path string, body
 
 func (p *proxyrunner) broadcastDownloadAdminRequest(method string, path string, msg *cmn.DlAdminBody) ([]byte, int, error) {
 	body := cmn.MustMarshal(msg)
-	responses := p.broadcastDownloadRequest(method, path, body, url.Values{})
+	responses := p.broadcastDownloadRequest(method, path, body, url.Values{}, nil)
 
 	notFoundCnt := 0
 	errs := make([]dlResponse, 0, 10) // errors other than than 404 (not found)
@@ -135,7 +136,9 @@ func (p *proxyrunner) broadcastDownloadAdminRequest(method string, path string,
 
 		finished, total, numPending, scheduled := 0, 0, 0, 0
 		allDispatchedCnt := 0
-		aborted := false
+		aborted, paused := false, false
+		priority := 0
+		var nextSync time.Time
 
 		currTasks := make([]cmn.TaskDlInfo, 0, len(stats))
 		finishedTasks := make([]cmn.TaskDlInfo, 0, len(stats))
@@ -147,9 +150,16 @@ func (p *proxyrunner) broadcastDownloadAdminRequest(method string, path string,
 			scheduled += stat.Scheduled
 
 			aborted = aborted || stat.Aborted
+			paused = paused || stat.Paused
+			if stat.Priority > priority {
+				priority = stat.Priority
+			}
 			if stat.AllDispatched {
 				allDispatchedCnt++
 			}
+			if !stat.NextSync.IsZero() && (nextSync.IsZero() || stat.NextSync.Before(nextSync)) {
+				nextSync = stat.NextSync
+			}
 
 			currTasks = append(currTasks, stat.CurrentTasks...)
 			finishedTasks = append(finishedTasks, stat.FinishedTasks...)
@@ -166,11 +176,14 @@ func (p *proxyrunner) broadcastDownloadAdminRequest(method string, path string,
 			Errs:          downloadErrs,
 			AllDispatched: allDispatchedCnt == len(stats),
 			Scheduled:     scheduled,
+			NextSync:      nextSync,
+			Paused:        paused,
+			Priority:      priority,
 		}
 
 		respJSON := cmn.MustMarshal(resp)
 		return respJSON, http.StatusOK, nil
-	case http.MethodDelete:
+	case http.MethodDelete, http.MethodPut:
 		response := responses[0]
 		return response.body, response.statusCode, response.err
 	default:
@@ -187,7 +200,11 @@ func (p *proxyrunner) broadcastStartDownloadRequest(r *http.Request, id string)
 	query := r.URL.Query()
 	query.Set(cmn.URLParamID, id)
 
-	responses := p.broadcastDownloadRequest(http.MethodPost, r.URL.Path, body, query)
+	// Forward only the headers which are meant for the source.
+	payload := &cmn.DlBase{}
+	payload.InitWithHeader(r.Header)
+
+	responses := p.broadcastDownloadRequest(http.MethodPost, r.URL.Path, body, query, payload.AsHeader())
 
 	failures := make([]error, 0, 10)
 	for _, resp := range responses {
@@ -206,33 +223,57 @@ func (p *proxyrunner) broadcastStartDownloadRequest(r *http.Request, id string)
 // [METHOD] /v1/download
 func (p *proxyrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case http.MethodGet, http.MethodDelete:
+	case http.MethodGet, http.MethodDelete, http.MethodPut:
 		p.httpDownloadAdmin(w, r)
 	case http.MethodPost:
 		p.httpDownloadPost(w, r)
 	default:
-		s := fmt.Sprintf("invalid method %s for /download path; expected one of %s, %s, %s",
-			r.Method, http.MethodGet, http.MethodDelete, http.MethodPost)
+		s := fmt.Sprintf("invalid method %s for /download path; expected one of %s, %s, %s, %s",
+			r.Method, http.MethodGet, http.MethodDelete, http.MethodPut, http.MethodPost)
 		cmn.InvalidHandlerWithMsg(w, r, s)
 	}
 }
 
-// httpDownloadAdmin is meant for aborting, removing and getting status updates for downloads.
+// httpDownloadAdmin is meant for aborting, removing, pausing, resuming,
+// changing priority and getting status updates for downloads.
 // GET /v1/download?id=...
 // DELETE /v1/download/{abort, remove}?id=...
+// PUT /v1/download/{pause, resume, priority}?id=...[&priority=...]
 func (p *proxyrunner) httpDownloadAdmin(w http.ResponseWriter, r *http.Request) {
 	var (
 		payload = &cmn.DlAdminBody{}
 	)
 
 	payload.InitWithQuery(r.URL.Query())
-	if err := payload.Validate(r.Method == http.MethodDelete); err != nil {
+	if err := payload.Validate(r.Method != http.MethodGet); err != nil {
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
 
 	path := ""
-	if r.Method == http.MethodDelete {
+	switch r.Method {
+	case http.MethodPut:
+		items, err := cmn.MatchRESTItems(r.URL.Path, 1, false, cmn.Version, cmn.Download)
+		if err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, err.Error())
+			return
+		}
+
+		path = items[0]
+		switch path {
+		case cmn.Pause, cmn.Resume:
+		case cmn.Priority:
+			if err := cmn.ValidateDlPriority(payload.Priority); err != nil {
+				p.invalmsghdlr(w, r, err.Error())
+				return
+			}
+		default:
+			s := fmt.Sprintf("Invalid action for PUT request: %s (expected one of %s, %s or %s).",
+				items[0], cmn.Pause, cmn.Resume, cmn.Priority)
+			cmn.InvalidHandlerWithMsg(w, r, s)
+			return
+		}
+	case http.MethodDelete:
 		items, err := cmn.MatchRESTItems(r.URL.Path, 1, false, cmn.Version, cmn.Download)
 		if err != nil {
 			cmn.InvalidHandlerWithMsg(w, r, err.Error())
@@ -309,6 +350,10 @@ func (p *proxyrunner) validateStartDownloadRequest(w http.ResponseWriter, r *htt
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if err := payload.ValidateLimits(p.smapowner.get().CountTargets()); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
 	return true
 }
 
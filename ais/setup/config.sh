@@ -107,7 +107,15 @@ cat > $CONFFILE <<EOL
 			"read_buffer_size":	${HTTP_READ_BUFFER_SIZE:-0},
 			"rproxy_cache":		true,
 			"use_https":		${USE_HTTPS:-false},
-			"chunked_transfer":	${CHUNKED_TRANSFER:-true}
+			"chunked_transfer":	${CHUNKED_TRANSFER:-true},
+			"http2":		${HTTP2:-false}
+		},
+		"tls": {
+			"certificate":		"${INTRA_TLS_CERTIFICATE}",
+			"key":			"${INTRA_TLS_KEY}",
+			"ca":			"${INTRA_TLS_CA}",
+			"hmac_secret":		"${INTRA_HMAC_SECRET}",
+			"enabled":		${INTRA_TLS:-false}
 		}
 	},
 	"fshc": {
@@ -136,7 +144,9 @@ cat > $CONFFILE <<EOL
 		"timeout_factor": 3
 	},
 	"downloader": {
-		"timeout": "1h"
+		"timeout":           "1h",
+		"chunk_size":        "1GiB",
+		"chunk_concurrency": 8
 	},
 	"distributed_sort": {
 		"duplicated_records":    "ignore",
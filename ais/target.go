@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"sync"
@@ -21,10 +22,15 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/dsort"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/dsort/filetype"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/housekeep/hk"
 	"github.com/NVIDIA/aistore/mirror"
+	"github.com/NVIDIA/aistore/notif"
 	"github.com/NVIDIA/aistore/reb"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/stats/statsd"
@@ -82,6 +88,7 @@ type (
 		}
 		regstate       regstate // the state of being registered with the primary (can be en/disabled via API)
 		clusterStarted atomic.Bool
+		changes        changeFeed // recent changes of the watched buckets
 	}
 )
 
@@ -212,6 +219,12 @@ func (t *targetrunner) Run() error {
 	if err := fs.CSM.RegisterFileType(fs.WorkfileType, &fs.WorkfileContentResolver{}); err != nil {
 		cmn.ExitLogf("%v", err)
 	}
+	if err := fs.CSM.RegisterFileType(filetype.ShardIndexType, &filetype.ShardIndexFile{}); err != nil {
+		cmn.ExitLogf("%v", err)
+	}
+	if err := fs.CSM.RegisterFileType(downloader.PartialFileType, &downloader.PartialFile{}); err != nil {
+		cmn.ExitLogf("%v", err)
+	}
 
 	if err := fs.Mountpaths.CreateBucketDir(cmn.AIS); err != nil {
 		cmn.ExitLogf("%v", err)
@@ -233,6 +246,12 @@ func (t *targetrunner) Run() error {
 	// prefetch
 	t.prefetchQueue = make(chan filesWithDeadline, prefetchChanSize)
 
+	t.changes.init()
+	hk.Housekeeper.Register("lifecycle", t.lifecycleHK, lifecycleInterval)
+	if err := notif.Init(t.si.DaemonID, filepath.Join(config.Confdir, notif.SpoolDirName)); err != nil {
+		glog.Errorf("%s: %v", tname, err)
+	}
+
 	t.authn = &authManager{
 		tokens:        make(map[string]*authRec),
 		revokedTokens: make(map[string]bool),
@@ -320,6 +339,7 @@ func (t *targetrunner) registerStats() {
 func (t *targetrunner) Stop(err error) {
 	glog.Infof("Stopping %s, err: %v", t.Getname(), err)
 	sleep := xaction.Registry.AbortAll()
+	notif.Stop()
 	if t.publicServer.s != nil {
 		t.unregister() // ignore errors
 	}
@@ -391,6 +411,10 @@ func (t *targetrunner) httpbckget(w http.ResponseWriter, r *http.Request) {
 			t.getbucketnames(w, r, normalizedProvider)
 		}
 	default:
+		if r.URL.Query().Get(cmn.URLParamWhat) == cmn.GetWhatChanges {
+			t.bucketChanges(w, r, apiItems[0])
+			return
+		}
 		s := fmt.Sprintf("Invalid route /buckets/%s", apiItems[0])
 		t.invalmsghdlr(w, r, s)
 	}
@@ -421,6 +445,22 @@ func (t *targetrunner) verifyProxyRedirection(w http.ResponseWriter, r *http.Req
 	return true
 }
 
+// verifyTargetCaller checks that the request has been sent by another target
+// of the cluster (see: cmn.HeaderCallerID).
+func (t *targetrunner) verifyTargetCaller(w http.ResponseWriter, r *http.Request, action string) bool {
+	var (
+		tname    = t.si.Name()
+		callerID = r.Header.Get(cmn.HeaderCallerID)
+		smap     = t.smapowner.get()
+	)
+	if callerID == "" || smap.GetTarget(callerID) == nil {
+		s := fmt.Sprintf("%s: %s from unknown target [%s], %s", tname, action, callerID, smap.StringEx())
+		t.invalmsghdlr(w, r, s, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
 // GET /v1/objects/bucket[+"/"+objname]
 // Checks if the object exists locally (if not, downloads it) and sends it back
 // If the bucket is in the Cloud one and ValidateWarmGet is enabled there is an extra
@@ -447,6 +487,11 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	record := query.Get(cmn.URLParamRecord)
+	if record != "" && rangeLen != 0 {
+		t.invalmsghdlr(w, r, fmt.Sprintf("%q cannot be used together with %q and %q", cmn.URLParamRecord, cmn.URLParamOffset, cmn.URLParamLength))
+		return
+	}
 	lom := &cluster.LOM{T: t, Objname: objName}
 	if err = lom.Init(bucket, provider, config); err != nil {
 		if _, ok := err.(*cmn.ErrorCloudBucketDoesNotExist); ok {
@@ -471,6 +516,7 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 		ctx:     t.contextWithAuth(r.Header),
 		offset:  rangeOff,
 		length:  rangeLen,
+		record:  record,
 		isGFN:   isGFNRequest,
 		chunked: config.Net.HTTP.Chunked,
 	}
@@ -506,8 +552,9 @@ func (t *targetrunner) offsetAndLength(query url.Values) (offset, length int64,
 // PUT /v1/objects/bucket-name/object-name
 func (t *targetrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 	var (
-		query    = r.URL.Query()
-		appendTy = query.Get(cmn.URLParamAppendType)
+		query         = r.URL.Query()
+		appendTy      = query.Get(cmn.URLParamAppendType)
+		shardIndex, _ = cmn.ParseBool(query.Get(cmn.URLParamShardIndex))
 	)
 	apitems, err := t.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
 	if err != nil {
@@ -548,6 +595,10 @@ func (t *targetrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if shardIndex && !extract.SupportsShardIndex(objname) {
+		t.invalmsghdlr(w, r, fmt.Sprintf("%s: shard index is supported only for .tar and .zip archives", lom))
+		return
+	}
 	if lom.IsAIS() && lom.VerConf().Enabled {
 		lom.Load() // need to know the current version if versioning enabled
 	}
@@ -555,6 +606,13 @@ func (t *targetrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 	if appendTy == "" {
 		if err, errCode := t.doPut(r, lom, started); err != nil {
 			t.invalmsghdlr(w, r, err.Error(), errCode)
+		} else if shardIndex {
+			lom.Lock(false)
+			_, err = extract.CreateShardIndex(lom)
+			lom.Unlock(false)
+			if err != nil {
+				t.invalmsghdlr(w, r, err.Error())
+			}
 		}
 	} else {
 		if filePath, err, errCode := t.doAppend(r, lom, started); err != nil {
@@ -846,6 +904,10 @@ func (t *targetrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 		t.renameObject(w, r, &msg)
 	case cmn.ActPromote:
 		t.promoteFQN(w, r, &msg)
+	case cmn.ActListArchive:
+		t.listArchive(w, r)
+	case cmn.ActExtract:
+		t.extractArchive(w, r, &msg)
 	default:
 		s := fmt.Sprintf(fmtUnknownAct, msg)
 		t.invalmsghdlr(w, r, s)
@@ -1019,7 +1081,9 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 		}
 		hdr.Set(cmn.HeaderObjNumCopies, strconv.Itoa(lom.NumCopies()))
 		if cksum := lom.Cksum(); cksum != nil {
-			hdr.Set(cmn.HeaderObjCksumVal, cksum.Value())
+			ty, val := cksum.Get()
+			hdr.Set(cmn.HeaderObjCksumType, ty)
+			hdr.Set(cmn.HeaderObjCksumVal, val)
 		}
 		if lom.Bck().Props.EC.Enabled {
 			if md, err := ec.ObjectMetadata(lom.Bck(), objName); err == nil {
@@ -1163,6 +1227,13 @@ func (t *targetrunner) doPut(r *http.Request, lom *cluster.LOM, started time.Tim
 }
 
 func (t *targetrunner) putMirror(lom *cluster.LOM) {
+	if lom.Bprops().Lifecycle.DefersMirror() {
+		return // will be mirrored once aged (see housekeep/lifecycle)
+	}
+	t.mirrorObject(lom)
+}
+
+func (t *targetrunner) mirrorObject(lom *cluster.LOM) {
 	const retries = 2
 	var (
 		err      error
@@ -1215,6 +1286,11 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 		}
 	}
 	if delFromAIS {
+		if extract.SupportsShardIndex(lom.Objname) {
+			if err := extract.RemoveShardIndex(lom); err != nil {
+				glog.Warningf("%s: failed to remove shard index, err: %v", lom, err)
+			}
+		}
 		errRet = lom.Remove()
 		if errRet != nil {
 			if !os.IsNotExist(errRet) {
@@ -1235,6 +1311,10 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 	if cloudErr != nil {
 		return fmt.Errorf("%s: failed to delete from cloud: %v", lom.StringEx(), cloudErr)
 	}
+	if errRet == nil && !evict && (delFromAIS || delFromCloud) {
+		t.recordChange(lom, cmn.ChangeDeleted)
+		notif.Notify(lom, cmn.NotifDelete)
+	}
 	return errRet
 }
 
@@ -1277,6 +1357,9 @@ func (t *targetrunner) renameObject(w http.ResponseWriter, r *http.Request, msg
 		lom.Lock(true)
 		if err = lom.Remove(); err != nil {
 			t.invalmsghdlr(w, r, err.Error())
+		} else {
+			t.recordChange(lom, cmn.ChangeRenamed, msg.Name)
+			notif.Notify(lom, cmn.NotifRename, msg.Name)
 		}
 		lom.Unlock(true)
 	}
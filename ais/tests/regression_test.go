@@ -446,7 +446,7 @@ func TestRenameObjects(t *testing.T) {
 	for objName := range objsPutCh {
 		newObjName := path.Join(renameStr, objName) + ".renamed" // objname fqn
 		newBaseNames = append(newBaseNames, newObjName)
-		if err := api.RenameObject(baseParams, bucket, objName, newObjName); err != nil {
+		if err := api.RenameObject(baseParams, bucket, objName, newObjName, cmn.AIS); err != nil {
 			t.Fatalf("Failed to rename object from %s => %s, err: %v", objName, newObjName, err)
 		}
 		i++
@@ -0,0 +1,250 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+//////////////////////////////////////////
+// LIST and EXTRACT archive => objects  //
+//////////////////////////////////////////
+
+// openArchive loads the archive (cold GET if needed) and returns it locked for
+// reading - the caller must unlock the lom and close the file.
+func (t *targetrunner) openArchive(ctx context.Context, w http.ResponseWriter, r *http.Request) (lom *cluster.LOM, file *os.File, ok bool) {
+	apitems, err := t.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	bucket, objname := apitems[0], apitems[1]
+	provider := r.URL.Query().Get(cmn.URLParamProvider)
+
+	lom = &cluster.LOM{T: t, Objname: objname}
+	if err = lom.Init(bucket, provider); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if !extract.SupportsArchive(lom.Objname) {
+		t.invalmsghdlr(w, r, fmt.Sprintf("%s: not an archive, expected one of: .tar, .tgz, .tar.gz, .zip", lom))
+		return
+	}
+
+	lom.Lock(false)
+	if err = lom.Load(); err != nil && !cmn.IsErrObjNought(err) {
+		lom.Unlock(false)
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err != nil { // does not exist
+		lom.Unlock(false)
+		if lom.IsAIS() {
+			t.invalmsghdlr(w, r, fmt.Sprintf("%s does not exist", lom), http.StatusNotFound)
+			return
+		}
+		// `GetCold` returns with the object locked for reading
+		if err, errCode := t.GetCold(ctx, lom, false); err != nil {
+			t.invalmsghdlr(w, r, err.Error(), errCode)
+			return
+		}
+	}
+	if file, err = os.Open(lom.FQN); err != nil {
+		lom.Unlock(false)
+		t.fshc(err, lom.FQN)
+		t.invalmsghdlr(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	return lom, file, true
+}
+
+// POST { action: listarch } /v1/objects/bucket-name/object-name
+func (t *targetrunner) listArchive(w http.ResponseWriter, r *http.Request) {
+	lom, file, ok := t.openArchive(t.contextWithAuth(r.Header), w, r)
+	if !ok {
+		return
+	}
+	defer func() {
+		file.Close()
+		lom.Unlock(false)
+	}()
+
+	entries := make([]cmn.ArchiveEntry, 0, 16)
+	err := extract.WalkArchive(lom.ParsedFQN, io.NewSectionReader(file, 0, lom.Size()), func(name string, r cmn.ReadSizer) error {
+		entries = append(entries, cmn.ArchiveEntry{Name: name, Size: r.Size()})
+		return nil
+	})
+	if err != nil {
+		t.invalmsghdlr(w, r, fmt.Sprintf("%s: failed to list archive, err: %v", lom, err))
+		return
+	}
+	t.writeJSON(w, r, cmn.MustMarshal(entries), "listarch")
+}
+
+// POST { action: extract } /v1/objects/bucket-name/object-name
+//
+// Each selected member of the archive becomes a separate object named
+// prefix + member name; the objects are PUT to their respective targets.
+func (t *targetrunner) extractArchive(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	var (
+		params  = cmn.ActValExtract{}
+		members cmn.StringSet
+		ctx     = t.contextWithAuth(r.Header)
+	)
+	if err := cmn.TryUnmarshal(msg.Value, &params); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if len(params.Members) > 0 {
+		members = make(cmn.StringSet, len(params.Members))
+		for _, member := range params.Members {
+			members.Add(member)
+		}
+	}
+
+	lom, file, ok := t.openArchive(ctx, w, r)
+	if !ok {
+		return
+	}
+	defer func() {
+		file.Close()
+		lom.Unlock(false)
+	}()
+
+	bckTo := lom.Bck()
+	if params.Bucket != "" {
+		bckTo = &cluster.Bck{Name: params.Bucket, Provider: params.Provider}
+		if err := bckTo.Init(t.bmdowner); err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+	}
+	if err := bckTo.AllowPUT(); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+
+	// objName returns the name of the object the member is extracted to or
+	// empty string if the member is not selected (or is a directory)
+	objName := func(name string) (string, error) {
+		if members != nil && !members.Contains(name) {
+			return "", nil
+		}
+		// reject members (and prefixes) which would escape the destination
+		cleaned, err := extract.CleanMemberName(name)
+		if err != nil || cleaned == "" {
+			return "", err
+		}
+		objname, err := extract.CleanMemberName(params.Prefix + cleaned)
+		if err != nil {
+			return "", err
+		}
+		if bckTo.Equal(lom.Bck()) && objname == lom.Objname {
+			return "", fmt.Errorf("member %q would overwrite the archive %s", name, lom)
+		}
+		return objname, nil
+	}
+
+	// validate all selected members first so that nothing is PUT when
+	// any of them is invalid
+	err := extract.WalkArchive(lom.ParsedFQN, io.NewSectionReader(file, 0, lom.Size()), func(name string, _ cmn.ReadSizer) error {
+		_, err := objName(name)
+		return err
+	})
+	if err != nil {
+		t.invalmsghdlr(w, r, fmt.Sprintf("%s: failed to extract archive, err: %v", lom, err))
+		return
+	}
+
+	var (
+		smap     = t.smapowner.get()
+		objNames = make([]string, 0, len(params.Members))
+	)
+	err = extract.WalkArchive(lom.ParsedFQN, io.NewSectionReader(file, 0, lom.Size()), func(name string, r cmn.ReadSizer) error {
+		objname, err := objName(name)
+		if err != nil || objname == "" {
+			return err
+		}
+		if err := t.putArchiveMember(ctx, smap, bckTo, objname, r); err != nil {
+			return err
+		}
+		objNames = append(objNames, objname)
+		return nil
+	})
+	if err != nil {
+		t.invalmsghdlr(w, r, fmt.Sprintf("%s: failed to extract archive, err: %v", lom, err))
+		return
+	}
+	if members != nil && len(objNames) != len(members) {
+		glog.Warningf("%s: extracted %d out of %d requested members", lom, len(objNames), len(members))
+	}
+	t.writeJSON(w, r, cmn.MustMarshal(objNames), "extract")
+}
+
+func (t *targetrunner) putArchiveMember(ctx context.Context, smap *smapX, bckTo *cluster.Bck, objname string, r cmn.ReadSizer) error {
+	dst := &cluster.LOM{T: t, Objname: objname}
+	if err := dst.Init(bckTo.Name, bckTo.Provider); err != nil {
+		return err
+	}
+	si, err := cluster.HrwTarget(dst.Uname(), &smap.Smap)
+	if err != nil {
+		return err
+	}
+
+	// local op
+	if si.DaemonID == t.si.DaemonID {
+		poi := &putObjInfo{
+			started: time.Now(),
+			t:       t,
+			lom:     dst,
+			r:       ioutil.NopCloser(r),
+			workFQN: fs.CSM.GenContentParsedFQN(dst.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+			ctx:     ctx,
+		}
+		err, _ := poi.putObject()
+		return err
+	}
+
+	// PUT object into different target
+	query := url.Values{}
+	query.Add(cmn.URLParamProvider, bckTo.Provider)
+	query.Add(cmn.URLParamProxyID, smap.ProxySI.DaemonID)
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodPut,
+		Base:   si.URL(cmn.NetworkIntraData),
+		Path:   cmn.URLPath(cmn.Version, cmn.Objects, bckTo.Name, objname),
+		Query:  query,
+		BodyR:  r,
+	}
+	req, _, cancel, err := reqArgs.ReqWithTimeout(cmn.GCO.Get().Timeout.SendFile)
+	if err != nil {
+		return fmt.Errorf("unexpected failure to create request, err: %v", err)
+	}
+	defer cancel()
+	req.ContentLength = r.Size()
+
+	resp, err := t.httpclientGetPut.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to PUT to %s, err: %v", reqArgs.URL(), err)
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("failed to PUT to %s, status code: %d", reqArgs.URL(), resp.StatusCode)
+	}
+	return nil
+}
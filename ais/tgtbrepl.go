@@ -87,6 +87,9 @@ func (ri *replicInfo) copyObject(lom *cluster.LOM, objnameTo string) (copied boo
 	if err == nil {
 		copied = true
 		dst.ReCache()
+		if lom.Uname() != dst.Uname() {
+			ri.t.recordChange(dst, cmn.ChangeCreated)
+		}
 
 		if ri.finalize {
 			//
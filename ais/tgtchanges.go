@@ -0,0 +1,164 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/housekeep/hk"
+)
+
+// Theory of operation
+//
+// Each target keeps in memory the recent changes of the namespace (objects
+// created, deleted and renamed) of each bucket that is being watched, that is,
+// whose change feed has been queried at least once during the last
+// changeFeedIdle. The changes of the buckets that are not watched are not
+// recorded at all.
+//
+// Every change gets a sequence number. The client's position in the feed of
+// the target is (epoch, seq) where epoch is the time the target started - the
+// feed does not survive restarts. When the position can no longer be served
+// (target restarted, the feed has been dropped or overflowed) the target
+// returns Reset, and the client must relist the bucket.
+//
+// The proxy aggregates the feeds of all targets (see prxchanges.go).
+
+const (
+	changeFeedCap     = 64 * 1024        // max number of changes kept per bucket
+	changeFeedMaxResp = 16 * 1024        // max number of changes returned by a single query
+	changeFeedIdle    = 10 * time.Minute // feed that has not been queried for so long is dropped
+)
+
+type (
+	changeFeed struct {
+		mtx   sync.Mutex
+		epoch int64
+		feeds map[string]*bckChangeFeed // bucket uname => feed
+	}
+
+	bckChangeFeed struct {
+		changes []cmn.BucketChange // changes[len-1] has sequence number `seq`
+		seq     int64
+		polled  time.Time
+	}
+
+	// target's response to the change feed query
+	tgtChanges struct {
+		Cursor  cmn.ChangeCursor   `json:"cursor"`
+		Changes []cmn.BucketChange `json:"changes"`
+		Reset   bool               `json:"reset"`
+	}
+)
+
+func (cf *changeFeed) init() {
+	cf.epoch = time.Now().UnixNano()
+	cf.feeds = make(map[string]*bckChangeFeed)
+	hk.Housekeeper.Register("change-feed", cf.housekeep, changeFeedIdle)
+}
+
+// record appends the change to the feed of the bucket, if watched.
+func (cf *changeFeed) record(bck *cluster.Bck, change cmn.BucketChange) {
+	if change.Time == 0 {
+		change.Time = time.Now().UnixNano()
+	}
+	cf.mtx.Lock()
+	if feed, ok := cf.feeds[bck.MakeUname("")]; ok {
+		if len(feed.changes) == changeFeedCap {
+			// drop the older half (amortized O(1) per change)
+			feed.changes = append(feed.changes[:0:0], feed.changes[changeFeedCap/2:]...)
+		}
+		feed.changes = append(feed.changes, change)
+		feed.seq++
+	}
+	cf.mtx.Unlock()
+}
+
+// get returns the changes that follow the given cursor; zero cursor is the
+// start of watching (no changes returned, only the current position).
+func (cf *changeFeed) get(bck *cluster.Bck, cursor cmn.ChangeCursor) (resp tgtChanges) {
+	uname := bck.MakeUname("")
+	cf.mtx.Lock()
+	defer cf.mtx.Unlock()
+
+	feed, ok := cf.feeds[uname]
+	if !ok {
+		feed = &bckChangeFeed{}
+		cf.feeds[uname] = feed
+	}
+	feed.polled = time.Now()
+	resp.Cursor = cmn.ChangeCursor{Epoch: cf.epoch, Seq: feed.seq}
+	if cursor.Epoch == 0 {
+		return
+	}
+	first := feed.seq - int64(len(feed.changes)) + 1
+	if !ok || cursor.Epoch != cf.epoch || cursor.Seq > feed.seq || cursor.Seq+1 < first {
+		resp.Reset = true
+		return
+	}
+	changes := feed.changes[cursor.Seq+1-first:]
+	if len(changes) > changeFeedMaxResp {
+		changes = changes[:changeFeedMaxResp]
+	}
+	resp.Changes = append([]cmn.BucketChange(nil), changes...)
+	resp.Cursor.Seq = cursor.Seq + int64(len(changes))
+	return
+}
+
+func (cf *changeFeed) housekeep() time.Duration {
+	cf.mtx.Lock()
+	for uname, feed := range cf.feeds {
+		if time.Since(feed.polled) > changeFeedIdle {
+			delete(cf.feeds, uname)
+		}
+	}
+	cf.mtx.Unlock()
+	return changeFeedIdle
+}
+
+// recordChange records the change of the object (see changeFeed).
+func (t *targetrunner) recordChange(lom *cluster.LOM, ty string, newName ...string) {
+	change := cmn.BucketChange{Type: ty, Name: lom.Objname}
+	switch ty {
+	case cmn.ChangeCreated:
+		change.Size = lom.Size()
+		change.Version = lom.Version()
+	case cmn.ChangeRenamed:
+		change.NewName = newName[0]
+	}
+	t.changes.record(lom.Bck(), change)
+}
+
+// GET /v1/buckets/bucket-name?what=changes
+func (t *targetrunner) bucketChanges(w http.ResponseWriter, r *http.Request, bucket string) {
+	var (
+		query    = r.URL.Query()
+		provider = query.Get(cmn.URLParamProvider)
+		bck      = &cluster.Bck{Name: bucket, Provider: provider}
+	)
+	if err := bck.Init(t.bmdowner); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	token, err := cmn.DecodeChangeToken(query.Get(cmn.URLParamChangeToken))
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	cursor, ok := token[t.si.DaemonID]
+	resp := t.changes.get(bck, cursor)
+	if !ok && len(token) > 0 {
+		// The target has joined after the token was issued: its changes may
+		// have not been recorded.
+		resp.Reset = true
+	}
+	body := cmn.MustMarshal(resp)
+	t.writeJSON(w, r, body, fmt.Sprintf("changes(%s)", bck))
+}
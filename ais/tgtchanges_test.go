@@ -0,0 +1,77 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Change feed", func() {
+	var (
+		cf  *changeFeed
+		bck = &cluster.Bck{Name: "bucket", Provider: cmn.AIS}
+	)
+
+	created := func(name string) cmn.BucketChange {
+		return cmn.BucketChange{Type: cmn.ChangeCreated, Name: name}
+	}
+
+	BeforeEach(func() {
+		cf = &changeFeed{}
+		cf.init()
+	})
+
+	It("should record changes only of watched bucket", func() {
+		cf.record(bck, created("a"))
+		resp := cf.get(bck, cmn.ChangeCursor{})
+		Expect(resp.Changes).To(BeEmpty())
+		Expect(resp.Reset).To(BeFalse())
+
+		cf.record(bck, created("b"))
+		cf.record(bck, cmn.BucketChange{Type: cmn.ChangeRenamed, Name: "b", NewName: "c"})
+		resp = cf.get(bck, resp.Cursor)
+		Expect(resp.Reset).To(BeFalse())
+		Expect(resp.Changes).To(HaveLen(2))
+		Expect(resp.Changes[0].Name).To(Equal("b"))
+		Expect(resp.Changes[1].NewName).To(Equal("c"))
+
+		resp = cf.get(bck, resp.Cursor)
+		Expect(resp.Changes).To(BeEmpty())
+	})
+
+	It("should reset when the position is lost", func() {
+		cursor := cf.get(bck, cmn.ChangeCursor{}).Cursor
+
+		// target restarted
+		resp := cf.get(bck, cmn.ChangeCursor{Epoch: cursor.Epoch - 1, Seq: cursor.Seq})
+		Expect(resp.Reset).To(BeTrue())
+
+		// feed overflowed
+		for i := 0; i < changeFeedCap+1; i++ {
+			cf.record(bck, created(fmt.Sprintf("obj%d", i)))
+		}
+		resp = cf.get(bck, cursor)
+		Expect(resp.Reset).To(BeTrue())
+		Expect(resp.Changes).To(BeEmpty())
+		Expect(resp.Cursor.Seq).To(BeEquivalentTo(changeFeedCap + 1))
+	})
+
+	It("should limit the number of returned changes", func() {
+		cursor := cf.get(bck, cmn.ChangeCursor{}).Cursor
+		for i := 0; i < changeFeedMaxResp+10; i++ {
+			cf.record(bck, created(fmt.Sprintf("obj%d", i)))
+		}
+		resp := cf.get(bck, cursor)
+		Expect(resp.Changes).To(HaveLen(changeFeedMaxResp))
+		resp = cf.get(bck, resp.Cursor)
+		Expect(resp.Changes).To(HaveLen(10))
+		Expect(resp.Changes[9].Name).To(Equal(fmt.Sprintf("obj%d", changeFeedMaxResp+9)))
+	})
+})
@@ -416,6 +416,8 @@ func (t *targetrunner) xactsStartRequest(kind, bucket string) error {
 		switch kind {
 		case cmn.ActLRU:
 			go t.RunLRU()
+		case cmn.ActLifecycle:
+			go t.RunLifecycle()
 		case cmn.ActLocalReb:
 			go t.rebManager.RunLocalReb(false /*skipGlobMisplaced*/)
 		case cmn.ActPrefetch:
@@ -1163,6 +1165,7 @@ func (t *targetrunner) pollClusterStarted(timeout time.Duration) {
 		}
 	}
 	t.clusterStarted.Store(true)
+	t.restoreSyncDownloads()
 }
 
 func (t *targetrunner) httpTokenDelete(w http.ResponseWriter, r *http.Request) {
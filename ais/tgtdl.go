@@ -22,7 +22,15 @@ import (
 // NOTE: This request is internal so we can have asserts there.
 // [METHOD] /v1/download
 func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
-	if !t.verifyProxyRedirection(w, r, cmn.Download) {
+	// NOTE: manifests and objects of list jobs are sent directly by other targets
+	// (see: downloader/manifest.go and downloader/list.go)
+	fromTarget := r.Method == http.MethodPut && (r.URL.Path == cmn.URLPath(cmn.Version, cmn.Download, cmn.Manifest) ||
+		r.URL.Path == cmn.URLPath(cmn.Version, cmn.Download, cmn.Objects))
+	if fromTarget {
+		if !t.verifyTargetCaller(w, r, cmn.Download) {
+			return
+		}
+	} else if !t.verifyProxyRedirection(w, r, cmn.Download) {
 		return
 	}
 	var (
@@ -42,7 +50,12 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		id := r.URL.Query().Get(cmn.URLParamID)
 		cmn.Assert(id != "")
 
-		dlJob, err := t.parseStartDownloadRequest(r, id)
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		dlJob, err := t.parseStartDownloadRequest(r, body, id)
 		if err != nil {
 			t.invalmsghdlr(w, r, err.Error())
 			return
@@ -52,7 +65,12 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 			glog.Infof("Downloading: %s", dlJob.ID())
 		}
 
-		response, respErr, statusCode = downloaderXact.Download(dlJob)
+		// headers of the request other than those of the sources are not
+		// persisted with the sync job
+		base := &cmn.DlBase{}
+		base.InitWithHeader(r.Header)
+		syncReq := &downloader.SyncRequest{Query: r.URL.Query(), Header: base.AsHeader(), Body: body}
+		response, respErr, statusCode = downloaderXact.Download(dlJob, syncReq)
 
 	case http.MethodGet:
 		payload := &cmn.DlAdminBody{}
@@ -63,7 +81,7 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 
 		if payload.ID != "" {
 			if glog.FastV(4, glog.SmoduleAIS) {
-				glog.Infof("Getting status of download: %s", payload)
+				glog.Infof("Getting status of download: %s", payload.ID)
 			}
 			response, respErr, statusCode = downloaderXact.JobStatus(payload.ID)
 		} else {
@@ -80,6 +98,64 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 			response, respErr, statusCode = downloaderXact.ListJobs(regex)
 		}
 
+	case http.MethodPut:
+		if !fromTarget {
+			payload := &cmn.DlAdminBody{}
+			if err = cmn.ReadJSON(w, r, payload); err != nil {
+				return
+			}
+			cmn.AssertNoErr(payload.Validate(true))
+
+			items, err := cmn.MatchRESTItems(r.URL.Path, 1, false, cmn.Version, cmn.Download)
+			cmn.AssertNoErr(err)
+
+			switch items[0] {
+			case cmn.Pause:
+				if glog.FastV(4, glog.SmoduleAIS) {
+					glog.Infof("Pausing download: %s", payload.ID)
+				}
+				response, respErr, statusCode = downloaderXact.PauseJob(payload.ID)
+			case cmn.Resume:
+				if glog.FastV(4, glog.SmoduleAIS) {
+					glog.Infof("Resuming download: %s", payload.ID)
+				}
+				response, respErr, statusCode = downloaderXact.ResumeJob(payload.ID)
+			case cmn.Priority:
+				if glog.FastV(4, glog.SmoduleAIS) {
+					glog.Infof("Changing priority of download: %s to %d", payload.ID, payload.Priority)
+				}
+				response, respErr, statusCode = downloaderXact.SetJobPriority(payload.ID, payload.Priority)
+			default:
+				cmn.AssertMsg(false, fmt.Sprintf("Invalid action for PUT request: %s (expected one of %s, %s or %s).", items[0], cmn.Pause, cmn.Resume, cmn.Priority))
+				return
+			}
+			break
+		}
+
+		id := r.URL.Query().Get(cmn.URLParamID)
+		cmn.Assert(id != "")
+
+		if r.URL.Path == cmn.URLPath(cmn.Version, cmn.Download, cmn.Objects) {
+			lo := &downloader.ListObjs{}
+			if err := cmn.ReadJSON(w, r, lo); err != nil {
+				return
+			}
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("Received %d objects of download %s from %s", len(lo.Objs), id, r.Header.Get(cmn.HeaderCallerID))
+			}
+			respErr, statusCode = downloaderXact.ReceiveListObjs(id, r.Header.Get(cmn.HeaderCallerID), lo)
+			break
+		}
+
+		var shards []*cmn.ManifestShard
+		if err := cmn.ReadJSON(w, r, &shards); err != nil {
+			return
+		}
+		if glog.FastV(4, glog.SmoduleAIS) {
+			glog.Infof("Received manifest of download %s from %s", id, r.Header.Get(cmn.HeaderCallerID))
+		}
+		respErr, statusCode = downloaderXact.ReceiveManifest(id, r.Header.Get(cmn.HeaderCallerID), shards)
+
 	case http.MethodDelete:
 		payload := &cmn.DlAdminBody{}
 		if err = cmn.ReadJSON(w, r, payload); err != nil {
@@ -93,12 +169,12 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		switch items[0] {
 		case cmn.Abort:
 			if glog.FastV(4, glog.SmoduleAIS) {
-				glog.Infof("Aborting download: %s", payload)
+				glog.Infof("Aborting download: %s", payload.ID)
 			}
 			response, respErr, statusCode = downloaderXact.AbortJob(payload.ID)
 		case cmn.Remove:
 			if glog.FastV(4, glog.SmoduleAIS) {
-				glog.Infof("Removing download: %s", payload)
+				glog.Infof("Removing download: %s", payload.ID)
 			}
 			response, respErr, statusCode = downloaderXact.RemoveJob(payload.ID)
 		default:
@@ -107,7 +183,7 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 	default:
-		cmn.AssertMsg(false, fmt.Sprintf("Invalid http method %s; expected one of %s, %s, %s", r.Method, http.MethodGet, http.MethodPost, http.MethodDelete))
+		cmn.AssertMsg(false, fmt.Sprintf("Invalid http method %s; expected one of %s, %s, %s, %s", r.Method, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete))
 		return
 	}
 
@@ -127,7 +203,7 @@ func (t *targetrunner) downloadHandler(w http.ResponseWriter, r *http.Request) {
 
 // parseStartDownloadRequest translates external http request into internal representation: DlJob interface
 // based on different type of request DlJob might of different type which implements the interface
-func (t *targetrunner) parseStartDownloadRequest(r *http.Request, id string) (downloader.DlJob, error) {
+func (t *targetrunner) parseStartDownloadRequest(r *http.Request, b []byte, id string) (downloader.DlJob, error) {
 	var (
 		// link -> objname
 		objects cmn.SimpleKVs
@@ -137,23 +213,30 @@ func (t *targetrunner) parseStartDownloadRequest(r *http.Request, id string) (do
 		singlePayload  = &cmn.DlSingleBody{}
 		rangePayload   = &cmn.DlRangeBody{}
 		multiPayload   = &cmn.DlMultiBody{}
+		listPayload    = &cmn.DlListBody{}
 		cloudPayload   = &cmn.DlCloudBody{}
 		objectsPayload interface{}
 
 		description string
+		cksumType   string // expected checksum of the single object, see: cmn.DlObj
+		cksumValue  string
 		bckIsAIS    bool
 		fromCloud   bool
 	)
 
 	payload.InitWithQuery(query)
+	payload.InitWithHeader(r.Header)
 
 	singlePayload.InitWithQuery(query)
 	rangePayload.InitWithQuery(query)
 	multiPayload.InitWithQuery(query)
+	listPayload.InitWithQuery(query)
 	cloudPayload.InitWithQuery(query)
-
-	b, err := ioutil.ReadAll(r.Body)
-	if err != nil {
+	for _, base := range []*cmn.DlBase{&singlePayload.DlBase, &rangePayload.DlBase, &multiPayload.DlBase, &listPayload.DlBase, &cloudPayload.DlBase} {
+		base.InitWithHeader(r.Header)
+	}
+	// Common part of all the formats - report its errors as they are.
+	if err := payload.Validate(); err != nil {
 		return nil, err
 	}
 
@@ -162,6 +245,7 @@ func (t *targetrunner) parseStartDownloadRequest(r *http.Request, id string) (do
 			return nil, err
 		}
 		description = singlePayload.Describe()
+		cksumType, cksumValue = singlePayload.DlObj.CksumType, singlePayload.CksumValue
 	} else if err := rangePayload.Validate(); err == nil {
 		// FIXME: rangePayload still evaluates all of the objects on this line
 		// it means that if range is 0-3mln, we will create 3mln objects right away
@@ -173,6 +257,22 @@ func (t *targetrunner) parseStartDownloadRequest(r *http.Request, id string) (do
 			return nil, err
 		}
 		description = rangePayload.Describe()
+	} else if err := listPayload.Validate(); err == nil {
+		bck := &cluster.Bck{Name: listPayload.Bucket, Provider: listPayload.Provider}
+		if err := bck.Init(t.bmdowner); err != nil {
+			if _, ok := err.(*cmn.ErrorCloudBucketDoesNotExist); !ok { // is ais
+				return nil, err
+			}
+		}
+		listBck := &cluster.Bck{Name: listPayload.ListBucket, Provider: listPayload.ListProvider}
+		if err := listBck.Init(t.bmdowner); err != nil {
+			return nil, err
+		}
+		if listPayload.Description == "" {
+			listPayload.Description = listPayload.Describe()
+		}
+		baseJob := downloader.NewBaseDlJob(id, bck, &listPayload.DlBase)
+		return downloader.NewListDlJob(t, baseJob, listBck, listPayload.ListObjname, listPayload.ListFormat), nil
 	} else if err := multiPayload.Validate(b); err == nil {
 		if err := jsoniter.Unmarshal(b, &objectsPayload); err != nil {
 			return nil, err
@@ -187,10 +287,10 @@ func (t *targetrunner) parseStartDownloadRequest(r *http.Request, id string) (do
 		// TODO -- FIXME: bckIsAIS must be removed; init bck (below) and check conditions&errors
 		//
 		bck := &cluster.Bck{Name: cloudPayload.Bucket, Provider: cloudPayload.Provider}
-		baseJob := downloader.NewBaseDlJob(id, bck, cloudPayload.Timeout, payload.Description)
+		baseJob := downloader.NewBaseDlJob(id, bck, &cloudPayload.DlBase)
 		return downloader.NewCloudBucketDlJob(t.contextWithAuth(r.Header), t, baseJob, cloudPayload.Prefix, cloudPayload.Suffix)
 	} else {
-		return nil, errors.New("input does not match any of the supported formats (single, range, multi, cloud)")
+		return nil, errors.New("input does not match any of the supported formats (single, range, list, multi, cloud)")
 	}
 
 	if payload.Description == "" {
@@ -198,7 +298,7 @@ func (t *targetrunner) parseStartDownloadRequest(r *http.Request, id string) (do
 	}
 
 	bck := &cluster.Bck{Name: payload.Bucket, Provider: payload.Provider}
-	if err = bck.Init(t.bmdowner); err != nil {
+	if err := bck.Init(t.bmdowner); err != nil {
 		if _, ok := err.(*cmn.ErrorCloudBucketDoesNotExist); !ok { // is ais
 			return nil, err
 		}
@@ -207,6 +307,46 @@ func (t *targetrunner) parseStartDownloadRequest(r *http.Request, id string) (do
 	if err != nil {
 		return nil, err
 	}
+	for i := range input.Objs {
+		input.Objs[i].CksumType, input.Objs[i].CksumValue = cksumType, cksumValue
+	}
+	targetCnt, err := downloader.CountTargets(t, objects, bck)
+	if err != nil {
+		return nil, err
+	}
+
+	return downloader.NewSliceDlJob(downloader.NewBaseDlJob(input.ID, bck, payload), input.Objs, targetCnt), nil
+}
 
-	return downloader.NewSliceDlJob(input.ID, input.Objs, bck, payload.Timeout, payload.Description), nil
+// restoreSyncDownloads restores the sync download jobs which have been
+// persisted before the target restarted (see: downloader.SyncJob).
+func (t *targetrunner) restoreSyncDownloads() {
+	jobs, err := downloader.LoadSyncJobs()
+	if err != nil {
+		glog.Errorf("%s: failed to load sync download jobs, err: %v", t.si.Name(), err)
+		return
+	}
+	if len(jobs) == 0 {
+		return
+	}
+	downloaderXact, err := xaction.Registry.RenewDownloader(t, t.statsif)
+	if err != nil {
+		glog.Errorf("%s: failed to restore sync download jobs, err: %v", t.si.Name(), err)
+		return
+	}
+	for _, sj := range jobs {
+		r, err := http.NewRequest(http.MethodPost, cmn.URLPath(cmn.Version, cmn.Download), nil)
+		cmn.AssertNoErr(err)
+		r.URL.RawQuery = sj.Request.Query.Encode()
+		if sj.Request.Header != nil {
+			r.Header = sj.Request.Header
+		}
+		dlJob, err := t.parseStartDownloadRequest(r, sj.Request.Body, sj.ID)
+		if err != nil {
+			glog.Errorf("%s: failed to restore sync download job %s, err: %v", t.si.Name(), sj.ID, err)
+			continue
+		}
+		downloaderXact.RestoreSyncJob(dlJob, sj)
+		glog.Infof("%s: restored sync download job %s", t.si.Name(), sj.ID)
+	}
 }
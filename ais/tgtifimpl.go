@@ -191,6 +191,17 @@ func (t *targetrunner) CopyObject(lom *cluster.LOM, bckTo *cluster.Bck, buf []by
 
 // FIXME: recomputes checksum if called with a bad one (optimize)
 func (t *targetrunner) GetCold(ct context.Context, lom *cluster.LOM, prefetch bool) (err error, errCode int) {
+	return t.getCold(ct, lom, prefetch, nil)
+}
+
+// GetColdVerified is a prefetch that stores the object only if `verify`
+// accepts the content downloaded into the work file - otherwise the object
+// (including its previous version, if any) stays intact.
+func (t *targetrunner) GetColdVerified(ct context.Context, lom *cluster.LOM, verify func(workFQN string) error) (err error, errCode int) {
+	return t.getCold(ct, lom, true /*prefetch*/, verify)
+}
+
+func (t *targetrunner) getCold(ct context.Context, lom *cluster.LOM, prefetch bool, verify func(string) error) (err error, errCode int) {
 	if prefetch {
 		if !lom.TryLock(true) {
 			glog.Infof("prefetch: cold GET race: %s - skipping", lom)
@@ -217,11 +228,17 @@ func (t *targetrunner) GetCold(ct context.Context, lom *cluster.LOM, prefetch bo
 			}
 		}
 	}()
+	if verify != nil {
+		if err = verify(workFQN); err != nil {
+			return
+		}
+	}
 	if err = cmn.Rename(workFQN, lom.FQN); err != nil {
 		err = fmt.Errorf("unexpected failure to rename %s => %s, err: %v", workFQN, lom.FQN, err)
 		t.fshc(err, lom.FQN)
 		return
 	}
+	lom.SetWtimeUnix(time.Now().UnixNano())
 	if err = lom.Persist(); err != nil {
 		return
 	}
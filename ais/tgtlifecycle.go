@@ -0,0 +1,63 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/housekeep/lifecycle"
+	"github.com/NVIDIA/aistore/xaction"
+)
+
+const lifecycleInterval = time.Hour // how often the lifecycle rules of the buckets are applied
+
+// housekeeping callback: runs the lifecycle x-action if any bucket has the rules enabled
+func (t *targetrunner) lifecycleHK() time.Duration {
+	bmd := t.bmdowner.Get()
+	for _, bmap := range []map[string]*cmn.BucketProps{bmd.LBmap, bmd.CBmap} {
+		for _, props := range bmap {
+			if props.Lifecycle.Enabled {
+				go t.RunLifecycle()
+				return lifecycleInterval
+			}
+		}
+	}
+	return lifecycleInterval
+}
+
+// RunLifecycle applies the lifecycle rules of the buckets to the objects
+// stored by this target (see housekeep/lifecycle).
+func (t *targetrunner) RunLifecycle() {
+	if t.RebalanceInfo().IsRebalancing {
+		glog.Infoln("Warning: rebalancing (local or global) is in progress, skipping lifecycle run")
+		return
+	}
+	xlc := xaction.Registry.RenewLifecycle()
+	if xlc == nil {
+		return
+	}
+	ini := lifecycle.InitLifecycle{
+		Xact: xlc,
+		T:    t,
+		Delete: func(lom *cluster.LOM) error {
+			return t.objDelete(context.Background(), lom, false /*evict*/)
+		},
+		Evict: func(lom *cluster.LOM) error {
+			return t.objDelete(context.Background(), lom, true /*evict*/)
+		},
+		Mirror: t.mirrorObject,
+		Encode: func(lom *cluster.LOM) error {
+			return ec.ECM.EncodeObject(lom)
+		},
+	}
+	lifecycle.Run(&ini) // blocking
+
+	xlc.EndTime(time.Now())
+}
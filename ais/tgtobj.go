@@ -21,9 +21,11 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/notif"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xaction"
 	"github.com/OneOfOne/xxhash"
@@ -68,6 +70,9 @@ type (
 		// Length determines how many bytes should be read from the file,
 		// starting from provided offset.
 		length int64
+		// Name of the record which should be read from the shard (archive).
+		// The offset and length of the record are determined by the shard index.
+		record string
 		// Determines if it is GFN request
 		isGFN bool
 		// true: chunked transfer (en)coding as per https://tools.ietf.org/html/rfc7230#page-36
@@ -145,12 +150,20 @@ func (poi *putObjInfo) finalize() (err error, errCode int) {
 		poi.lom.Uncache()
 		return
 	}
-	if ecErr := ec.ECM.EncodeObject(poi.lom); ecErr != nil && ecErr != ec.ErrorECDisabled {
-		err = ecErr
-		return
+	// erasure code, unless deferred until the object ages (see housekeep/lifecycle)
+	if !poi.lom.Bprops().Lifecycle.DefersEC() {
+		if ecErr := ec.ECM.EncodeObject(poi.lom); ecErr != nil && ecErr != ec.ErrorECDisabled {
+			err = ecErr
+			return
+		}
 	}
 
 	poi.t.putMirror(poi.lom)
+	// neither rebalancing nor cold GET change the namespace
+	if !poi.migrated && (poi.lom.IsAIS() || !poi.cold) {
+		poi.t.recordChange(poi.lom, cmn.ChangeCreated)
+		notif.Notify(poi.lom, cmn.NotifPut)
+	}
 	return
 }
 
@@ -167,7 +180,7 @@ func (poi *putObjInfo) tryFinalize() (err error, errCode int) {
 			return
 		}
 		cmn.Assert(lom.Cksum() != nil)
-		ver, err, errCode = poi.t.cloud.putObj(poi.ctx, file, lom)
+		ver, err, errCode = poi.t.cloud.PutObj(poi.ctx, file, lom)
 		file.Close()
 		if err != nil {
 			err = fmt.Errorf("%s: PUT failed, err: %v", lom, err)
@@ -193,6 +206,10 @@ func (poi *putObjInfo) tryFinalize() (err error, errCode int) {
 			return
 		}
 	}
+	// migrated objects retain the write time of the original (see housekeep/lifecycle)
+	if !poi.migrated {
+		lom.SetWtimeUnix(time.Now().UnixNano())
+	}
 
 	if err := cmn.Rename(poi.workFQN, lom.FQN); err != nil {
 		return fmt.Errorf("rename failed => %s: %v", lom, err), 0
@@ -565,6 +582,24 @@ func (goi *getObjInfo) getFromNeighbor(lom *cluster.LOM, tsi *cluster.Snode) (ok
 	return
 }
 
+// resolveRecord sets the offset and length of the record from the shard index.
+// Empty records have nothing to read (zero length means the whole object).
+func (goi *getObjInfo) resolveRecord() (empty bool, err error, errCode int) {
+	index, err := extract.LoadShardIndex(goi.lom)
+	if err != nil {
+		return false, err, http.StatusInternalServerError
+	}
+	record, ok := index.Records[goi.record]
+	if !ok {
+		return false, fmt.Errorf("record %q does not exist in %s", goi.record, goi.lom), http.StatusNotFound
+	}
+	if record.Size == 0 {
+		return true, nil, 0
+	}
+	goi.offset, goi.length = record.Offset, record.Size
+	return false, nil, 0
+}
+
 func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode int) {
 	var (
 		file    *os.File
@@ -587,6 +622,13 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 		}
 	}()
 
+	if goi.record != "" {
+		var empty bool
+		if empty, err, errCode = goi.resolveRecord(); err != nil || empty {
+			return
+		}
+	}
+
 	cksumConf := goi.lom.CksumConf()
 	cksumRange := cksumConf.Type != cmn.ChecksumNone && goi.length > 0 && cksumConf.EnableReadRange
 
@@ -160,6 +160,37 @@ func GetBucketsSummaries(baseParams BaseParams, bucket, provider string,
 	return summaries, nil
 }
 
+// GetBucketChanges API
+//
+// Returns the changes of the bucket namespace (objects created, deleted and
+// renamed) since the position given by `token`. Empty token returns no changes,
+// only the current position - to be passed to the next call. If there are no
+// changes yet, the call waits for them up to `wait`.
+// When the returned changes have Reset set, some changes were lost and the
+// bucket must be relisted.
+func GetBucketChanges(baseParams BaseParams, bucket, provider, token string, wait time.Duration) (*cmn.BucketChanges, error) {
+	var (
+		changes = &cmn.BucketChanges{}
+		path    = cmn.URLPath(cmn.Version, cmn.Buckets, bucket)
+		q       = url.Values{}
+	)
+	baseParams.Method = http.MethodGet
+	q.Set(cmn.URLParamWhat, cmn.GetWhatChanges)
+	q.Set(cmn.URLParamProvider, provider)
+	q.Set(cmn.URLParamChangeToken, token)
+	if wait > 0 {
+		q.Set(cmn.URLParamWait, wait.String())
+	}
+	b, err := DoHTTPRequest(baseParams, path, nil, OptionalParams{Query: q})
+	if err != nil {
+		return nil, err
+	}
+	if err = jsoniter.Unmarshal(b, changes); err != nil {
+		return nil, fmt.Errorf("failed to json-unmarshal, err: %v", err)
+	}
+	return changes, nil
+}
+
 // CreateBucket API
 //
 // CreateBucket sends a HTTP request to a proxy to create an ais bucket with the given name
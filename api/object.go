@@ -128,13 +128,14 @@ func HeadObject(baseParams BaseParams, bucket, provider, object string, checkExi
 	}
 
 	objProps := &cmn.ObjectProps{
-		Size:      size,
-		Version:   r.Header.Get(cmn.HeaderObjVersion),
-		Atime:     atime,
-		NumCopies: numCopies,
-		Checksum:  r.Header.Get(cmn.HeaderObjCksumVal),
-		Present:   present,
-		BckIsAIS:  isais,
+		Size:         size,
+		Version:      r.Header.Get(cmn.HeaderObjVersion),
+		Atime:        atime,
+		NumCopies:    numCopies,
+		Checksum:     r.Header.Get(cmn.HeaderObjCksumVal),
+		ChecksumType: r.Header.Get(cmn.HeaderObjCksumType),
+		Present:      present,
+		BckIsAIS:     isais,
 	}
 
 	if ecStr := r.Header.Get(cmn.HeaderObjECMeta); ecStr != "" {
@@ -413,15 +414,16 @@ func FlushObject(args AppendArgs) (err error) {
 // Creates a cmn.ActionMsg with the new name of the object
 // and sends a POST HTTP Request to /v1/objects/bucket-name/object-name
 //
-// FIXME: handle cloud provider - here and elsewhere
-func RenameObject(baseParams BaseParams, bucket, oldName, newName string) error {
+// NOTE: only objects in ais buckets can be renamed
+func RenameObject(baseParams BaseParams, bucket, oldName, newName, provider string) error {
 	msg, err := jsoniter.Marshal(cmn.ActionMsg{Action: cmn.ActRename, Name: newName})
 	if err != nil {
 		return err
 	}
 	baseParams.Method = http.MethodPost
 	path := cmn.URLPath(cmn.Version, cmn.Objects, bucket, oldName)
-	_, err = DoHTTPRequest(baseParams, path, msg)
+	query := url.Values{cmn.URLParamProvider: []string{provider}}
+	_, err = DoHTTPRequest(baseParams, path, msg, OptionalParams{Query: query})
 	return err
 }
 
@@ -452,6 +454,48 @@ func PromoteFileOrDir(args *PromoteArgs) error {
 	return err
 }
 
+// ListArchive API
+//
+// Returns the members of the archive (.tar, .tgz, .tar.gz or .zip) stored as
+// the object in the bucket.
+func ListArchive(baseParams BaseParams, bucket, provider, object string) (entries []cmn.ArchiveEntry, err error) {
+	msg, err := jsoniter.Marshal(cmn.ActionMsg{Action: cmn.ActListArchive})
+	if err != nil {
+		return nil, err
+	}
+	baseParams.Method = http.MethodPost
+	path := cmn.URLPath(cmn.Version, cmn.Objects, bucket, object)
+	query := url.Values{cmn.URLParamProvider: []string{provider}}
+	params := OptionalParams{Query: query}
+	resp, err := DoHTTPRequest(baseParams, path, msg, params)
+	if err != nil {
+		return nil, err
+	}
+	err = jsoniter.Unmarshal(resp, &entries)
+	return entries, err
+}
+
+// ExtractArchive API
+//
+// Extracts the members of the archive stored as the object in the bucket into
+// separate objects. Returns the names of the created objects.
+func ExtractArchive(baseParams BaseParams, bucket, provider, object string, args *cmn.ActValExtract) (objNames []string, err error) {
+	msg, err := jsoniter.Marshal(cmn.ActionMsg{Action: cmn.ActExtract, Value: args})
+	if err != nil {
+		return nil, err
+	}
+	baseParams.Method = http.MethodPost
+	path := cmn.URLPath(cmn.Version, cmn.Objects, bucket, object)
+	query := url.Values{cmn.URLParamProvider: []string{provider}}
+	params := OptionalParams{Query: query}
+	resp, err := DoHTTPRequest(baseParams, path, msg, params)
+	if err != nil {
+		return nil, err
+	}
+	err = jsoniter.Unmarshal(resp, &objNames)
+	return objNames, err
+}
+
 // ReplicateObject API
 //
 // ReplicateObject replicates given object in bucket using targetrunner's replicate endpoint.
@@ -486,7 +530,8 @@ func DownloadSingleWithParam(baseParams BaseParams, dlBody cmn.DlSingleBody) (st
 	baseParams.Method = http.MethodPost
 	path := cmn.URLPath(cmn.Version, cmn.Download)
 	optParams := OptionalParams{
-		Query: query,
+		Query:  query,
+		Header: dlBody.AsHeader(),
 	}
 	return doDlDownloadRequest(baseParams, path, nil, optParams)
 }
@@ -506,7 +551,22 @@ func DownloadRangeWithParam(baseParams BaseParams, dlBody cmn.DlRangeBody) (stri
 	baseParams.Method = http.MethodPost
 	path := cmn.URLPath(cmn.Version, cmn.Download)
 	optParams := OptionalParams{
-		Query: query,
+		Query:  query,
+		Header: dlBody.AsHeader(),
+	}
+	return doDlDownloadRequest(baseParams, path, nil, optParams)
+}
+
+// DownloadListWithParam downloads the links listed in the object stored in the
+// cluster (see: cmn.DlListBody).
+func DownloadListWithParam(baseParams BaseParams, dlBody cmn.DlListBody) (string, error) {
+	query := dlBody.AsQuery()
+
+	baseParams.Method = http.MethodPost
+	path := cmn.URLPath(cmn.Version, cmn.Download)
+	optParams := OptionalParams{
+		Query:  query,
+		Header: dlBody.AsHeader(),
 	}
 	return doDlDownloadRequest(baseParams, path, nil, optParams)
 }
@@ -515,6 +575,12 @@ func DownloadMulti(baseParams BaseParams, description string, bucket string, m i
 	dlBody := cmn.DlMultiBody{}
 	dlBody.Bucket = bucket
 	dlBody.Description = description
+	return DownloadMultiWithParam(baseParams, dlBody, m)
+}
+
+// DownloadMultiWithParam downloads the objects given either as a list of links
+// or as a map of object names to links.
+func DownloadMultiWithParam(baseParams BaseParams, dlBody cmn.DlMultiBody, m interface{}) (string, error) {
 	query := dlBody.AsQuery()
 
 	msg, err := jsoniter.Marshal(m)
@@ -525,7 +591,8 @@ func DownloadMulti(baseParams BaseParams, description string, bucket string, m i
 	baseParams.Method = http.MethodPost
 	path := cmn.URLPath(cmn.Version, cmn.Download)
 	optParams := OptionalParams{
-		Query: query,
+		Query:  query,
+		Header: dlBody.AsHeader(),
 	}
 	return doDlDownloadRequest(baseParams, path, msg, optParams)
 }
@@ -546,7 +613,8 @@ func DownloadCloudWithParam(baseParams BaseParams, dlBody cmn.DlCloudBody) (stri
 	baseParams.Method = http.MethodPost
 	path := cmn.URLPath(cmn.Version, cmn.Download)
 	optParams := OptionalParams{
-		Query: query,
+		Query:  query,
+		Header: dlBody.AsHeader(),
 	}
 	return doDlDownloadRequest(baseParams, path, nil, optParams)
 }
@@ -618,6 +686,30 @@ func DownloadRemove(baseParams BaseParams, id string) error {
 	return err
 }
 
+func DownloadPause(baseParams BaseParams, id string) error {
+	return doDlAdminPutRequest(baseParams, cmn.Pause, cmn.DlAdminBody{ID: id})
+}
+
+func DownloadResume(baseParams BaseParams, id string) error {
+	return doDlAdminPutRequest(baseParams, cmn.Resume, cmn.DlAdminBody{ID: id})
+}
+
+// DownloadSetPriority changes the priority of the download job,
+// see: cmn.DlBase.Priority.
+func DownloadSetPriority(baseParams BaseParams, id string, priority int) error {
+	return doDlAdminPutRequest(baseParams, cmn.Priority, cmn.DlAdminBody{ID: id, Priority: priority})
+}
+
+func doDlAdminPutRequest(baseParams BaseParams, action string, dlBody cmn.DlAdminBody) error {
+	baseParams.Method = http.MethodPut
+	path := cmn.URLPath(cmn.Version, cmn.Download, action)
+	optParams := OptionalParams{
+		Query: dlBody.AsQuery(),
+	}
+	_, err := DoHTTPRequest(baseParams, path, nil, optParams)
+	return err
+}
+
 func doDlDownloadRequest(baseParams BaseParams, path string, msg []byte, optParams OptionalParams) (string, error) {
 	respBytes, err := DoHTTPRequest(baseParams, path, msg, optParams)
 	if err != nil {
@@ -98,8 +98,9 @@ const (
 	subcmdStopDownload = subcmdDownload
 
 	// Set subcommands
-	subcmdSetConfig = subcmdConfig
-	subcmdSetProps  = subcmdProps
+	subcmdSetConfig   = subcmdConfig
+	subcmdSetProps    = subcmdProps
+	subcmdSetDownload = subcmdDownload
 
 	// Register subcommands
 	subcmdRegisterProxy  = subcmdProxy
@@ -256,8 +257,27 @@ var (
 	// Download
 	descriptionFlag = cli.StringFlag{Name: "description,desc", Usage: "description of the job - can be useful when listing all downloads"}
 	timeoutFlag     = cli.StringFlag{Name: "timeout", Usage: "timeout for request to external resource, eg. '30m'"}
+	dlManifestFlag  = cli.StringFlag{Name: "manifest", Usage: "name of the object to which the manifest of downloaded objects is written once the download finishes"}
+	dlChunkSizeFlag = cli.StringFlag{Name: "chunk-size", Usage: "objects larger than chunk size are downloaded in chunks concurrently, eg. '256MiB'"}
 	verboseFlag     = cli.BoolFlag{Name: "verbose,v", Usage: "verbose"}
 
+	dlLimitConnsFlag     = cli.IntFlag{Name: "limit-connections", Usage: "max number of concurrent connections of the job (cluster-wide)"}
+	dlLimitRPSFlag       = cli.IntFlag{Name: "limit-rps", Usage: "max number of requests per second of the job (cluster-wide)"}
+	dlLimitBPSFlag       = cli.StringFlag{Name: "limit-bps", Usage: "max bandwidth of the job (cluster-wide), eg. '10MiB'"}
+	dlHostLimitConnsFlag = cli.IntFlag{Name: "host-limit-connections", Usage: "max number of concurrent connections to each host (cluster-wide)"}
+	dlHostLimitRPSFlag   = cli.IntFlag{Name: "host-limit-rps", Usage: "max number of requests per second to each host (cluster-wide)"}
+	dlHostLimitBPSFlag   = cli.StringFlag{Name: "host-limit-bps", Usage: "max bandwidth from each host (cluster-wide), eg. '10MiB'"}
+	dlHeaderFlag         = cli.StringSliceFlag{Name: "header", Usage: "header added to each request to the source, eg. 'Cookie: session=abc' (can be repeated)"}
+	dlListFlag           = cli.StringFlag{Name: "list", Usage: "object with the links to download, one per line (txt, csv or jsonl), eg. 'ais://bucket/links.csv'"}
+	dlTokenFlag          = cli.StringFlag{Name: "token", Usage: "bearer token added to each request to the source (eg. HuggingFace access token)"}
+	dlSyncIntervalFlag   = cli.StringFlag{Name: "sync-interval", Usage: "run the job again every interval, downloading only new and changed objects, eg. '24h'"}
+	dlCksumTypeFlag      = cli.StringFlag{Name: "cksum-type", Usage: "type of the expected checksums: md5, sha256, xxhash or crc32c"}
+	dlCksumListFlag      = cli.StringFlag{Name: "cksum-list", Usage: "object (in the destination bucket) with the expected checksums in 'sha256sum' format, eg. 'SHA256SUMS'"}
+	dlCksumFlag          = cli.StringFlag{Name: "cksum", Usage: "expected checksum of the downloaded object (single download only)"}
+	dlPriorityFlag       = cli.IntFlag{Name: "priority", Usage: "priority of the job (1-100), concurrent jobs share the cluster in proportion to their priorities"}
+	dlPauseFlag          = cli.BoolFlag{Name: "pause", Usage: "pause the job instead of stopping it (see: --resume)"}
+	dlResumeFlag         = cli.StringFlag{Name: "resume", Usage: "resume the paused job with given ID instead of starting a new one"}
+
 	// dSort
 	dsortBucketFlag   = cli.StringFlag{Name: "bucket", Value: cmn.DSortNameLowercase + "-testing", Usage: "bucket where shards will be put"}
 	dsortTemplateFlag = cli.StringFlag{Name: "template", Value: "shard-{0..9}", Usage: "template of input shard name"}
@@ -25,14 +25,33 @@ var (
 		subcmdStartDownload: {
 			timeoutFlag,
 			descriptionFlag,
+			dlManifestFlag,
+			dlChunkSizeFlag,
+			dlLimitConnsFlag,
+			dlLimitRPSFlag,
+			dlLimitBPSFlag,
+			dlHostLimitConnsFlag,
+			dlHostLimitRPSFlag,
+			dlHostLimitBPSFlag,
+			dlHeaderFlag,
+			dlTokenFlag,
+			dlListFlag,
+			dlSyncIntervalFlag,
+			dlCksumTypeFlag,
+			dlCksumListFlag,
+			dlCksumFlag,
+			dlPriorityFlag,
+			dlResumeFlag,
 		},
 		subcmdStartDsort: {},
 	}
 
 	stopCmdsFlags = map[string][]cli.Flag{
-		subcmdStopXaction:  {},
-		subcmdStopDownload: {},
-		subcmdStopDsort:    {},
+		subcmdStopXaction: {},
+		subcmdStopDownload: {
+			dlPauseFlag,
+		},
+		subcmdStopDsort: {},
 	}
 
 	controlCmds = []cli.Command{
@@ -171,16 +190,51 @@ func stopXactionHandler(c *cli.Context) (err error) {
 }
 
 func startDownloadHandler(c *cli.Context) error {
+	if flagIsSet(c, dlResumeFlag) {
+		id := parseStrFlag(c, dlResumeFlag)
+		if err := api.DownloadResume(defaultAPIParams, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.Writer, "download job %s has been resumed successfully.\n", id)
+		return nil
+	}
+
 	var (
-		description = parseStrFlag(c, descriptionFlag)
-		timeout     = parseStrFlag(c, timeoutFlag)
-		id          string
+		description  = parseStrFlag(c, descriptionFlag)
+		timeout      = parseStrFlag(c, timeoutFlag)
+		manifest     = parseStrFlag(c, dlManifestFlag)
+		chunkSize    = parseStrFlag(c, dlChunkSizeFlag)
+		syncInterval = parseStrFlag(c, dlSyncIntervalFlag)
+		id           string
 	)
 
 	basePayload := cmn.DlBase{
-		Provider:    cmn.AIS, // NOTE: currently downloading only to ais buckets is supported
-		Timeout:     timeout,
-		Description: description,
+		Provider:     cmn.AIS, // NOTE: currently downloading only to ais buckets is supported
+		Timeout:      timeout,
+		Description:  description,
+		Manifest:     manifest,
+		ChunkSize:    chunkSize,
+		SyncInterval: syncInterval,
+		CksumList:    parseStrFlag(c, dlCksumListFlag),
+		CksumType:    parseStrFlag(c, dlCksumTypeFlag),
+		Priority:     parseIntFlag(c, dlPriorityFlag),
+		Limits: cmn.DlLimits{
+			Connections:    parseIntFlag(c, dlLimitConnsFlag),
+			RequestsPerSec: parseIntFlag(c, dlLimitRPSFlag),
+			BytesPerSec:    parseStrFlag(c, dlLimitBPSFlag),
+		},
+		HostLimits: cmn.DlLimits{
+			Connections:    parseIntFlag(c, dlHostLimitConnsFlag),
+			RequestsPerSec: parseIntFlag(c, dlHostLimitRPSFlag),
+			BytesPerSec:    parseStrFlag(c, dlHostLimitBPSFlag),
+		},
+	}
+
+	if err := parseDlHeaders(c, &basePayload); err != nil {
+		return err
+	}
+	if flagIsSet(c, dlListFlag) {
+		return startListDownload(c, basePayload)
 	}
 
 	if c.NArg() == 0 {
@@ -217,8 +271,10 @@ func startDownloadHandler(c *cli.Context) error {
 		payload := cmn.DlSingleBody{
 			DlBase: basePayload,
 			DlObj: cmn.DlObj{
-				Link:    link,
-				Objname: pathSuffix, // in this case pathSuffix is a full name of the object
+				Link:       link,
+				Objname:    pathSuffix, // in this case pathSuffix is a full name of the object
+				CksumType:  basePayload.CksumType,
+				CksumValue: parseStrFlag(c, dlCksumFlag),
 			},
 		}
 		id, err = api.DownloadSingleWithParam(defaultAPIParams, payload)
@@ -231,6 +287,37 @@ func startDownloadHandler(c *cli.Context) error {
 	return nil
 }
 
+// startListDownload starts the download of the links listed in the object
+// stored in the cluster - only the destination is expected as the argument.
+func startListDownload(c *cli.Context, basePayload cmn.DlBase) error {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "destination")
+	}
+	listBucket, listObjname, err := parseDest(parseStrFlag(c, dlListFlag))
+	if err != nil {
+		return fmt.Errorf("invalid %s flag: %v", dlListFlag.Name, err)
+	}
+	if listObjname == "" {
+		return fmt.Errorf("invalid %s flag: object name cannot be omitted", dlListFlag.Name)
+	}
+	bucket, _, err := parseDest(c.Args().First())
+	if err != nil {
+		return err
+	}
+	basePayload.Bucket = bucket
+	payload := cmn.DlListBody{
+		DlBase:      basePayload,
+		ListBucket:  listBucket,
+		ListObjname: listObjname,
+	}
+	id, err := api.DownloadListWithParam(defaultAPIParams, payload)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.App.Writer, id)
+	return nil
+}
+
 func stopDownloadHandler(c *cli.Context) (err error) {
 	id := c.Args().First()
 
@@ -238,6 +325,14 @@ func stopDownloadHandler(c *cli.Context) (err error) {
 		return missingArgumentsError(c, "download job ID")
 	}
 
+	if flagIsSet(c, dlPauseFlag) {
+		if err = api.DownloadPause(defaultAPIParams, id); err != nil {
+			return
+		}
+		fmt.Fprintf(c.App.Writer, "download job %s has been paused successfully.\n", id)
+		return
+	}
+
 	if err = api.DownloadAbort(defaultAPIParams, id); err != nil {
 		return
 	}
@@ -303,3 +398,23 @@ func bucketXactionNames() cmn.StringSet {
 
 	return result
 }
+
+// parseDlHeaders sets the headers of the download job from the `--header`
+// and `--token` flags.
+func parseDlHeaders(c *cli.Context, payload *cmn.DlBase) error {
+	headers := parseStrSliceFlag(c, dlHeaderFlag)
+	if token := parseStrFlag(c, dlTokenFlag); token != "" {
+		headers = append(headers, cmn.HeaderAuthorization+": "+cmn.HeaderBearer+" "+token)
+	}
+	for _, header := range headers {
+		idx := strings.Index(header, ":")
+		if idx <= 0 {
+			return fmt.Errorf("invalid header %q, expected format: 'Name: value'", header)
+		}
+		if payload.Headers == nil {
+			payload.Headers = make(map[string]string, len(headers))
+		}
+		payload.Headers[strings.TrimSpace(header[:idx])] = strings.TrimSpace(header[idx+1:])
+	}
+	return nil
+}
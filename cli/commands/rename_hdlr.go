@@ -73,7 +73,7 @@ func renameObjectHandler(c *cli.Context) (err error) {
 		return incorrectUsageError(c, fmt.Errorf("no bucket specified for object '%s'", oldObj))
 	}
 
-	if err = api.RenameObject(defaultAPIParams, bucket, oldObj, newObj); err != nil {
+	if err = api.RenameObject(defaultAPIParams, bucket, oldObj, newObj, cmn.AIS); err != nil {
 		return
 	}
 
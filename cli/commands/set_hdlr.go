@@ -8,6 +8,7 @@ package commands
 import (
 	"fmt"
 
+	"github.com/NVIDIA/aistore/api"
 	"github.com/urfave/cli"
 )
 
@@ -19,6 +20,9 @@ var (
 			jsonspecFlag,
 			resetFlag,
 		},
+		subcmdSetDownload: {
+			dlPriorityFlag,
+		},
 	}
 
 	setCmds = []cli.Command{
@@ -42,6 +46,14 @@ var (
 					Action:       setPropsHandler,
 					BashComplete: bucketCompletions([]cli.BashCompleteFunc{propCompletions}, false /* multiple */, false /* separator */),
 				},
+				{
+					Name:         subcmdSetDownload,
+					Usage:        "updates priority of a download job with given ID",
+					ArgsUsage:    jobIDArgument,
+					Flags:        setCmdsFlags[subcmdSetDownload],
+					Action:       setDownloadHandler,
+					BashComplete: downloadIDRunningCompletions,
+				},
 			},
 		},
 	}
@@ -64,3 +76,22 @@ func setPropsHandler(c *cli.Context) (err error) {
 	}
 	return
 }
+
+func setDownloadHandler(c *cli.Context) (err error) {
+	id := c.Args().First()
+
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "download job ID")
+	}
+	if !flagIsSet(c, dlPriorityFlag) {
+		return missingArgumentsError(c, "--"+dlPriorityFlag.Name)
+	}
+
+	priority := parseIntFlag(c, dlPriorityFlag)
+	if err = api.DownloadSetPriority(defaultAPIParams, id, priority); err != nil {
+		return
+	}
+
+	fmt.Fprintf(c.App.Writer, "priority of download job %s has been set to %d.\n", id, priority)
+	return
+}
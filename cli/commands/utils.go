@@ -337,6 +337,15 @@ func parseIntFlag(c *cli.Context, flag cli.Flag) int {
 	return c.Int(flagName)
 }
 
+// Returns the values of a string slice flag (either parent or local scope)
+func parseStrSliceFlag(c *cli.Context, flag cli.Flag) []string {
+	flagName := cleanFlag(flag.GetName())
+	if c.GlobalIsSet(flagName) {
+		return c.GlobalStringSlice(flagName)
+	}
+	return c.StringSlice(flagName)
+}
+
 func parseByteFlagToInt(c *cli.Context, flag cli.Flag) (int64, error) {
 	flagValue := parseStrFlag(c, flag)
 	b, err := cmn.S2B(flagValue)
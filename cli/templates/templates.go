@@ -226,7 +226,9 @@ const (
 	BucketVerConfTmpl = "\n{{$obj := .Versioning}}Bucket Versioning\n" +
 		" Type:{{$obj.Type}}\n Validate Warm Get:{{$obj.ValidateWarmGet}}\n Enabled:{{$obj.Enabled}}\n"
 	DownloaderConfTmpl = "\n{{$obj := .Downloader}}Downloader Config\n" +
-		" Timeout: {{$obj.TimeoutStr}}\n"
+		" Timeout: {{$obj.TimeoutStr}}\n" +
+		" Chunk Size: {{$obj.ChunkSizeStr}}\n" +
+		" Chunk Concurrency: {{$obj.ChunkConcurrency}}\n"
 	DSortConfTmpl = "\n{{$obj := .DSort}}Distributed Sort Config\n" +
 		" Duplicated Records:\t{{$obj.DuplicatedRecords}}\n" +
 		" Missing Shards:\t{{$obj.MissingShards}}\n" +
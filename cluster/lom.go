@@ -43,6 +43,7 @@ type (
 		size    int64
 		atime   int64
 		atimefs int64
+		wtime   int64 // write (PUT) time - unlike mtime, survives migration, resilvering, and mirroring
 		bckID   uint64
 		cksum   *cmn.Cksum // ReCache(ref)
 		copies  fs.MPI     // ditto
@@ -87,6 +88,8 @@ func (lom *LOM) SetCksum(cksum *cmn.Cksum) { lom.md.cksum = cksum }
 func (lom *LOM) Atime() time.Time          { return time.Unix(0, lom.md.atime) }
 func (lom *LOM) AtimeUnix() int64          { return lom.md.atime }
 func (lom *LOM) SetAtimeUnix(tu int64)     { lom.md.atime = tu }
+func (lom *LOM) WtimeUnix() int64          { return lom.md.wtime }
+func (lom *LOM) SetWtimeUnix(tu int64)     { lom.md.wtime = tu }
 func (lom *LOM) ECEnabled() bool           { return lom.Bprops().EC.Enabled }
 func (lom *LOM) LRUEnabled() bool          { return lom.Bprops().LRU.Enabled }
 func (lom *LOM) IsHRW() bool               { return lom.HrwFQN == lom.FQN } // subj to resilvering
@@ -136,6 +139,7 @@ func (lom *LOM) CopyMetadata(from *LOM) {
 	lom.md.size = from.md.size
 	lom.md.version = from.md.version
 	lom.md.atime = from.md.atime
+	lom.md.wtime = from.md.wtime
 }
 
 func (lom *LOM) CloneCopiesMd() int {
@@ -25,6 +25,7 @@ const (
 	lomObjVersion
 	lomObjSize
 	lomObjCopies
+	lomObjWtime
 )
 
 // delimiters
@@ -113,6 +114,7 @@ func (md *lmeta) unmarshal(mdstr string) (err error) {
 		cksumType, cksumValue             string
 		haveSize, haveVersion, haveCopies bool
 		haveCksumType, haveCksumValue     bool
+		haveWtime                         bool
 		last                              bool
 	)
 	expectedCksum = binary.BigEndian.Uint64([]byte(mdstr))
@@ -177,6 +179,12 @@ func (md *lmeta) unmarshal(mdstr string) (err error) {
 				}
 				md.copies[copyFQN] = mpathInfo
 			}
+		case lomObjWtime:
+			if haveWtime {
+				return errors.New(invalid + "#9")
+			}
+			md.wtime = int64(binary.BigEndian.Uint64([]byte(val)))
+			haveWtime = true
 		default:
 			return errors.New(invalid + "#6")
 		}
@@ -231,6 +239,10 @@ func (md *lmeta) marshal(buf []byte) (metaCksum uint64, off int) {
 	if md.version != "" {
 		appendMD(lomObjVersion, md.version, true)
 	}
+	if md.wtime != 0 {
+		binary.BigEndian.PutUint64(b8[0:], uint64(md.wtime))
+		appendMD(lomObjWtime, string(b8[0:]), true)
+	}
 	binary.BigEndian.PutUint64(b8[0:], uint64(md.size))
 	appendMD(lomObjSize, string(b8[0:]), false)
 	if len(md.copies) > 0 {
@@ -31,6 +31,7 @@ const (
 
 type CloudProvider interface {
 	ListBucket(ctx context.Context, bucket string, msg *cmn.SelectMsg) (bckList *cmn.BucketList, err error, errCode int)
+	PutObj(ctx context.Context, r io.Reader, lom *LOM) (version string, err error, errCode int)
 }
 
 // a callback called by EC PUT jogger after the object is processed and
@@ -61,6 +62,7 @@ type Target interface {
 	PutObject(workFQN string, reader io.ReadCloser, lom *LOM, recvType RecvType, cksum *cmn.Cksum, started time.Time) error
 	CopyObject(lom *LOM, bckTo *Bck, buf []byte, localOnly bool) (bool, error)
 	GetCold(ctx context.Context, lom *LOM, prefetch bool) (error, int)
+	GetColdVerified(ctx context.Context, lom *LOM, verify func(workFQN string) error) (error, int)
 	PromoteFile(srcFQN string, bck *Bck, objName string, overwrite, safe, verbose bool) (err error)
 	LookupRemoteSingle(lom *LOM, si *Snode) bool
 
@@ -58,6 +58,9 @@ func (*TargetMock) RebalanceInfo() RebalanceInfo {
 func (*TargetMock) GetCold(ctx context.Context, lom *LOM, prefetch bool) (error, int) {
 	return nil, http.StatusOK
 }
+func (*TargetMock) GetColdVerified(ctx context.Context, lom *LOM, verify func(string) error) (error, int) {
+	return nil, http.StatusOK
+}
 func (*TargetMock) PutObject(_ string, _ io.ReadCloser, _ *LOM, _ RecvType, _ *cmn.Cksum, _ time.Time) error {
 	return nil
 }
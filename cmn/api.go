@@ -5,6 +5,7 @@
 package cmn
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/url"
 	"strings"
@@ -29,6 +30,22 @@ type ActValPromote struct {
 	Verbose   bool   `json:"verbose"`
 }
 
+// ActValExtract is the value of the extract action (see ActExtract). Empty
+// Members means all members of the archive. Empty Bucket means the bucket
+// of the archive.
+type ActValExtract struct {
+	Members  []string `json:"members"`
+	Bucket   string   `json:"bucket"`
+	Provider string   `json:"provider"`
+	Prefix   string   `json:"prefix"`
+}
+
+// ArchiveEntry describes a single member of the archive (see ActListArchive).
+type ArchiveEntry struct {
+	Name string `json:"name"`
+	Size int64  `json:"size,string"`
+}
+
 type XactKindMeta struct {
 	IsGlobal bool
 }
@@ -45,6 +62,7 @@ var XactKind = XactKindType{
 	ActDownload:     {true},
 	ActEvictObjects: {true},
 	ActDelete:       {true},
+	ActLifecycle:    {true},
 
 	// bucket's kinds
 	ActECGet:       {},
@@ -203,6 +221,79 @@ type BucketNames struct {
 	AIS   []string `json:"ais"`
 }
 
+// enum: BucketChange.Type
+const (
+	ChangeCreated = "created" // object created or overwritten
+	ChangeDeleted = "deleted"
+	ChangeRenamed = "renamed"
+)
+
+// BucketChange describes a single change of the bucket namespace
+type BucketChange struct {
+	Type    string `json:"type"`               // one of the Change* enum above
+	Name    string `json:"name"`               // name of the object
+	NewName string `json:"new_name,omitempty"` // new name of the object (ChangeRenamed only)
+	Size    int64  `json:"size,string,omitempty"`
+	Version string `json:"version,omitempty"`
+	Time    int64  `json:"time,string"` // Unix time (nanoseconds) of the change
+}
+
+// BucketChanges is the result of querying the change feed of a bucket.
+// Token is to be passed to the next query; Reset indicates that some changes
+// were lost (eg. due to target restart) and the bucket must be relisted.
+type BucketChanges struct {
+	Changes []BucketChange `json:"changes"`
+	Token   string         `json:"token"`
+	Reset   bool           `json:"reset"`
+}
+
+// ChangeCursor is a position in the change feed of a single target
+type ChangeCursor struct {
+	Epoch int64 `json:"e,string"` // target startup time; changes when the target restarts
+	Seq   int64 `json:"s,string"` // sequence number of the last seen change
+}
+
+// ChangeToken is the position in the change feed of the entire cluster: daemon ID => cursor
+type ChangeToken map[string]ChangeCursor
+
+func (t ChangeToken) Encode() string {
+	return base64.RawURLEncoding.EncodeToString(MustMarshal(t))
+}
+
+func DecodeChangeToken(s string) (t ChangeToken, err error) {
+	t = make(ChangeToken)
+	if s == "" {
+		return
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = jsoniter.Unmarshal(b, &t)
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid change token %q: %v", s, err)
+	}
+	return
+}
+
+// ObjectEvent is the object event delivered to the bucket webhook (see NotifConf)
+type ObjectEvent struct {
+	Type     string `json:"type"` // one of the Notif* enum
+	Bucket   string `json:"bucket"`
+	Provider string `json:"provider"`
+	Name     string `json:"name"`               // name of the object
+	NewName  string `json:"new_name,omitempty"` // new name of the object (NotifRename only)
+	Size     int64  `json:"size,string,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+	Time     int64  `json:"time,string"` // Unix time (nanoseconds) of the event
+	Target   string `json:"target"`      // ID of the target that has generated the event
+}
+
+// ObjectEvents is the body of the POST request delivering a batch of events to the webhook
+type ObjectEvents struct {
+	Events []ObjectEvent `json:"events"`
+}
+
 func MakeAccess(aattr uint64, action string, bits uint64) uint64 {
 	if aattr == AllowAnyAccess {
 		aattr = AllowAllAccess
@@ -247,6 +338,12 @@ type BucketProps struct {
 	// EC defines erasure coding setting for the bucket
 	EC ECConf `json:"ec"`
 
+	// Notif defines delivery of the object events to a webhook
+	Notif NotifConf `json:"notif"`
+
+	// Lifecycle defines the rules applied to the objects depending on their age
+	Lifecycle LifecycleConf `json:"lifecycle"`
+
 	// Bucket access attributes - see Allow* above
 	AccessAttrs uint64 `json:"aattrs,string"`
 
@@ -262,12 +359,14 @@ type BucketProps struct {
 }
 
 type BucketPropsToUpdate struct {
-	Versioning  *VersionConfToUpdate `json:"versioning"`
-	Cksum       *CksumConfToUpdate   `json:"cksum"`
-	LRU         *LRUConfToUpdate     `json:"lru"`
-	Mirror      *MirrorConfToUpdate  `json:"mirror"`
-	EC          *ECConfToUpdate      `json:"ec"`
-	AccessAttrs *uint64              `json:"attrs,string"`
+	Versioning  *VersionConfToUpdate   `json:"versioning"`
+	Cksum       *CksumConfToUpdate     `json:"cksum"`
+	LRU         *LRUConfToUpdate       `json:"lru"`
+	Mirror      *MirrorConfToUpdate    `json:"mirror"`
+	EC          *ECConfToUpdate        `json:"ec"`
+	Notif       *NotifConfToUpdate     `json:"notif"`
+	Lifecycle   *LifecycleConfToUpdate `json:"lifecycle"`
+	AccessAttrs *uint64                `json:"attrs,string"`
 }
 
 type TierConf struct {
@@ -419,6 +518,7 @@ type ObjectProps struct {
 	Version      string
 	Atime        time.Time
 	Checksum     string
+	ChecksumType string
 	NumCopies    int
 	DataSlices   int
 	ParitySlices int
@@ -505,7 +605,7 @@ func (bp *BucketProps) Validate(bckIsAIS bool, targetCnt int, urlOutsideCluster
 		}
 	}
 	validationArgs := &ValidationArgs{BckIsAIS: bckIsAIS, TargetCnt: targetCnt}
-	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC}
+	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC, &bp.Notif, &bp.Lifecycle}
 	for _, validator := range validators {
 		if err := validator.ValidateAsProps(validationArgs); err != nil {
 			return err
@@ -552,6 +652,8 @@ func NewBucketPropsToUpdate(nvs SimpleKVs) (props BucketPropsToUpdate, err error
 		LRU:        &LRUConfToUpdate{},
 		Mirror:     &MirrorConfToUpdate{},
 		EC:         &ECConfToUpdate{},
+		Notif:      &NotifConfToUpdate{},
+		Lifecycle:  &LifecycleConfToUpdate{},
 	}
 
 	for key, val := range nvs {
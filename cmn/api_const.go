@@ -19,6 +19,7 @@ const (
 	ChecksumXXHash = "xxhash"
 	ChecksumMD5    = "md5"
 	ChecksumCRC32C = "crc32c"
+	ChecksumSHA256 = "sha256" // only to verify downloaded objects, see: DlObj
 )
 
 // module names
@@ -37,12 +38,24 @@ const (
 	FlushOp  = "flush"
 )
 
+// enum: object events delivered to the bucket webhook (see NotifConf)
+const (
+	NotifPut      = "put"
+	NotifDelete   = "delete"
+	NotifRename   = "rename"
+	NotifECEncode = "ec_encode"
+	NotifDownload = "download"
+)
+
+var NotifEvents = []string{NotifPut, NotifDelete, NotifRename, NotifECEncode, NotifDownload}
+
 // ActionMsg.Action enum (includes xactions)
 const (
 	ActShutdown      = "shutdown"
 	ActGlobalReb     = "rebalance" // global cluster-wide rebalance
 	ActLocalReb      = "resilver"  // local rebalance aka resilver
 	ActLRU           = "lru"
+	ActLifecycle     = "lifecycle"
 	ActSyncLB        = "synclb"
 	ActCreateLB      = "createlb"
 	ActDestroyLB     = "destroylb"
@@ -57,6 +70,8 @@ const (
 	ActSummaryBucket = "summarybck"
 	ActRename        = "rename"
 	ActPromote       = "promote"
+	ActListArchive   = "listarch"
+	ActExtract       = "extract"
 	ActReplicate     = "replicate"
 	ActEvictObjects  = "evictobjects"
 	ActDelete        = "delete"
@@ -139,14 +154,23 @@ const (
 	// custom
 	HeaderAppendHandle = "append.handle"
 
+	// downloader: headers with this prefix (eg. "Dl-Header-Authorization")
+	// are added (without the prefix) to the requests to the source
+	HeaderDlPrefix = "Dl-Header-"
+
 	// intra-cluster: streams
-	HeaderSessID   = "session.id"
-	HeaderCompress = "compress" // LZ4Compression, etc.
+	HeaderSessID       = "session.id"
+	HeaderSessAuth     = "session.auth"  // "<timestamp>:<HMAC>", see TLSConf.HMACSecret
+	HeaderSessKey      = "session.key"   // flow control: unique key of the sending stream
+	HeaderCompress     = "compress"      // LZ4Compression, etc.
+	HeaderFlowConsumed = "flow.consumed" // flow control: stream offset consumed by the receiver
+	HeaderFlowWindow   = "flow.window"   // flow control: max bytes in flight granted by the receiver
 )
 
 // supported compressions (alg-s)
 const (
-	LZ4Compression = "lz4"
+	LZ4Compression  = "lz4"
+	ZstdCompression = "zstd"
 )
 
 // URL Query "?name1=val1&name2=..."
@@ -160,6 +184,10 @@ const (
 	URLParamProvider    = "provider"     // ais | cloud
 	URLParamPrefix      = "prefix"       // prefix for list objects in a bucket
 	URLParamRegex       = "regex"        // dsort/downloader regex
+	URLParamRecord      = "record"       // name of the record (file in the archive) to be read from the shard
+	URLParamShardIndex  = "shard_index"  // true: create index of the shard (archive) being PUT
+	URLParamChangeToken = "change_token" // position in the change feed of the bucket (see ChangeToken)
+	URLParamWait        = "wait"         // long-poll: max time to wait for the changes
 	// internal use
 	URLParamCheckExistsAny   = "cea" // true: lookup object in all mountpaths (NOTE: compare with URLParamCheckExists)
 	URLParamProxyID          = "pid" // ID of the redirecting proxy
@@ -186,15 +214,45 @@ const (
 	URLParamTotalUncompressedSize     = "tunc"
 
 	// downloader
-	URLParamBucket      = "bucket"
-	URLParamID          = "id"
-	URLParamLink        = "link"
-	URLParamObjName     = "objname"
-	URLParamSuffix      = "suffix"
-	URLParamTemplate    = "template"
-	URLParamSubdir      = "subdir"
-	URLParamTimeout     = "timeout"
-	URLParamDescription = "description"
+	URLParamBucket       = "bucket"
+	URLParamID           = "id"
+	URLParamLink         = "link"
+	URLParamObjName      = "objname"
+	URLParamSuffix       = "suffix"
+	URLParamTemplate     = "template"
+	URLParamSubdir       = "subdir"
+	URLParamTimeout      = "timeout"
+	URLParamDescription  = "description"
+	URLParamManifest     = "manifest"
+	URLParamChunkSize    = "chunk_size"
+	URLParamSyncInterval = "sync_interval"
+	URLParamCksumType    = "cksum_type"
+	URLParamCksumValue   = "cksum_value"
+	URLParamCksumList    = "cksum_list"
+	URLParamPriority     = "priority"
+
+	// type of the checksums listed in the URLParamCksumList object
+	URLParamCksumListType = "cksum_list_type"
+
+	// downloader limits (see: DlLimits), limits of each host of the job
+	// are prefixed with URLParamHostPrefix
+	URLParamLimitConns = "limit_connections"
+	URLParamLimitRPS   = "limit_requests_per_sec"
+	URLParamLimitBPS   = "limit_bytes_per_sec"
+	URLParamHostPrefix = "host_"
+
+	// downloader: links read from the object stored in the cluster (see: DlListBody)
+	URLParamListBucket   = "list_bucket"
+	URLParamListProvider = "list_provider"
+	URLParamListObjName  = "list_objname"
+	URLParamListFormat   = "list_format"
+)
+
+// enum: format of the object with links (cmn.URLParamListFormat)
+const (
+	DlListFormatTxt   = "txt"   // one link per line
+	DlListFormatCSV   = "csv"   // link[,objname] per line
+	DlListFormatJSONL = "jsonl" // {"link": ..., "objname": ...} per line
 )
 
 // enum: task action (cmn.URLParamTaskAction)
@@ -217,6 +275,7 @@ const (
 	GetWhatSysInfo      = "sysinfo"
 	GetWhatDiskStats    = "disk"
 	GetWhatDaemonStatus = "status"
+	GetWhatChanges      = "changes" // change feed of the bucket (see BucketChanges)
 )
 
 // SelectMsg.TimeFormat enum
@@ -298,8 +357,12 @@ const (
 	Records     = "records"
 	Shards      = "shards"
 	FinishedAck = "finished-ack"
+	Manifest    = "manifest"
 	List        = "list"
 	Remove      = "remove"
+	Pause       = "pause"
+	Resume      = "resume"
+	Priority    = "priority"
 
 	// CLI
 	Target = "target"
@@ -332,7 +395,7 @@ const (
 const (
 	CompressAlways = "always"
 	CompressNever  = "never"
-	CompressRatio  = "ratio=%d" // adaptive: min ratio that warrants compression
+	CompressRatio  = "ratio=%g" // adaptive: min ratio that warrants compression
 )
 
 // AuthN consts
@@ -5,12 +5,15 @@
 package cmn
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"path"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -28,6 +31,10 @@ type DlStatusResp struct {
 	Aborted       bool `json:"aborted"`
 	AllDispatched bool `json:"all_dispatched"` // if true, dispatcher has already scheduled all tasks for given job
 	Pending       int  `json:"num_pending"`    // tasks currently in download queue
+	// Time of the next run of the sync job, zero if none is scheduled.
+	NextSync time.Time `json:"next_sync"`
+	Paused   bool      `json:"paused"`
+	Priority int       `json:"priority"`
 
 	CurrentTasks  []TaskDlInfo  `json:"current_tasks,omitempty"`
 	FinishedTasks []TaskDlInfo  `json:"finished_tasks,omitempty"`
@@ -50,12 +57,18 @@ func (d *DlStatusResp) Print(verbose bool) string {
 				sb.WriteString(fmt.Sprintf("%s: %s\n", e.Name, e.Err))
 			}
 		}
+		if !d.NextSync.IsZero() {
+			sb.WriteString(fmt.Sprintf("Next sync: %s\n", d.NextSync.Format(time.RFC3339)))
+		}
 
 		return sb.String()
 	}
 
 	realFinished := d.Finished + errCount
 	sb.WriteString(fmt.Sprintf("Download progress: %d/%d (%.2f%%)", realFinished, d.TotalCnt(), 100*float64(realFinished)/float64(d.TotalCnt())))
+	if d.Paused {
+		sb.WriteString(" (paused)")
+	}
 	if !verbose {
 		sb.WriteString("\n")
 		return sb.String()
@@ -102,11 +115,14 @@ type DlJobInfo struct {
 	NumErrors   int    `json:"num_errors"`
 	NumPending  int    `json:"num_pending"`
 	Aborted     bool   `json:"aborted"`
+	Paused      bool   `json:"paused"`
+	Priority    int    `json:"priority"`
 }
 
 func (j *DlJobInfo) Aggregate(rhs DlJobInfo) {
 	j.NumErrors += rhs.NumErrors
 	j.NumPending += rhs.NumPending
+	j.Paused = j.Paused || rhs.Paused
 }
 
 func (j *DlJobInfo) IsRunning() bool {
@@ -128,7 +144,9 @@ func (j *DlJobInfo) String() string {
 
 	sb.WriteString(": ")
 
-	if j.NumPending == 0 {
+	if j.Paused {
+		sb.WriteString(fmt.Sprintf("paused, %d files waiting to be downloaded", j.NumPending))
+	} else if j.NumPending == 0 {
 		sb.WriteString("finished")
 	} else {
 		sb.WriteString(fmt.Sprintf("%d files still being downloaded", j.NumPending))
@@ -142,6 +160,110 @@ type DlBase struct {
 	Bucket      string `json:"bucket"`
 	Provider    string `json:"provider"`
 	Timeout     string `json:"timeout"`
+	// Name of the manifest object which is written into the bucket once
+	// the job has finished. No manifest is written if empty.
+	Manifest string `json:"manifest"`
+	// Objects larger than the chunk size are downloaded in chunks
+	// concurrently, overrides the value from the config if set.
+	ChunkSize string `json:"chunk_size"`
+	// Limits of the whole job and of each host from which the job downloads.
+	Limits     DlLimits `json:"limits"`
+	HostLimits DlLimits `json:"host_limits"`
+	// Headers added to each request to the source (eg. authorization,
+	// cookies). They travel in HTTP headers (see: HeaderDlPrefix) rather
+	// than in the query so that they are neither logged nor echoed back.
+	Headers map[string]string `json:"-"`
+	// If set, the job is run again every sync interval (eg. "24h") after
+	// its previous run has finished. Each run downloads only new objects
+	// and the objects which have changed since the previous run.
+	SyncInterval string `json:"sync_interval"`
+	// Name of the object (in the bucket) with the expected checksums of the
+	// objects in the format of `sha256sum` (or `md5sum` etc.) output, the
+	// type of the checksums is given by CksumType. Downloaded objects are
+	// verified against them (see: DlObj).
+	CksumList string `json:"cksum_list"`
+	CksumType string `json:"cksum_list_type"`
+	// Priority of the job (see: DlMinPriority, DlMaxPriority). Concurrent
+	// jobs share the downloader in proportion to their priorities, zero
+	// means DlDefaultPriority.
+	Priority int `json:"priority"`
+}
+
+const (
+	DlMinPriority     = 1
+	DlMaxPriority     = 100
+	DlDefaultPriority = 10
+)
+
+func ValidateDlPriority(priority int) error {
+	if priority < DlMinPriority || priority > DlMaxPriority {
+		return fmt.Errorf("invalid priority: %d (expected value in range [%d, %d])", priority, DlMinPriority, DlMaxPriority)
+	}
+	return nil
+}
+
+// DlLimits restricts the load which the download job puts on the sources.
+// The limits are cluster-wide - each target gets its (equal) share. Zero
+// means no limit.
+type DlLimits struct {
+	Connections    int    `json:"connections"`      // max number of concurrent connections
+	RequestsPerSec int    `json:"requests_per_sec"` // max number of requests per second
+	BytesPerSec    string `json:"bytes_per_sec"`    // max bandwidth, eg. "10MiB"
+}
+
+func (l *DlLimits) initWithQuery(query url.Values, prefix string) {
+	l.Connections, _ = strconv.Atoi(query.Get(prefix + URLParamLimitConns))
+	l.RequestsPerSec, _ = strconv.Atoi(query.Get(prefix + URLParamLimitRPS))
+	l.BytesPerSec = query.Get(prefix + URLParamLimitBPS)
+}
+
+func (l *DlLimits) addToQuery(query url.Values, prefix string) {
+	if l.Connections != 0 {
+		query.Add(prefix+URLParamLimitConns, strconv.Itoa(l.Connections))
+	}
+	if l.RequestsPerSec != 0 {
+		query.Add(prefix+URLParamLimitRPS, strconv.Itoa(l.RequestsPerSec))
+	}
+	if l.BytesPerSec != "" {
+		query.Add(prefix+URLParamLimitBPS, l.BytesPerSec)
+	}
+}
+
+func (l *DlLimits) validate() error {
+	if l.Connections < 0 {
+		return fmt.Errorf("invalid number of connections: %d", l.Connections)
+	}
+	if l.RequestsPerSec < 0 {
+		return fmt.Errorf("invalid number of requests per second: %d", l.RequestsPerSec)
+	}
+	if bps, err := S2B(l.BytesPerSec); err != nil || bps < 0 {
+		return fmt.Errorf("invalid bytes per second: %q", l.BytesPerSec)
+	}
+	return nil
+}
+
+// ValidateLimits checks the limits against the number of targets among which
+// they are divided: each of the targets must be able to make at least one
+// connection without exceeding the limit.
+func (b *DlBase) ValidateLimits(targetCnt int) error {
+	if b.Limits.Connections > 0 && b.Limits.Connections < targetCnt {
+		return fmt.Errorf("invalid limits: number of connections (%d) is lower than the number of targets (%d)",
+			b.Limits.Connections, targetCnt)
+	}
+	if b.HostLimits.Connections > 0 && b.HostLimits.Connections < targetCnt {
+		return fmt.Errorf("invalid host limits: number of connections (%d) is lower than the number of targets (%d)",
+			b.HostLimits.Connections, targetCnt)
+	}
+	return nil
+}
+
+func (l DlLimits) String() string {
+	return fmt.Sprintf("conns=%d, rps=%d, bps=%q", l.Connections, l.RequestsPerSec, l.BytesPerSec)
+}
+
+// IsZero returns true if there are no limits.
+func (l *DlLimits) IsZero() bool {
+	return l.Connections == 0 && l.RequestsPerSec == 0 && (l.BytesPerSec == "" || l.BytesPerSec == "0")
 }
 
 func (b *DlBase) InitWithQuery(query url.Values) {
@@ -151,6 +273,14 @@ func (b *DlBase) InitWithQuery(query url.Values) {
 	b.Provider = query.Get(URLParamProvider)
 	b.Timeout = query.Get(URLParamTimeout)
 	b.Description = query.Get(URLParamDescription)
+	b.Manifest = query.Get(URLParamManifest)
+	b.ChunkSize = query.Get(URLParamChunkSize)
+	b.SyncInterval = query.Get(URLParamSyncInterval)
+	b.CksumList = query.Get(URLParamCksumList)
+	b.CksumType = query.Get(URLParamCksumListType)
+	b.Priority, _ = strconv.Atoi(query.Get(URLParamPriority))
+	b.Limits.initWithQuery(query, "")
+	b.HostLimits.initWithQuery(query, URLParamHostPrefix)
 }
 
 func (b *DlBase) AsQuery() url.Values {
@@ -167,9 +297,65 @@ func (b *DlBase) AsQuery() url.Values {
 	if b.Description != "" {
 		query.Add(URLParamDescription, b.Description)
 	}
+	if b.Manifest != "" {
+		query.Add(URLParamManifest, b.Manifest)
+	}
+	if b.ChunkSize != "" {
+		query.Add(URLParamChunkSize, b.ChunkSize)
+	}
+	if b.SyncInterval != "" {
+		query.Add(URLParamSyncInterval, b.SyncInterval)
+	}
+	if b.CksumList != "" {
+		query.Add(URLParamCksumList, b.CksumList)
+	}
+	if b.CksumType != "" {
+		query.Add(URLParamCksumListType, b.CksumType)
+	}
+	if b.Priority != 0 {
+		query.Add(URLParamPriority, strconv.Itoa(b.Priority))
+	}
+	b.Limits.addToQuery(query, "")
+	b.HostLimits.addToQuery(query, URLParamHostPrefix)
 	return query
 }
 
+// InitWithHeader initializes the headers of the job from the request headers.
+func (b *DlBase) InitWithHeader(header http.Header) {
+	for name := range header {
+		if !strings.HasPrefix(name, HeaderDlPrefix) {
+			continue
+		}
+		if b.Headers == nil {
+			b.Headers = make(map[string]string)
+		}
+		b.Headers[strings.TrimPrefix(name, HeaderDlPrefix)] = header.Get(name)
+	}
+}
+
+func (b *DlBase) AsHeader() http.Header {
+	if len(b.Headers) == 0 {
+		return nil
+	}
+	header := make(http.Header, len(b.Headers))
+	for name, value := range b.Headers {
+		header.Set(HeaderDlPrefix+name, value)
+	}
+	return header
+}
+
+// redacted returns the copy with the values of the headers redacted.
+func (b *DlBase) redacted() DlBase {
+	c := *b
+	if len(b.Headers) > 0 {
+		c.Headers = make(map[string]string, len(b.Headers))
+		for name := range b.Headers {
+			c.Headers[name] = redacted
+		}
+	}
+	return c
+}
+
 func (b *DlBase) Validate() error {
 	if b.Bucket == "" {
 		return fmt.Errorf("missing the %q which is required", URLParamBucket)
@@ -179,6 +365,38 @@ func (b *DlBase) Validate() error {
 			return fmt.Errorf("failed to parse timeout field: %v", err)
 		}
 	}
+	if size, err := S2B(b.ChunkSize); err != nil || size < 0 {
+		return fmt.Errorf("invalid chunk_size field: %q", b.ChunkSize)
+	}
+	if b.SyncInterval != "" {
+		if interval, err := time.ParseDuration(b.SyncInterval); err != nil || interval < time.Minute {
+			return fmt.Errorf("invalid sync_interval field: %q (expected duration of at least 1m)", b.SyncInterval)
+		}
+	}
+	if b.CksumType != "" && !IsDlCksumType(b.CksumType) {
+		return fmt.Errorf("invalid cksum_list_type field: %q (expected one of: %v)", b.CksumType, dlCksumTypes)
+	}
+	if b.CksumList != "" && b.CksumType == "" {
+		return fmt.Errorf("missing the %q of the %q", URLParamCksumListType, URLParamCksumList)
+	}
+	if b.Priority != 0 {
+		if err := ValidateDlPriority(b.Priority); err != nil {
+			return err
+		}
+	}
+	if err := b.Limits.validate(); err != nil {
+		return fmt.Errorf("invalid limits: %v", err)
+	}
+	if err := b.HostLimits.validate(); err != nil {
+		return fmt.Errorf("invalid host limits: %v", err)
+	}
+	for name := range b.Headers {
+		// Range and conditional requests are made by the downloader itself.
+		switch name = http.CanonicalHeaderKey(name); name {
+		case "Range", "If-Range", "If-None-Match", "If-Modified-Since":
+			return fmt.Errorf("header %q cannot be set", name)
+		}
+	}
 	return nil
 }
 
@@ -186,6 +404,28 @@ type DlObj struct {
 	Objname   string `json:"objname"`
 	Link      string `json:"link"`
 	FromCloud bool   `json:"from_cloud"`
+	// Version of the cloud object, used by the sync jobs to determine
+	// whether the object has changed (see: DlBase.SyncInterval). Size and
+	// ETag of the cloud object are used instead when it has no version.
+	Version string `json:"version,omitempty"`
+	Size    int64  `json:"size,omitempty"`
+	ETag    string `json:"etag,omitempty"`
+	// Expected checksum of the object (optional). The downloaded object is
+	// verified against it and is not stored in the bucket on mismatch.
+	CksumType  string `json:"cksum_type,omitempty"`
+	CksumValue string `json:"cksum_value,omitempty"`
+}
+
+// types of the checksums against which the downloaded objects can be verified
+var dlCksumTypes = []string{ChecksumMD5, ChecksumSHA256, ChecksumXXHash, ChecksumCRC32C}
+
+func IsDlCksumType(ty string) bool {
+	for _, t := range dlCksumTypes {
+		if t == ty {
+			return true
+		}
+	}
+	return false
 }
 
 func (b *DlObj) Validate() error {
@@ -202,18 +442,29 @@ func (b *DlObj) Validate() error {
 	if b.Objname == "" {
 		return fmt.Errorf("missing the %q from the request body", URLParamObjName)
 	}
+	if b.CksumValue != "" {
+		if !IsDlCksumType(b.CksumType) {
+			return fmt.Errorf("invalid %q: %q (expected one of: %v)", URLParamCksumType, b.CksumType, dlCksumTypes)
+		}
+		b.CksumValue = strings.ToLower(b.CksumValue)
+		if _, err := hex.DecodeString(b.CksumValue); err != nil {
+			return fmt.Errorf("invalid %q: %q (expected hex string)", URLParamCksumValue, b.CksumValue)
+		}
+	}
 	return nil
 }
 
 // Internal status/delete request body
 type DlAdminBody struct {
-	ID    string `json:"id"`
-	Regex string `json:"regex"`
+	ID       string `json:"id"`
+	Regex    string `json:"regex"`
+	Priority int    `json:"priority"` // new priority of the job, see: DlBase.Priority
 }
 
 func (b *DlAdminBody) InitWithQuery(query url.Values) {
 	b.ID = query.Get(URLParamID)
 	b.Regex = query.Get(URLParamRegex)
+	b.Priority, _ = strconv.Atoi(query.Get(URLParamPriority))
 }
 
 func (b *DlAdminBody) AsQuery() url.Values {
@@ -224,6 +475,9 @@ func (b *DlAdminBody) AsQuery() url.Values {
 	if b.Regex != "" {
 		query.Add(URLParamRegex, b.Regex)
 	}
+	if b.Priority != 0 {
+		query.Add(URLParamPriority, strconv.Itoa(b.Priority))
+	}
 	return query
 }
 
@@ -269,7 +523,7 @@ func (b *DlBody) Validate() error {
 }
 
 func (b *DlBody) String() string {
-	return fmt.Sprintf("%s, id=%q", b.DlBase, b.ID)
+	return fmt.Sprintf("%v, id=%q", b.DlBase.redacted(), b.ID)
 }
 
 type TaskInfoByName []TaskDlInfo
@@ -309,12 +563,20 @@ func (b *DlSingleBody) InitWithQuery(query url.Values) {
 	b.DlBase.InitWithQuery(query)
 	b.Link = query.Get(URLParamLink)
 	b.Objname = query.Get(URLParamObjName)
+	b.CksumValue = query.Get(URLParamCksumValue)
+	if b.CksumValue != "" {
+		b.DlObj.CksumType = query.Get(URLParamCksumType)
+	}
 }
 
 func (b *DlSingleBody) AsQuery() url.Values {
 	query := b.DlBase.AsQuery()
 	query.Add(URLParamLink, b.Link)
 	query.Add(URLParamObjName, b.Objname)
+	if b.CksumValue != "" {
+		query.Add(URLParamCksumType, b.DlObj.CksumType)
+		query.Add(URLParamCksumValue, b.CksumValue)
+	}
 	return query
 }
 
@@ -335,11 +597,11 @@ func (b *DlSingleBody) ExtractPayload() (SimpleKVs, error) {
 }
 
 func (b *DlSingleBody) Describe() string {
-	return fmt.Sprintf("%s -> %s/%s", b.Link, b.Bucket, b.Objname)
+	return fmt.Sprintf("%s -> %s/%s", RedactURL(b.Link), b.Bucket, b.Objname)
 }
 
 func (b *DlSingleBody) String() string {
-	return fmt.Sprintf("Link: %q, Bucket: %q, Objname: %q.", b.Link, b.Bucket, b.Objname)
+	return fmt.Sprintf("Link: %q, Bucket: %q, Objname: %q.", RedactURL(b.Link), b.Bucket, b.Objname)
 }
 
 // Range request
@@ -458,6 +720,78 @@ func (b *DlMultiBody) String() string {
 	return fmt.Sprintf("bucket: %q", b.Bucket)
 }
 
+// List request: links are read from the object stored in the cluster
+type DlListBody struct {
+	DlBase
+	ListBucket   string `json:"list_bucket"`   // defaults to the destination bucket
+	ListProvider string `json:"list_provider"` // defaults to the provider of the destination bucket
+	ListObjname  string `json:"list_objname"`
+	// Format of the object, see: DlListFormatTxt et al. If empty it is
+	// determined by the extension of the object (.csv, .jsonl) and
+	// defaults to plain text.
+	ListFormat string `json:"list_format"`
+}
+
+func (b *DlListBody) InitWithQuery(query url.Values) {
+	b.DlBase.InitWithQuery(query)
+	b.ListBucket = query.Get(URLParamListBucket)
+	b.ListProvider = query.Get(URLParamListProvider)
+	b.ListObjname = query.Get(URLParamListObjName)
+	b.ListFormat = query.Get(URLParamListFormat)
+}
+
+func (b *DlListBody) AsQuery() url.Values {
+	query := b.DlBase.AsQuery()
+	if b.ListBucket != "" {
+		query.Add(URLParamListBucket, b.ListBucket)
+	}
+	if b.ListProvider != "" {
+		query.Add(URLParamListProvider, b.ListProvider)
+	}
+	query.Add(URLParamListObjName, b.ListObjname)
+	if b.ListFormat != "" {
+		query.Add(URLParamListFormat, b.ListFormat)
+	}
+	return query
+}
+
+func (b *DlListBody) Validate() error {
+	if b.ListObjname == "" {
+		return fmt.Errorf("missing the %q which is required", URLParamListObjName)
+	}
+	if err := b.DlBase.Validate(); err != nil {
+		return err
+	}
+	if b.ListBucket == "" {
+		b.ListBucket, b.ListProvider = b.Bucket, b.Provider
+	}
+	if b.ListFormat == "" {
+		switch {
+		case strings.HasSuffix(b.ListObjname, ".csv"):
+			b.ListFormat = DlListFormatCSV
+		case strings.HasSuffix(b.ListObjname, ".jsonl"):
+			b.ListFormat = DlListFormatJSONL
+		default:
+			b.ListFormat = DlListFormatTxt
+		}
+	}
+	switch b.ListFormat {
+	case DlListFormatTxt, DlListFormatCSV, DlListFormatJSONL:
+	default:
+		return fmt.Errorf("invalid %q: %q (expected one of: %s, %s, %s)",
+			URLParamListFormat, b.ListFormat, DlListFormatTxt, DlListFormatCSV, DlListFormatJSONL)
+	}
+	return nil
+}
+
+func (b *DlListBody) Describe() string {
+	return fmt.Sprintf("list %s/%s -> %s", b.ListBucket, b.ListObjname, b.Bucket)
+}
+
+func (b *DlListBody) String() string {
+	return fmt.Sprintf("bucket: %q, list: %q/%q (format: %q)", b.Bucket, b.ListBucket, b.ListObjname, b.ListFormat)
+}
+
 // Cloud request
 type DlCloudBody struct {
 	DlBase
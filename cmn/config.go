@@ -9,6 +9,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -38,6 +39,9 @@ const (
 	// EC
 	MinSliceCount = 1  // minimum number of data or parity slices
 	MaxSliceCount = 32 // maximum number of data or parity slices
+
+	// Downloader
+	DefaultDlChunkConcurrency = 8 // concurrent chunks of a single object (if not configured)
 )
 
 const (
@@ -294,6 +298,45 @@ type MirrorConfToUpdate struct {
 	Enabled     *bool  `json:"enabled"`
 }
 
+// NotifConf defines delivery of the object events of the bucket to a webhook.
+// The events are delivered in batches (see ObjectEvents) by HTTP POST.
+type NotifConf struct {
+	URL          string `json:"url"`        // webhook URL
+	Events       string `json:"events"`     // comma-separated Notif* enum (empty: all events)
+	BatchSize    int64  `json:"batch_size"` // max number of events in a single POST
+	BatchTimeStr string `json:"batch_time"` // max time an event waits for the batch to fill up
+	Enabled      bool   `json:"enabled"`
+}
+
+type NotifConfToUpdate struct {
+	URL          *string `json:"url"`
+	Events       *string `json:"events"`
+	BatchSize    *int64  `json:"batch_size"`
+	BatchTimeStr *string `json:"batch_time"`
+	Enabled      *bool   `json:"enabled"`
+}
+
+// LifecycleConf defines the rules that get periodically applied to the
+// objects of the bucket depending on their age (see housekeep/lifecycle).
+// The age of an object is the time since it was written (PUT, cold GET).
+type LifecycleConf struct {
+	Prefix     string `json:"prefix"`      // comma-separated prefixes of the affected objects (empty: all objects)
+	ExpireDays int64  `json:"expire_days"` // delete ais objects older than so many days (0: never)
+	EvictDays  int64  `json:"evict_days"`  // evict cloud objects not accessed for so many days (0: never)
+	MirrorDays int64  `json:"mirror_days"` // mirror objects once they are so many days old instead of on PUT (0: on PUT)
+	ECDays     int64  `json:"ec_days"`     // erasure code objects once they are so many days old instead of on PUT (0: on PUT)
+	Enabled    bool   `json:"enabled"`
+}
+
+type LifecycleConfToUpdate struct {
+	Prefix     *string `json:"prefix"`
+	ExpireDays *int64  `json:"expire_days"`
+	EvictDays  *int64  `json:"evict_days"`
+	MirrorDays *int64  `json:"mirror_days"`
+	ECDays     *int64  `json:"ec_days"`
+	Enabled    *bool   `json:"enabled"`
+}
+
 type RahConf struct {
 	ObjectMem int64 `json:"object_mem"`
 	TotalMem  int64 `json:"total_mem"`
@@ -465,6 +508,7 @@ type NetConf struct {
 	IPv4IntraData    string   `json:"ipv4_intra_data"`
 	L4               L4Conf   `json:"l4"`
 	HTTP             HTTPConf `json:"http"`
+	TLS              TLSConf  `json:"tls"`
 	UseIntraControl  bool     `json:"-"`
 	UseIntraData     bool     `json:"-"`
 }
@@ -490,6 +534,16 @@ type HTTPConf struct {
 	RevProxyCache   bool   `json:"rproxy_cache"`       // RevProxy caches or work as transparent proxy
 	UseHTTPS        bool   `json:"use_https"`          // use HTTPS instead of HTTP
 	Chunked         bool   `json:"chunked_transfer"`   // https://tools.ietf.org/html/rfc7230#page-36
+	HTTP2           bool   `json:"http2"`              // multiplex intra-cluster data streams over HTTP/2 (h2c)
+}
+
+// TLSConf configures intra-cluster control and data networks (see also: ServerConfig and ClientConfig).
+type TLSConf struct {
+	Certificate string `json:"certificate"` // node's openssl certificate
+	Key         string `json:"key"`         // node's openssl key
+	CA          string `json:"ca"`          // CA certificate; when specified, peers must present certificates signed by it (mTLS)
+	HMACSecret  string `json:"hmac_secret"` // when specified, transport sessions are authenticated with HMAC-SHA256
+	Enabled     bool   `json:"enabled"`     // use HTTPS for intra-cluster networks
 }
 
 type FSHCConf struct {
@@ -524,6 +578,12 @@ type KeepaliveConf struct {
 type DownloaderConf struct {
 	TimeoutStr string        `json:"timeout"`
 	Timeout    time.Duration `json:"-"`
+	// Objects larger than the chunk size are downloaded in chunks (byte
+	// ranges) concurrently. Zero (or empty) disables chunked downloads.
+	ChunkSizeStr string `json:"chunk_size"`
+	ChunkSize    int64  `json:"-"`
+	// Max number of chunks of a single object downloaded concurrently.
+	ChunkConcurrency int `json:"chunk_concurrency"`
 }
 
 type DSortConf struct {
@@ -548,6 +608,14 @@ type CompressionConf struct {
 	Checksum     bool `json:"checksum"`   // true: checksum lz4 frames
 }
 
+// CompressionRules is the parsed compression enum (see CompressAlways, etc.) - a comma-separated
+// list of rules, e.g.: "always", "zstd", "ratio=1.5" or "zstd,ratio=1.5"
+type CompressionRules struct {
+	Alg      string  // LZ4Compression (default) or ZstdCompression
+	MinRatio float64 // adaptive: min ratio that warrants compression, zero - compress always
+	Enabled  bool
+}
+
 //==============================
 //
 // config functions
@@ -625,6 +693,12 @@ func LoadConfigErr(clivars *ConfigCLI) (config *Config, changed bool, err error)
 	if config.Net.IPv4IntraData != "" && config.Net.L4.PortIntraData != 0 && (differentIPs || differentPorts) {
 		config.Net.UseIntraData = true
 	}
+	if config.Net.TLS.Enabled && (!config.Net.UseIntraControl || !config.Net.UseIntraData) {
+		return nil, false, errors.New("net.tls requires separate intra-cluster control and data networks")
+	}
+	if config.Net.HTTP.HTTP2 && !config.Net.UseIntraData {
+		return nil, false, errors.New("net.http.http2 requires separate intra-cluster data network")
+	}
 
 	// CLI override
 	if clivars.StatsTime != 0 {
@@ -811,6 +885,105 @@ func (c *MirrorConf) ValidateAsProps(args *ValidationArgs) error {
 	return c.Validate(nil)
 }
 
+func (c *NotifConf) Validate(_ *Config) error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid notif.url: %q (expected absolute http(s) URL)", c.URL)
+	}
+	for _, event := range c.EventList() {
+		if !StringInSlice(event, NotifEvents) {
+			return fmt.Errorf("invalid notif.events: %q (expected one of: %v)", event, NotifEvents)
+		}
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("invalid notif.batch_size: %d (expected >0)", c.BatchSize)
+	}
+	if d, err := time.ParseDuration(c.BatchTimeStr); err != nil || d <= 0 {
+		return fmt.Errorf("invalid notif.batch_time: %q (expected positive duration)", c.BatchTimeStr)
+	}
+	return nil
+}
+
+func (c *NotifConf) ValidateAsProps(args *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	return c.Validate(nil)
+}
+
+// EventList returns the events to be delivered; empty list means all events.
+func (c *NotifConf) EventList() (events []string) {
+	for _, event := range strings.Split(c.Events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return
+}
+
+// Wants returns true if the event is to be delivered to the webhook.
+func (c *NotifConf) Wants(event string) bool {
+	events := c.EventList()
+	return len(events) == 0 || StringInSlice(event, events)
+}
+
+func (c *LifecycleConf) Validate(_ *Config) error {
+	for name, days := range map[string]int64{
+		"expire_days": c.ExpireDays, "evict_days": c.EvictDays, "mirror_days": c.MirrorDays, "ec_days": c.ECDays,
+	} {
+		if days < 0 {
+			return fmt.Errorf("invalid lifecycle.%s: %d (expected >=0)", name, days)
+		}
+	}
+	return nil
+}
+
+func (c *LifecycleConf) ValidateAsProps(args *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if err := c.Validate(nil); err != nil {
+		return err
+	}
+	if args.BckIsAIS && c.EvictDays > 0 {
+		return errors.New("lifecycle.evict_days applies only to cloud buckets")
+	}
+	if !args.BckIsAIS && c.ExpireDays > 0 {
+		return errors.New("lifecycle.expire_days applies only to ais buckets")
+	}
+	return nil
+}
+
+// PrefixList returns the prefixes of the affected objects; empty list means all objects.
+func (c *LifecycleConf) PrefixList() (prefixes []string) {
+	for _, prefix := range strings.Split(c.Prefix, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return
+}
+
+// Matches returns true if the rules apply to the object.
+func (c *LifecycleConf) Matches(objName string) bool {
+	prefixes := c.PrefixList()
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(objName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefersMirror returns true if the objects are mirrored by lifecycle rather than on PUT.
+func (c *LifecycleConf) DefersMirror() bool { return c.Enabled && c.MirrorDays > 0 }
+
+// DefersEC returns true if the objects are erasure coded by lifecycle rather than on PUT.
+func (c *LifecycleConf) DefersEC() bool { return c.Enabled && c.ECDays > 0 }
+
 func (c *ECConf) Validate(_ *Config) error {
 	if c.ObjSizeLimit < 0 {
 		return fmt.Errorf("invalid ec.obj_size_limit: %d (expected >=0)", c.ObjSizeLimit)
@@ -822,6 +995,9 @@ func (c *ECConf) Validate(_ *Config) error {
 	if c.ParitySlices < MinSliceCount || c.ParitySlices > MaxSliceCount {
 		return fmt.Errorf("invalid ec.parity_slices: %d (expected value in range [%d, %d])", c.ParitySlices, MinSliceCount, MaxSliceCount)
 	}
+	if _, err := ParseCompression(c.Compression); err != nil {
+		return fmt.Errorf("invalid ec.compression: %v", err)
+	}
 	return nil
 }
 
@@ -876,6 +1052,9 @@ func (c *RebalanceConf) Validate(_ *Config) (err error) {
 	if c.Quiesce, err = time.ParseDuration(c.QuiesceStr); err != nil {
 		return fmt.Errorf("invalid rebalance.quiesce format %s, err %v", c.QuiesceStr, err)
 	}
+	if _, err = ParseCompression(c.Compression); err != nil {
+		return fmt.Errorf("invalid rebalance.compression: %v", err)
+	}
 	return nil
 }
 
@@ -918,12 +1097,12 @@ func (c *NetConf) Validate(_ *Config) (err error) {
 	if c.L4.Port, err = ParsePort(c.L4.PortStr); err != nil {
 		return fmt.Errorf("invalid public port specified: %v", err)
 	}
-	if c.L4.PortIntraControl != 0 {
+	if c.L4.PortIntraControlStr != "" {
 		if c.L4.PortIntraControl, err = ParsePort(c.L4.PortIntraControlStr); err != nil {
 			return fmt.Errorf("invalid intra control port specified: %v", err)
 		}
 	}
-	if c.L4.PortIntraData != 0 {
+	if c.L4.PortIntraDataStr != "" {
 		if c.L4.PortIntraData, err = ParsePort(c.L4.PortIntraDataStr); err != nil {
 			return fmt.Errorf("invalid intra data port specified: %v", err)
 		}
@@ -953,6 +1132,20 @@ func (c *NetConf) Validate(_ *Config) (err error) {
 				c.HTTP.RevProxy, RevProxyCloud, RevProxyTarget)
 		}
 	}
+	if c.TLS.Enabled {
+		if c.TLS.Certificate == "" || c.TLS.Key == "" {
+			return errors.New("net.tls: certificate and key must be specified")
+		}
+		if _, err = c.TLS.ServerConfig(); err != nil {
+			return fmt.Errorf("net.tls: %v", err)
+		}
+	}
+	// the signature covers neither the payload nor a nonce: sent in the clear
+	// or to a server whose certificate is not verified (see: ClientConfig), a
+	// signed session can be captured and relayed while the timestamp is fresh
+	if c.TLS.HMACSecret != "" && (!c.TLS.Enabled || c.TLS.CA == "") {
+		return errors.New("net.tls: hmac_secret requires tls to be enabled with ca")
+	}
 	if !c.HTTP.Chunked {
 		glog.Warningln("disabled chunked transfer may cause a slow down (see also: Content-Length)")
 	}
@@ -963,6 +1156,15 @@ func (c *DownloaderConf) Validate(_ *Config) (err error) {
 	if c.Timeout, err = time.ParseDuration(c.TimeoutStr); err != nil {
 		return fmt.Errorf("invalid downloader.timeout %s", c.TimeoutStr)
 	}
+	if c.ChunkSize, err = S2B(c.ChunkSizeStr); err != nil || c.ChunkSize < 0 {
+		return fmt.Errorf("invalid downloader.chunk_size %s", c.ChunkSizeStr)
+	}
+	if c.ChunkConcurrency < 0 {
+		return fmt.Errorf("invalid downloader.chunk_concurrency %d", c.ChunkConcurrency)
+	}
+	if c.ChunkConcurrency == 0 {
+		c.ChunkConcurrency = DefaultDlChunkConcurrency
+	}
 	return nil
 }
 
@@ -989,6 +1191,9 @@ func (c *DSortConf) Validate(_ *Config) (err error) {
 	if _, err := S2B(c.DSorterMemThreshold); err != nil {
 		return fmt.Errorf("invalid distributed_sort.dsorter_mem_threshold: %s (err: %s)", c.DSorterMemThreshold, err)
 	}
+	if _, err := ParseCompression(c.Compression); err != nil {
+		return fmt.Errorf("invalid distributed_sort.compression: %v", err)
+	}
 	return nil
 }
 
@@ -1070,6 +1275,30 @@ func ValidateMpath(mpath string) (string, error) {
 	return cleanMpath, nil
 }
 
+func ParseCompression(s string) (rules CompressionRules, err error) {
+	rules.Alg = LZ4Compression
+	if s == "" || s == CompressNever {
+		return
+	}
+	rules.Enabled = true
+	for _, rule := range strings.Split(s, ",") {
+		rule = strings.TrimSpace(rule)
+		switch {
+		case rule == CompressAlways:
+		case rule == LZ4Compression || rule == ZstdCompression:
+			rules.Alg = rule
+		case strings.HasPrefix(rule, "ratio="):
+			if rules.MinRatio, err = strconv.ParseFloat(strings.TrimPrefix(rule, "ratio="), 64); err != nil || rules.MinRatio < 1 {
+				return rules, fmt.Errorf("invalid compression rule %q (expecting ratio >= 1)", rule)
+			}
+		default:
+			return rules, fmt.Errorf("invalid compression rule %q (expecting one of: %s, %s, %s, %s, %q)",
+				rule, CompressNever, CompressAlways, LZ4Compression, ZstdCompression, CompressRatio)
+		}
+	}
+	return
+}
+
 // NOTE: uncompressed block sizes - the enum currently supported by the github.com/pierrec/lz4
 func (c *CompressionConf) Validate(_ *Config) (err error) {
 	if c.BlockMaxSize != 64*KiB && c.BlockMaxSize != 256*KiB && c.BlockMaxSize != MiB && c.BlockMaxSize != 4*MiB {
@@ -0,0 +1,52 @@
+// Package cmn provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+// Dataset manifest is an optional object written into the output bucket by
+// the jobs which create many objects (shards), eg. dSort or downloader. It
+// lists all the created objects together with their content so that the
+// consumers (eg. training data loaders) do not need to rescan every shard.
+type (
+	DatasetManifest struct {
+		JobID    string           `json:"job_id"`
+		Kind     string           `json:"kind"` // kind of the job which has created the objects
+		Bucket   string           `json:"bucket"`
+		Provider string           `json:"provider"`
+		Shards   []*ManifestShard `json:"shards"`
+	}
+
+	// ManifestShard describes single object (shard) created by the job.
+	ManifestShard struct {
+		Name       string `json:"name"`
+		Size       int64  `json:"size,string"`
+		CksumType  string `json:"cksum_type,omitempty"`
+		CksumValue string `json:"cksum_value,omitempty"`
+		Version    string `json:"version,omitempty"`
+		// Records are known only if the job has created the shard from
+		// the records (eg. dSort). Otherwise they are omitted.
+		RecordCount int               `json:"record_count,omitempty"`
+		Records     []*ManifestRecord `json:"records,omitempty"`
+	}
+
+	// ManifestRecord describes single record of the shard. The record
+	// consists of objects with the same name but different extensions.
+	ManifestRecord struct {
+		Name    string               `json:"name"`
+		Objects []*ManifestRecordObj `json:"objects"`
+	}
+
+	ManifestRecordObj struct {
+		Extension string `json:"extension"`
+		Size      int64  `json:"size,string"`
+	}
+)
+
+// SetCksum fills the checksum of the shard, if known.
+func (s *ManifestShard) SetCksum(cksum *Cksum) {
+	if cksum == nil {
+		return
+	}
+	s.CksumType, s.CksumValue = cksum.Get()
+}
@@ -6,12 +6,17 @@ package cmn
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"strconv"
 	"syscall"
 	"time"
+
+	"golang.org/x/net/http2"
 )
 
 const (
@@ -20,6 +25,12 @@ const (
 	NetworkIntraData    = "intra_data"
 )
 
+// HTTP/2 flow control: max bytes the server buffers per connection and per stream
+const (
+	http2ConnBuffer   = 16 * MiB
+	http2StreamBuffer = 4 * MiB
+)
+
 var (
 	KnownNetworks = []string{NetworkPublic, NetworkIntraControl, NetworkIntraData}
 )
@@ -33,8 +44,10 @@ type (
 		MaxIdleConns     int
 		WriteBufferSize  int
 		ReadBufferSize   int
+		TLS              *tls.Config // when specified, overrides UseHTTPS
 		UseHTTPS         bool
 		UseHTTPProxyEnv  bool
+		HTTP2            bool // multiplex requests over HTTP/2 connections: h2c or, with TLS, h2
 	}
 )
 
@@ -82,7 +95,9 @@ func NewTransport(args TransportArgs) *http.Transport {
 		ReadBufferSize:        args.ReadBufferSize,
 		MaxIdleConns:          args.MaxIdleConns,
 	}
-	if args.UseHTTPS {
+	if args.TLS != nil {
+		transport.TLSClientConfig = args.TLS
+	} else if args.UseHTTPS {
 		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
 	if args.UseHTTPProxyEnv {
@@ -91,8 +106,57 @@ func NewTransport(args TransportArgs) *http.Transport {
 	return transport
 }
 
+// NewHTTP2Transport returns HTTP/2 transport that multiplexes all requests to a given
+// host over a single connection. Without TLS the connections are cleartext HTTP/2 (h2c)
+// with prior knowledge - the servers must support it (see NewHTTP2Server).
+func NewHTTP2Transport(args TransportArgs) *http2.Transport {
+	var (
+		dialTimeout = args.DialTimeout
+		transport   = &http2.Transport{}
+	)
+	if dialTimeout == 0 {
+		dialTimeout = 30 * time.Second
+	}
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if args.SndRcvBufSize > 0 {
+		dialer.Control = args.setSockOpt
+	}
+	if args.TLS != nil {
+		transport.TLSClientConfig = args.TLS
+	} else if args.UseHTTPS {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	if transport.TLSClientConfig != nil {
+		transport.DialTLS = func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return tls.DialWithDialer(dialer, network, addr, cfg)
+		}
+	} else {
+		transport.AllowHTTP = true
+		transport.DialTLS = func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dialer.Dial(network, addr)
+		}
+	}
+	return transport
+}
+
+// NewHTTP2Server returns HTTP/2 server configuration to serve the clients that use NewHTTP2Transport
+func NewHTTP2Server() *http2.Server {
+	return &http2.Server{
+		MaxUploadBufferPerConnection: http2ConnBuffer,
+		MaxUploadBufferPerStream:     http2StreamBuffer,
+	}
+}
+
 func NewClient(args TransportArgs) *http.Client {
-	transport := NewTransport(args)
+	var transport http.RoundTripper
+	if args.HTTP2 {
+		transport = NewHTTP2Transport(args)
+	} else {
+		transport = NewTransport(args)
+	}
 	client := &http.Client{
 		Transport: transport,
 		Timeout:   args.Timeout,
@@ -115,3 +179,84 @@ func (args *TransportArgs) ConnControl(c syscall.RawConn) (cntl func(fd uintptr)
 func (args *TransportArgs) setSockOpt(_, _ string, c syscall.RawConn) (err error) {
 	return c.Control(args.ConnControl(c))
 }
+
+// ServerConfig returns TLS configuration of intra-cluster servers: when CA is
+// specified the clients must present certificates signed by the CA.
+func (c *TLSConf) ServerConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.Certificate, c.Key)
+	if err != nil {
+		return nil, err
+	}
+	conf := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if c.CA != "" {
+		if conf.ClientCAs, err = c.caPool(); err != nil {
+			return nil, err
+		}
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return conf, nil
+}
+
+// ClientConfig returns TLS configuration of intra-cluster clients, nil if TLS
+// is disabled. When CA is specified the servers' certificates are verified
+// against the CA; host names are not verified since the nodes are addressed
+// by their IPs.
+func (c *TLSConf) ClientConfig() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.Certificate, c.Key)
+	if err != nil {
+		return nil, err
+	}
+	conf := &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		InsecureSkipVerify: true,
+	}
+	if c.CA != "" {
+		pool, err := c.caPool()
+		if err != nil {
+			return nil, err
+		}
+		conf.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyPeerCerts(rawCerts, pool)
+		}
+	}
+	return conf, nil
+}
+
+func (c *TLSConf) caPool() (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(c.CA)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates found in %q", c.CA)
+	}
+	return pool, nil
+}
+
+func verifyPeerCerts(rawCerts [][]byte, roots *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no peer certificates")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
@@ -62,3 +62,34 @@ func thisFileDir(t *testing.T) string {
 	tassert.Fatalf(t, ok, "Taking path of a file failed")
 	return filepath.Dir(filename)
 }
+
+func TestParseCompression(t *testing.T) {
+	var tests = []struct {
+		compression string
+		expected    cmn.CompressionRules
+		valid       bool
+	}{
+		{"", cmn.CompressionRules{Alg: cmn.LZ4Compression}, true},
+		{cmn.CompressNever, cmn.CompressionRules{Alg: cmn.LZ4Compression}, true},
+		{cmn.CompressAlways, cmn.CompressionRules{Alg: cmn.LZ4Compression, Enabled: true}, true},
+		{"zstd", cmn.CompressionRules{Alg: cmn.ZstdCompression, Enabled: true}, true},
+		{"ratio=1.5", cmn.CompressionRules{Alg: cmn.LZ4Compression, MinRatio: 1.5, Enabled: true}, true},
+		{"zstd, ratio=2", cmn.CompressionRules{Alg: cmn.ZstdCompression, MinRatio: 2, Enabled: true}, true},
+		{"ratio=0.5", cmn.CompressionRules{}, false},
+		{"ratio=abc", cmn.CompressionRules{}, false},
+		{"gzip", cmn.CompressionRules{}, false},
+	}
+	for _, test := range tests {
+		rules, err := cmn.ParseCompression(test.compression)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("expected error for compression %q", test.compression)
+			}
+			continue
+		}
+		tassert.CheckError(t, err)
+		if rules != test.expected {
+			t.Errorf("compression %q: expected %+v, got %+v", test.compression, test.expected, rules)
+		}
+	}
+}
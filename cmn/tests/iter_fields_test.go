@@ -77,6 +77,19 @@ var _ = Describe("IterFields", func() {
 					"lru.dont_evict_time":   "",
 					"lru.capacity_upd_time": "",
 
+					"notif.url":        "",
+					"notif.events":     "",
+					"notif.batch_size": int64(0),
+					"notif.batch_time": "",
+					"notif.enabled":    false,
+
+					"lifecycle.prefix":      "",
+					"lifecycle.expire_days": int64(0),
+					"lifecycle.evict_days":  int64(0),
+					"lifecycle.mirror_days": int64(0),
+					"lifecycle.ec_days":     int64(0),
+					"lifecycle.enabled":     false,
+
 					"aattrs": uint64(0),
 					"bid":    uint64(0),
 				},
@@ -16,6 +16,8 @@ const (
 	gsAPIPathPrefix = "/storage/v1"
 
 	s3UrlRegex = `(s3-|s3\.)?(.*)\.amazonaws\.com`
+
+	redacted = "xxxxx"
 )
 
 func IsGoogleStorageURL(u *url.URL) bool {
@@ -30,3 +32,24 @@ func IsS3URL(link string) bool {
 	re := regexp.MustCompile(s3UrlRegex)
 	return re.MatchString(link)
 }
+
+// RedactURL replaces the credentials (user info) and the values of the query
+// parameters (eg. signature of a signed URL) so that the link can be logged
+// or shown to the user.
+func RedactURL(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return redacted
+	}
+	if u.User != nil {
+		u.User = url.User(redacted)
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query[key] = []string{redacted}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
@@ -0,0 +1,250 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// ================================ Chunks =====================================
+//
+// Objects which are larger than the chunk size (see: cmn.DownloaderConf and
+// cmn.DlBase) are split into chunks (byte ranges) which are downloaded
+// concurrently and written directly into the partial file at their offsets.
+// The decision is made after the first response: chunks are used only if the
+// source supports ranges (`Accept-Ranges: bytes`) and provides the validator
+// (ETag or Last-Modified) which guarantees that all the chunks come from the
+// same version of the object.
+//
+// Progress of each chunk is persisted together with the resume info so that
+// the chunked download can be resumed as well (see: resume.go). The object is
+// checksummed as a whole when the partial file is put into the cluster.
+//
+// ================================ Chunks =====================================
+
+var (
+	// errUseChunks is returned when the object should be downloaded in chunks.
+	errUseChunks = errors.New("download in chunks")
+	// errSourceChanged is returned when the chunk cannot be downloaded because
+	// the object has changed (or the source stopped supporting ranges).
+	errSourceChanged = errors.New("source does not serve the requested range")
+)
+
+type (
+	// resumeChunk describes the byte range [Start, End) of the object and the
+	// number of bytes of the range which are already downloaded (and synced).
+	resumeChunk struct {
+		Start int64 `json:"start,string"`
+		End   int64 `json:"end,string"`
+		Done  int64 `json:"done,string"`
+	}
+
+	offsetWriter struct {
+		f   *os.File
+		off int64
+	}
+)
+
+func (w *offsetWriter) Write(b []byte) (n int, err error) {
+	n, err = w.f.WriteAt(b, w.off)
+	w.off += int64(n)
+	return
+}
+
+func (c *resumeChunk) finished() bool { return c.Start+c.Done >= c.End }
+
+func splitChunks(size, chunkSize int64) []*resumeChunk {
+	chunks := make([]*resumeChunk, 0, (size+chunkSize-1)/chunkSize)
+	for start := int64(0); start < size; start += chunkSize {
+		end := start + chunkSize
+		if end > size {
+			end = size
+		}
+		chunks = append(chunks, &resumeChunk{Start: start, End: end})
+	}
+	return chunks
+}
+
+// useChunks determines if the object which the response is for should be
+// downloaded in chunks.
+func (t *singleObjectTask) useChunks(resp *http.Response, ri *resumeInfo) bool {
+	chunkSize := t.chunkSize
+	if chunkSize == 0 {
+		chunkSize = cmn.GCO.Get().Downloader.ChunkSize
+	}
+	if chunkSize == 0 || ri.Size <= chunkSize || ri.Validator == "" {
+		return false
+	}
+	if resp.Header.Get("Accept-Ranges") != "bytes" {
+		return false
+	}
+	ri.Chunks = splitChunks(ri.Size, chunkSize)
+	return true
+}
+
+// downloadChunks downloads all the chunks of the object which are not yet
+// finished and writes them into the partial file.
+func (t *singleObjectTask) downloadChunks(part *partFile, ri *resumeInfo) (errMsg string, err error) {
+	var (
+		mtx     sync.Mutex
+		wg      = &sync.WaitGroup{}
+		chunkCh = make(chan *resumeChunk, len(ri.Chunks))
+		errCh   = make(chan error, len(ri.Chunks))
+	)
+	file, err := part.open()
+	if err != nil {
+		return internalErrorMessage(), err
+	}
+	if err = file.Truncate(ri.Size); err != nil {
+		return internalErrorMessage(), err
+	}
+
+	done := int64(0)
+	for _, chunk := range ri.Chunks {
+		done += chunk.Done
+		if !chunk.finished() {
+			chunkCh <- chunk
+		}
+	}
+	close(chunkCh)
+	t.currentSize.Store(done)
+	t.totalSize = ri.Size
+
+	// Persists the progress of the chunk - must be called after the data
+	// has been synced.
+	progress := func(chunk *resumeChunk, n int64) {
+		mtx.Lock()
+		chunk.Done += n
+		dlStore.saveResumeInfo(ri)
+		mtx.Unlock()
+	}
+
+	concurrency := cmn.GCO.Get().Downloader.ChunkConcurrency
+	if concurrency == 0 {
+		concurrency = cmn.DefaultDlChunkConcurrency
+	}
+	concurrency = cmn.Min(concurrency, len(chunkCh))
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf, slab := memsys.GMM().AllocDefault()
+			defer slab.Free(buf)
+			for chunk := range chunkCh {
+				if err := t.downloadChunk(file, chunk, ri.Validator, buf, progress); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+
+	if err = <-errCh; err != nil {
+		errMsg = limiterErrorMessage(err)
+		return
+	}
+	return "", nil
+}
+
+// downloadChunk downloads the rest of the chunk. Every attempt continues
+// where the previous one has stopped; the attempts are counted as retries only
+// when there is no progress at all.
+func (t *singleObjectTask) downloadChunk(file *os.File, chunk *resumeChunk, validator string, buf []byte, progress func(*resumeChunk, int64)) (err error) {
+	throttledCnt := 0
+	for i, attempt := 0, 0; i < retryCnt && attempt < maxAttemptCnt && !chunk.finished(); i, attempt = i+1, attempt+1 {
+		var written int64
+		written, err = t.downloadChunkRange(file, chunk, validator, buf, progress)
+		if err == nil || err == errSourceChanged || t.downloadCtx.Err() != nil {
+			return
+		}
+		if retryThrottled(err, &throttledCnt) {
+			i--
+			continue
+		}
+		if written > 0 {
+			i = -1
+		}
+		glog.Warningf("failed to download chunk [%d, %d) of %s (resuming at %d), err: %v",
+			chunk.Start, chunk.End, t, chunk.Start+chunk.Done, err)
+	}
+	return
+}
+
+func (t *singleObjectTask) downloadChunkRange(file *os.File, chunk *resumeChunk, validator string, buf []byte, progress func(*resumeChunk, int64)) (written int64, err error) {
+	var (
+		req   *http.Request
+		resp  *http.Response
+		start = chunk.Start + chunk.Done
+	)
+	if req, err = t.newRequest(); err != nil {
+		return
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, chunk.End-1))
+	req.Header.Set("If-Range", validator)
+
+	if resp, err = t.limiter.do(t.downloadCtx, req); err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		return 0, fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		// The object has changed in the meantime (or the source does not
+		// support ranges after all) - the chunks cannot be used.
+		return 0, errSourceChanged
+	}
+	var rangeStart int64
+	if rangeStart, err = contentRangeStart(resp); err != nil {
+		return
+	}
+	if rangeStart != start {
+		return 0, fmt.Errorf("unexpected Content-Range start: %d (expected: %d)", rangeStart, start)
+	}
+
+	var read int64
+	progressReader := &progressReader{
+		r: io.LimitReader(resp.Body, chunk.End-start),
+		reporter: func(n int64) {
+			read += n
+			t.currentSize.Add(n)
+		},
+	}
+	// The data which has not been synced is downloaded again.
+	defer func() {
+		t.currentSize.Sub(read - written)
+	}()
+	for {
+		var n int64
+		w := &offsetWriter{f: file, off: chunk.Start + chunk.Done}
+		n, err = io.CopyBuffer(w, io.LimitReader(progressReader, resumeSyncInterval), buf)
+		if n > 0 {
+			if errSync := file.Sync(); errSync != nil {
+				return written, errSync
+			}
+			written += n
+			progress(chunk, n)
+		}
+		if err != nil || n < resumeSyncInterval {
+			break
+		}
+	}
+	if err == nil && !chunk.finished() {
+		err = io.ErrUnexpectedEOF
+	}
+	return
+}
@@ -0,0 +1,159 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/OneOfOne/xxhash"
+)
+
+// ================================ Checksums ==================================
+//
+// The expected checksums of the objects can be supplied either with each
+// object (see: cmn.DlObj) or in the list of checksums stored in the bucket
+// (see: cmn.DlBase.CksumList) in the format of `sha256sum` output:
+//
+//   <hex checksum> <space or '*'><objname>
+//
+// Each target reads the list when the job is started (and before each run of
+// the sync job) and keeps the checksums of the objects which belong to it. Downloaded objects are verified against
+// the expected checksums: objects downloaded from links before they are
+// stored in the bucket, objects from the cloud right after they were stored
+// (and removed on mismatch). Mismatches are reported as errors of the job.
+//
+// ================================ Checksums ==================================
+
+// expectedCksum returns the checksum which the downloaded object should have,
+// empty value if none.
+func expectedCksum(jInfo *DownloadJobInfo, obj cmn.DlObj) (ty, value string) {
+	if obj.CksumValue != "" {
+		return obj.CksumType, obj.CksumValue
+	}
+	if jInfo == nil {
+		return "", ""
+	}
+	dlStore.RLock()
+	defer dlStore.RUnlock()
+	if value, ok := jInfo.cksums[obj.Objname]; ok {
+		return jInfo.cksumType, value
+	}
+	return "", ""
+}
+
+// loadCksums reads the list of checksums of the job and returns the checksums
+// of the objects which belong to this target.
+func (d *Downloader) loadCksums(job DlJob) (map[string]string, error) {
+	objname, _ := job.CksumList()
+	bck := &cluster.Bck{Name: job.Bucket(), Provider: job.Provider()}
+	r, err := openObject(d.t, bck, objname, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checksums %s/%s, err: %v", job.Bucket(), objname, err)
+	}
+	defer r.Close()
+	smap := d.t.GetSowner().Get()
+	cksums, err := parseCksums(r, func(name string) bool {
+		si, err := cluster.HrwTarget(bck.MakeUname(name), smap)
+		return err == nil && si.ID() == d.t.Snode().ID()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checksums %s/%s, err: %v", job.Bucket(), objname, err)
+	}
+	return cksums, nil
+}
+
+func (is *infoStore) setCksums(job DlJob, cksums map[string]string) {
+	jInfo, err := is.getJob(job.ID())
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	_, ty := job.CksumList()
+	is.Lock()
+	jInfo.cksumType, jInfo.cksums = ty, cksums
+	is.Unlock()
+}
+
+// parseCksums parses the list of checksums and returns the checksums of the
+// objects accepted by the filter (objname -> checksum).
+func parseCksums(r io.Reader, filter func(name string) bool) (map[string]string, error) {
+	var (
+		cksums  = make(map[string]string, 64)
+		scanner = bufio.NewScanner(r)
+		lineNum int
+	)
+	scanner.Buffer(nil, listPageSize)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		idx := strings.IndexAny(line, " \t")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid line %d: expected checksum and name", lineNum)
+		}
+		value := strings.ToLower(line[:idx])
+		if _, err := hex.DecodeString(value); err != nil {
+			return nil, fmt.Errorf("invalid line %d: invalid checksum %q", lineNum, line[:idx])
+		}
+		name := strings.TrimLeft(line[idx:], " \t")
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "*"), "./")
+		if name == "" {
+			return nil, fmt.Errorf("invalid line %d: missing name", lineNum)
+		}
+		if filter(name) {
+			cksums[name] = value
+		}
+	}
+	return cksums, scanner.Err()
+}
+
+func newCksumHash(ty string) hash.Hash {
+	switch ty {
+	case cmn.ChecksumMD5:
+		return md5.New()
+	case cmn.ChecksumSHA256:
+		return sha256.New()
+	case cmn.ChecksumCRC32C:
+		return cmn.NewCRC32C()
+	default:
+		cmn.Assert(ty == cmn.ChecksumXXHash)
+		return xxhash.New64()
+	}
+}
+
+// verifyCksum computes the checksum of the file and compares it with
+// the expected one.
+func verifyCksum(fqn, ty, expected string) error {
+	file, err := os.Open(fqn)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	h := newCksumHash(ty)
+	buf, slab := memsys.GMM().AllocDefault()
+	defer slab.Free(buf)
+	if _, err := io.CopyBuffer(h, file, buf); err != nil {
+		return err
+	}
+	if actual := cmn.HashToStr(h); actual != expected {
+		return fmt.Errorf("%s checksum mismatch: expected %s, got %s", ty, expected, actual)
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/sdomino/scribble"
 )
 
@@ -19,6 +20,9 @@ const (
 	persistDownloaderJobsPath = "downloader_jobs.db" // base name to persist downloader jobs' file
 	downloaderErrors          = "errors"
 	downloaderTasks           = "tasks"
+	downloaderResume          = "resume"
+	downloaderSync            = "sync"
+	downloaderSyncJobs        = "sync_jobs"
 
 	// Number of errors stored in memory. When the number of errors exceeds
 	// this number, then all errors will be flushed to disk
@@ -182,5 +186,108 @@ func (db *downloaderDB) delete(id string) {
 	db.mtx.Lock()
 	db.driver.Delete(downloaderErrors, id)
 	db.driver.Delete(downloaderTasks, id)
+	delete(db.errCache, id)
+	delete(db.taskInfoCache, id)
 	db.mtx.Unlock()
 }
+
+func (db *downloaderDB) getResumeInfo(key string, ri *resumeInfo) error {
+	return db.driver.Read(downloaderResume, key, ri)
+}
+
+func (db *downloaderDB) persistResumeInfo(key string, ri *resumeInfo) error {
+	return db.driver.Write(downloaderResume, key, ri)
+}
+
+func (db *downloaderDB) deleteResumeInfo(key string) {
+	db.driver.Delete(downloaderResume, key)
+}
+
+func (db *downloaderDB) getSyncInfo(key string, si *syncInfo) error {
+	return db.driver.Read(downloaderSync, key, si)
+}
+
+func (db *downloaderDB) persistSyncInfo(key string, si *syncInfo) error {
+	return db.driver.Write(downloaderSync, key, si)
+}
+
+func (db *downloaderDB) deleteSyncInfo(key string) {
+	db.driver.Delete(downloaderSync, key)
+}
+
+func (db *downloaderDB) getAllResumeInfo() ([]*resumeInfo, error) {
+	records, err := db.driver.ReadAll(downloaderResume)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	infos := make([]*resumeInfo, 0, len(records))
+	for _, record := range records {
+		ri := &resumeInfo{}
+		if err := jsoniter.UnmarshalFromString(record, ri); err != nil {
+			glog.Error(err)
+			continue
+		}
+		infos = append(infos, ri)
+	}
+	return infos, nil
+}
+
+func (db *downloaderDB) persistSyncJob(sj *SyncJob) (err error) {
+	if len(sj.Request.Header) > 0 {
+		sealed := *sj
+		if sealed.Request.SealedHeader, err = sealHeader(sj.Request.Header); err != nil {
+			return
+		}
+		sj = &sealed
+	}
+	return db.driver.Write(downloaderSyncJobs, sj.ID, sj)
+}
+
+// updateSyncJob updates the persisted sync job, if any. The headers are kept
+// sealed as they have been persisted.
+func (db *downloaderDB) updateSyncJob(id string, update func(sj *SyncJob)) error {
+	sj := &SyncJob{}
+	if err := db.driver.Read(downloaderSyncJobs, id, sj); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	update(sj)
+	return db.driver.Write(downloaderSyncJobs, id, sj)
+}
+
+func (db *downloaderDB) deleteSyncJob(id string) {
+	db.driver.Delete(downloaderSyncJobs, id)
+}
+
+func (db *downloaderDB) getAllSyncJobs() ([]*SyncJob, error) {
+	records, err := db.driver.ReadAll(downloaderSyncJobs)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	jobs := make([]*SyncJob, 0, len(records))
+	for _, record := range records {
+		sj := &SyncJob{}
+		if err := jsoniter.UnmarshalFromString(record, sj); err != nil {
+			glog.Error(err)
+			continue
+		}
+		if len(sj.Request.SealedHeader) > 0 {
+			header, err := openHeader(sj.Request.SealedHeader)
+			if err != nil {
+				glog.Errorf("failed to decrypt headers of sync download job %s, err: %v", sj.ID, err)
+				continue
+			}
+			sj.Request.Header, sj.Request.SealedHeader = header, nil
+		}
+		jobs = append(jobs, sj)
+	}
+	return jobs, nil
+}
@@ -26,8 +26,14 @@ type (
 
 		joggers  map[string]*jogger       // mpath -> jogger
 		abortJob map[string]chan struct{} // jobID -> abort job chan
+		syncJob  map[string]chan struct{} // jobID -> stop chan of the scheduled run, see: sync.go
+
+		// jobs which are being dispatched, accessed only by run(), see: priority.go
+		jobs  map[string]*dispatchJob
+		vtime uint64 // pass of the most recently dispatched job
 
 		dispatchDownloadCh chan DlJob
+		wakeCh             chan struct{} // signals that a job has been resumed or aborted
 
 		stopCh cmn.StopCh
 		sync.RWMutex
@@ -39,9 +45,13 @@ func newDispatcher(parent *Downloader) *dispatcher {
 		parent:  parent,
 		joggers: make(map[string]*jogger, 8),
 
+		jobs: make(map[string]*dispatchJob, 8),
+
 		dispatchDownloadCh: make(chan DlJob, jobsChSize),
+		wakeCh:             make(chan struct{}, 1),
 		stopCh:             cmn.NewStopCh(),
 		abortJob:           make(map[string]chan struct{}, jobsChSize),
+		syncJob:            make(map[string]chan struct{}, jobsChSize),
 	}
 }
 
@@ -56,14 +66,29 @@ func (d *dispatcher) init() {
 
 func (d *dispatcher) run() {
 	for {
-		select {
-		case job := <-d.dispatchDownloadCh:
-			if !d.dispatchDownload(job) {
-				// stop dispatcher if aborted
+		dJob := d.nextJob()
+		if dJob == nil {
+			// nothing to dispatch (or all jobs are paused)
+			select {
+			case job := <-d.dispatchDownloadCh:
+				d.addJob(job)
+			case <-d.wakeCh:
+			case <-d.stopCh.Listen():
 				d.stop()
 				return
 			}
-		case <-d.stopCh.Listen():
+			continue
+		}
+
+		select {
+		case job := <-d.dispatchDownloadCh:
+			d.addJob(job)
+			continue
+		default:
+		}
+
+		if !d.dispatchNext(dJob) {
+			// stop dispatcher if aborted
 			d.stop()
 			return
 		}
@@ -77,6 +102,9 @@ func (d *dispatcher) Abort() {
 // stop running joggers
 // no need to cleanup maps, dispatcher should not be used after stop()
 func (d *dispatcher) stop() {
+	for _, dJob := range d.jobs {
+		d.finishJob(dJob)
+	}
 	for _, jogger := range d.joggers {
 		jogger.stop()
 	}
@@ -103,6 +131,11 @@ func (d *dispatcher) cleanUpAborted(jobID string) {
 	d.Unlock()
 }
 
+// wake wakes up the dispatcher which waits for a job to dispatch.
+func (d *dispatcher) wake() {
+	notify(d.wakeCh)
+}
+
 func (d *dispatcher) ScheduleForDownload(job DlJob) {
 	d.Lock()
 	d.abortJob[job.ID()] = make(chan struct{}, 1)
@@ -115,40 +148,69 @@ func (d *dispatcher) ScheduleForDownload(job DlJob) {
  * dispatcher's dispatch methods (forwards request to jogger)
  */
 
-func (d *dispatcher) dispatchDownload(job DlJob) (ok bool) {
-	defer func() {
-		dlStore.markFinished(job.ID())
-		dlStore.flush(job.ID())
-		d.cleanUpAborted(job.ID())
-	}()
+func (d *dispatcher) addJob(job DlJob) {
+	// The job starts at the current virtual time, see: priority.go.
+	d.jobs[job.ID()] = &dispatchJob{job: job, pass: d.vtime}
+	// Keep the downloader running while the job is being dispatched (even
+	// if it is paused and has no pending tasks).
+	d.parent.IncPending()
+}
 
-	if aborted := d.checkAborted(); aborted || d.checkAbortedJob(job) {
-		return !aborted
+func (d *dispatcher) finishJob(dJob *dispatchJob) {
+	job := dJob.job
+	delete(d.jobs, job.ID())
+	dlStore.markFinished(job.ID())
+	dlStore.flush(job.ID())
+	d.cleanUpAborted(job.ID())
+	if job.SyncInterval() > 0 {
+		d.scheduleSync(job)
 	}
+	d.parent.DecPending()
+}
 
-	for {
-		objs, ok := job.GenNext()
-		if !ok {
-			_ = dlStore.setAllDispatched(job.ID(), true)
-			return true
+// nextJob returns the job with the lowest pass which is not paused, nil if
+// there is none. Aborted jobs are finished in the meantime.
+func (d *dispatcher) nextJob() (next *dispatchJob) {
+	for id, dJob := range d.jobs {
+		if d.checkAbortedJob(dJob.job) {
+			d.finishJob(dJob)
+			continue
 		}
+		if dlStore.isPaused(id) {
+			continue
+		}
+		if next == nil || lessPass(dJob.pass, id, next.pass, next.job.ID()) {
+			next = dJob
+		}
+	}
+	return next
+}
 
-		for _, obj := range objs {
-			if aborted := d.checkAborted(); aborted || d.checkAbortedJob(job) {
-				return !aborted
-			}
+// dispatchNext dispatches the next object of the job, returns false if the
+// dispatcher was aborted in the meantime.
+func (d *dispatcher) dispatchNext(dJob *dispatchJob) (ok bool) {
+	if d.checkAborted() {
+		return false
+	}
 
-			err, ok := d.blockingDispatchDownloadSingle(job, obj)
-			if err != nil {
-				glog.Errorf("Download job %s failed, couldn't download object %s, aborting; %s", job.ID(), obj.Link, err.Error())
-				cmn.AssertNoErr(dlStore.setAborted(job.ID()))
-				return ok
-			}
-			if !ok {
-				return false
-			}
-		}
+	job := dJob.job
+	obj, ok := dJob.next()
+	if !ok {
+		_ = dlStore.setAllDispatched(job.ID(), true)
+		d.parent.checkManifest(job.ID())
+		d.finishJob(dJob)
+		return true
+	}
+	d.vtime = dJob.pass
+	dJob.pass += jobStride(job.ID())
+
+	err, ok := d.blockingDispatchDownloadSingle(job, obj)
+	if err != nil {
+		glog.Errorf("Download job %s failed, couldn't download object %s, aborting; %s", job.ID(), cmn.RedactURL(obj.Link), err.Error())
+		cmn.AssertNoErr(dlStore.setAborted(job.ID()))
+		d.finishJob(dJob)
 	}
+	return ok
 }
 
 func (d *dispatcher) jobAbortedCh(jobID string) <-chan struct{} {
@@ -192,15 +254,16 @@ func (d *dispatcher) createTasksLom(job DlJob, obj cmn.DlObj) (*cluster.LOM, err
 	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
-	if err == nil { // TODO -- FIXME: check version
+	if err == nil && (job.SyncInterval() == 0 || !objChanged(obj, lom)) {
 		if glog.V(4) {
 			glog.Infof("object %q already exists - skipping", obj.Objname)
 		}
+		dlStore.addManifestShard(job.ID(), lom)
 		return nil, nil
 	}
 
 	if lom.ParsedFQN.MpathInfo == nil {
-		err = fmt.Errorf("download task for %s failed. Failed to get mountpath for the request's fqn %s", obj.Link, lom.FQN)
+		err = fmt.Errorf("download task for %s failed. Failed to get mountpath for the request's fqn %s", cmn.RedactURL(obj.Link), lom.FQN)
 		glog.Error(err)
 		return nil, err
 	}
@@ -219,8 +282,18 @@ func (d *dispatcher) prepareTask(job DlJob, obj cmn.DlObj) (*singleObjectTask, *
 			provider: job.Provider(),
 			timeout:  job.Timeout(),
 		},
+		chunkSize:  job.ChunkSize(),
+		headers:    job.Headers(),
+		sync:       job.SyncInterval() > 0,
 		finishedCh: make(chan error, 1),
 	}
+	if jInfo, err := dlStore.getJob(job.ID()); err == nil {
+		t.limiter = jInfo.limiter
+		t.cksumType, t.cksumValue = expectedCksum(jInfo, obj)
+	} else {
+		t.limiter = newJobLimiter(job, d.parent.targetCnt(job))
+		t.cksumType, t.cksumValue = expectedCksum(nil, obj)
+	}
 
 	lom, err := d.createTasksLom(job, obj)
 	if err != nil {
@@ -254,16 +327,18 @@ func (d *dispatcher) blockingDispatchDownloadSingle(job DlJob, obj cmn.DlObj) (e
 		return err, true
 	}
 
-	select {
-	// FIXME: if this particular jogger is full, but others are available, dispatcher
-	// will wait with dispatching all of the requests anyway
-	case jogger.putCh(task) <- task:
-		return nil, true
-	case <-d.jobAbortedCh(job.ID()):
-		return nil, true
-	case <-d.stopCh.Listen():
-		return nil, false
+	for !jogger.put(task) {
+		select {
+		// FIXME: if this particular jogger is full, but others are available, dispatcher
+		// will wait with dispatching all of the requests anyway
+		case <-jogger.q.spaceCh:
+		case <-d.jobAbortedCh(job.ID()):
+			return nil, true
+		case <-d.stopCh.Listen():
+			return nil, false
+		}
 	}
+	return nil, true
 }
 
 func (d *dispatcher) dispatchRemove(req *request) {
@@ -277,7 +352,12 @@ func (d *dispatcher) dispatchRemove(req *request) {
 		req.writeErrResp(fmt.Errorf("download job with id = %s is still running", jInfo.ID), http.StatusBadRequest)
 		return
 	}
+	if !d.parent.Aborted() && jInfo.Paused.Load() && !jInfo.Aborted.Load() && !jInfo.AllDispatched.Load() {
+		req.writeErrResp(fmt.Errorf("download job with id = %s is paused", jInfo.ID), http.StatusBadRequest)
+		return
+	}
 
+	d.cancelSync(req.id)
 	dlStore.delJob(req.id)
 	req.writeResp(nil)
 }
@@ -287,6 +367,7 @@ func (d *dispatcher) dispatchAbort(req *request) {
 	if err != nil {
 		return
 	}
+	d.cancelSync(req.id)
 
 	for _, j := range d.joggers {
 		j.Lock()
@@ -298,16 +379,63 @@ func (d *dispatcher) dispatchAbort(req *request) {
 		}
 
 		// Remove all pending tasks from queue
-		if m, ok := j.q.m[req.id]; ok {
-			d.parent.SubPending(int64(len(m)))
-			delete(j.q.m, req.id)
-		}
+		d.parent.SubPending(int64(j.q.removeJob(req.id)))
 
 		j.Unlock()
 	}
 
 	err = dlStore.setAborted(req.id)
 	cmn.AssertNoErr(err) // Everything should be okay since getReqFromDB
+
+	// Stop dispatching the job
+	d.Lock()
+	if abCh, ok := d.abortJob[req.id]; ok {
+		close(abCh)
+		delete(d.abortJob, req.id)
+	}
+	d.Unlock()
+	d.wake()
+	req.writeResp(nil)
+}
+
+func (d *dispatcher) dispatchPause(req *request) {
+	d.setPaused(req, true)
+}
+
+func (d *dispatcher) dispatchResume(req *request) {
+	d.setPaused(req, false)
+}
+
+// setPaused pauses or resumes the job, the tasks which are being downloaded
+// are not interrupted (see: priority.go).
+func (d *dispatcher) setPaused(req *request, paused bool) {
+	jInfo, err := d.parent.checkJob(req)
+	if err != nil {
+		return
+	}
+	if jInfo.Aborted.Load() {
+		req.writeErrResp(fmt.Errorf("download job with id = %s has been aborted", jInfo.ID), http.StatusBadRequest)
+		return
+	}
+
+	err = dlStore.setPaused(req.id, paused)
+	cmn.AssertNoErr(err) // Everything should be okay since checkJob
+	for _, j := range d.joggers {
+		j.q.setPaused(req.id, paused)
+	}
+	if !paused {
+		d.wake()
+	}
+	req.writeResp(nil)
+}
+
+func (d *dispatcher) dispatchPriority(req *request) {
+	if _, err := d.parent.checkJob(req); err != nil {
+		return
+	}
+
+	err := dlStore.setPriority(req.id, req.priority)
+	cmn.AssertNoErr(err) // Everything should be okay since checkJob
 	req.writeResp(nil)
 }
 
@@ -344,6 +472,9 @@ func (d *dispatcher) dispatchStatus(req *request) {
 		Aborted:       jInfo.Aborted.Load(),
 		AllDispatched: jInfo.AllDispatched.Load(),
 		Scheduled:     int(jInfo.ScheduledCnt.Load()),
+		NextSync:      jInfo.NextSync.Load(),
+		Paused:        jInfo.Paused.Load(),
+		Priority:      int(jInfo.Priority.Load()),
 	})
 }
 
@@ -357,6 +488,8 @@ func (d *dispatcher) dispatchList(req *request) {
 			NumErrors:   int(r.ErrorCnt.Load()),
 			NumPending:  d.parent.getNumPending(r.ID),
 			Aborted:     r.Aborted.Load(),
+			Paused:      r.Paused.Load(),
+			Priority:    int(r.Priority.Load()),
 		}
 	}
 
@@ -39,6 +39,8 @@ import (
 //   * Stop     - to stop
 //   * Download    - to download a new object from a URL
 //   * Abort       - to abort a previously requested download (currently queued or currently downloading)
+//   * Pause       - to pause a previously requested download (see: Resume)
+//   * Priority    - to change the priority of a previously requested download
 //   * Status      - to request the status of a previously requested download
 // The Download, Abort and Status requests are encapsulated into an internal
 // request object, added to a dispatcher's request queue and then are dispatched by dispatcher
@@ -46,18 +48,20 @@ import (
 // are used only internally. Dispatcher is implemented as goroutine listening for
 // incoming requests from Downloader
 //
-// Each jogger, which corresponds to one mountpath, has a download channel
-// (downloadCh) where download requests, that are dispatched from Dispatcher, are
-// queued. Thus, downloads occur on a per-mountpath basis and are handled one at
-// a time by jogger as they arrive.
+// Each jogger, which corresponds to one mountpath, has a download queue where
+// download requests, that are dispatched from Dispatcher, are queued per job.
+// Thus, downloads occur on a per-mountpath basis and are handled one at a time
+// by jogger in the order given by the priorities of the jobs (see: priority.go).
 //
 // ====== Downloading ======
 //
 // After Downloader received a download job, it sends the job to Dispatcher.
-// Dispatcher processes one job at the time, extracting objects to download
-// from job in batches. When joggers queues have available space for new objects
-// to download, dispatcher puts objects to download in these queues. If joggers
-// are currently full, dispatcher waits with dispatching next batch until they aren't.
+// Dispatcher processes all jobs concurrently, extracting objects to download
+// from jobs in batches and dispatching them one by one from the job with
+// the lowest pass (see: priority.go). When joggers queues have available space
+// for new objects to download, dispatcher puts objects to download in these
+// queues. If joggers are currently full, dispatcher waits with dispatching next
+// object until they aren't.
 //
 // Single object's download is represented as object of `task` type, and there is at
 // most one active task assigned to any jogger at any given time. The
@@ -114,6 +118,9 @@ const (
 	adminAbort   = "ABORT"
 	adminStatus  = "STATUS"
 	adminList    = "LIST"
+	adminPause   = "PAUSE"
+	adminResume  = "RESUME"
+	adminPrio    = "PRIORITY"
 	taskDownload = "DOWNLOAD"
 
 	jobsChSize = 1000
@@ -163,9 +170,10 @@ type (
 	// for a download request. These objects are used by Downloader to process
 	// the request, and are then dispatched to the correct jogger to be handled.
 	request struct {
-		action     string         // one of: adminAbort, adminList, adminStatus, adminRemove, taskDownload
+		action     string         // one of: adminAbort, adminList, adminStatus, adminRemove, adminPause, adminResume, adminPrio, taskDownload
 		id         string         // id of the job task
 		regex      *regexp.Regexp // regex of descriptions to return if id is empty
+		priority   int            // new priority of the job (adminPrio)
 		obj        cmn.DlObj
 		bucket     string
 		provider   string
@@ -183,7 +191,7 @@ type (
 //==================================== Requests ===========================================
 
 func (req *request) String() (str string) {
-	str += fmt.Sprintf("id: %q, objname: %q, link: %q, from_cloud: %v, ", req.id, req.obj.Objname, req.obj.Link, req.obj.FromCloud)
+	str += fmt.Sprintf("id: %q, objname: %q, link: %q, from_cloud: %v, ", req.id, req.obj.Objname, cmn.RedactURL(req.obj.Link), req.obj.FromCloud)
 	if req.bucket != "" {
 		str += fmt.Sprintf("bucket: %q (provider: %q), ", req.bucket, req.provider)
 	}
@@ -284,6 +292,12 @@ Loop:
 				d.dispatcher.dispatchRemove(req)
 			case adminList:
 				d.dispatcher.dispatchList(req)
+			case adminPause:
+				d.dispatcher.dispatchPause(req)
+			case adminResume:
+				d.dispatcher.dispatchResume(req)
+			case adminPrio:
+				d.dispatcher.dispatchPriority(req)
 			default:
 				cmn.AssertFmt(false, req, req.action)
 			}
@@ -321,10 +335,23 @@ func (d *Downloader) Stop(err error) {
 /*
  * Downloader's exposed methods
  */
-func (d *Downloader) Download(dJob DlJob) (resp interface{}, err error, statusCode int) {
+// Download starts the job. The sync job is persisted together with the request
+// which has started it (see: SyncJob).
+func (d *Downloader) Download(dJob DlJob, req *SyncRequest) (resp interface{}, err error, statusCode int) {
 	d.IncPending()
 	defer d.DecPending()
-	dlStore.setJob(dJob.ID(), dJob)
+	var cksums map[string]string
+	if objname, _ := dJob.CksumList(); objname != "" {
+		if cksums, err = d.loadCksums(dJob); err != nil {
+			return nil, err, http.StatusBadRequest
+		}
+	}
+	dlStore.setJob(dJob.ID(), dJob, d.targetCnt(dJob), d.t.GetSowner().Get())
+	dlStore.setCksums(dJob, cksums)
+	if dJob.SyncInterval() > 0 && req != nil {
+		jInfo, _ := dlStore.getJob(dJob.ID())
+		dlStore.saveSyncJob(dJob.ID(), req, jInfo.syncStart)
+	}
 
 	select {
 	case d.downloadCh <- dJob:
@@ -334,6 +361,31 @@ func (d *Downloader) Download(dJob DlJob) (resp interface{}, err error, statusCo
 	}
 }
 
+// RestoreSyncJob registers the sync job which has been persisted before the
+// target restarted, together with the changes of its scheduling. The job is
+// not run until its next scheduled run.
+func (d *Downloader) RestoreSyncJob(dJob DlJob, sj *SyncJob) {
+	dlStore.setJob(dJob.ID(), dJob, d.targetCnt(dJob), d.t.GetSowner().Get())
+	jInfo, _ := dlStore.getJob(dJob.ID())
+	jInfo.syncStart = sj.Start
+	jInfo.Paused.Store(sj.Paused)
+	if sj.Priority != 0 {
+		jInfo.Priority.Store(int32(sj.Priority))
+	}
+	jInfo.AllDispatched.Store(true)
+	jInfo.FinishedTime.Store(time.Now())
+	d.dispatcher.scheduleSync(dJob)
+}
+
+// targetCnt returns the number of targets among which the limits of the job
+// are divided.
+func (d *Downloader) targetCnt(dJob DlJob) int {
+	if cnt := dJob.TargetCnt(); cnt > 0 {
+		return cnt
+	}
+	return d.t.GetSowner().Get().CountTargets()
+}
+
 func (d *Downloader) AbortJob(id string) (resp interface{}, err error, statusCode int) {
 	d.IncPending()
 	req := &request{
@@ -364,6 +416,52 @@ func (d *Downloader) RemoveJob(id string) (resp interface{}, err error, statusCo
 	return r.resp, r.err, r.statusCode
 }
 
+func (d *Downloader) PauseJob(id string) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	req := &request{
+		action:     adminPause,
+		id:         id,
+		responseCh: make(chan *response, 1),
+	}
+	d.adminCh <- req
+
+	// await the response
+	r := <-req.responseCh
+	d.DecPending()
+	return r.resp, r.err, r.statusCode
+}
+
+func (d *Downloader) ResumeJob(id string) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	req := &request{
+		action:     adminResume,
+		id:         id,
+		responseCh: make(chan *response, 1),
+	}
+	d.adminCh <- req
+
+	// await the response
+	r := <-req.responseCh
+	d.DecPending()
+	return r.resp, r.err, r.statusCode
+}
+
+func (d *Downloader) SetJobPriority(id string, priority int) (resp interface{}, err error, statusCode int) {
+	d.IncPending()
+	req := &request{
+		action:     adminPrio,
+		id:         id,
+		priority:   priority,
+		responseCh: make(chan *response, 1),
+	}
+	d.adminCh <- req
+
+	// await the response
+	r := <-req.responseCh
+	d.DecPending()
+	return r.resp, r.err, r.statusCode
+}
+
 func (d *Downloader) JobStatus(id string) (resp interface{}, err error, statusCode int) {
 	d.IncPending()
 	req := &request{
@@ -442,9 +540,7 @@ func (d *Downloader) getNumPending(jobID string) int {
 
 	d.dispatcher.RLock()
 	for _, j := range d.dispatcher.joggers {
-		if tasks, ok := j.q.m[jobID]; ok {
-			numPending += len(tasks)
-		}
+		numPending += j.q.pending(jobID)
 	}
 	d.dispatcher.RUnlock()
 
@@ -456,11 +552,11 @@ func internalErrorMessage() string {
 }
 
 func httpClientErrorMessage(err *url.Error) string {
-	return fmt.Sprintf("Error performing request to object's location (%s): %v.", err.URL, err.Err)
+	return fmt.Sprintf("Error performing request to object's location (%s): %v.", cmn.RedactURL(err.URL), err.Err)
 }
 
 func httpRequestErrorMessage(url string, resp *http.Response) string {
-	return fmt.Sprintf("Error downloading file from object's location (%s). Server returned status: %s", url, resp.Status)
+	return fmt.Sprintf("Error downloading file from object's location (%s). Server returned status: %s", cmn.RedactURL(url), resp.Status)
 }
 
 //
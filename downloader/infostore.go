@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/housekeep/hk"
 )
@@ -78,12 +79,21 @@ func (is *infoStore) getList(descRegex *regexp.Regexp) []*DownloadJobInfo {
 	return jobsInfo
 }
 
-func (is *infoStore) setJob(id string, job DlJob) {
+// setJob registers the job; targetCnt is the number of targets among which
+// the limits of the job are divided and smap is the current cluster map.
+func (is *infoStore) setJob(id string, job DlJob, targetCnt int, smap *cluster.Smap) {
 	jInfo := &DownloadJobInfo{
-		ID:          job.ID(),
-		Total:       job.Len(),
-		Description: job.Description(),
+		ID:           job.ID(),
+		Total:        job.Len(),
+		Description:  job.Description(),
+		Bucket:       job.Bucket(),
+		Provider:     job.Provider(),
+		ManifestName: job.Manifest(),
+		syncStart:    time.Now(),
+		limiter:      newJobLimiter(job, targetCnt),
+		smap:         smap,
 	}
+	jInfo.Priority.Store(int32(job.Priority()))
 
 	is.Lock()
 	is.jobInfo[id] = jInfo
@@ -155,6 +165,30 @@ func (is *infoStore) setAborted(id string) error {
 	return nil
 }
 
+// resetJob prepares the info of the sync job for its next run. Errors and
+// tasks of the previous run are removed.
+func (is *infoStore) resetJob(id string, smap *cluster.Smap) {
+	jInfo, err := is.getJob(id)
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+
+	jInfo.FinishedCnt.Store(0)
+	jInfo.ScheduledCnt.Store(0)
+	jInfo.ErrorCnt.Store(0)
+	jInfo.AllDispatched.Store(false)
+	jInfo.smap = smap
+
+	jInfo.manifest.Lock()
+	jInfo.manifest.local, jInfo.manifest.all, jInfo.manifest.received = nil, nil, nil
+	jInfo.manifest.notifyCh = nil
+	jInfo.manifest.sent.Store(false)
+	jInfo.manifest.Unlock()
+
+	is.downloaderDB.delete(id)
+}
+
 func (is *infoStore) delJob(id string) {
 	delete(is.jobInfo, id)
 	is.downloaderDB.delete(id)
@@ -165,11 +199,13 @@ func (is *infoStore) housekeep() time.Duration {
 
 	is.Lock()
 	for id, jInfo := range is.jobInfo {
-		if time.Since(jInfo.FinishedTime.Load()) > interval {
+		// Scheduled sync jobs and paused jobs are kept until they are aborted or removed.
+		if time.Since(jInfo.FinishedTime.Load()) > interval && jInfo.NextSync.Load().IsZero() && !jInfo.Paused.Load() {
 			is.delJob(id)
 		}
 	}
 	is.Unlock()
 
+	is.housekeepResume()
 	return interval
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
@@ -36,22 +37,60 @@ type (
 		GenNext() (objs []cmn.DlObj, ok bool)
 
 		Description() string
+		// Manifest returns the name of the manifest object which should be
+		// written into the bucket once the job finishes, empty if none.
+		Manifest() string
+		// ChunkSize returns the size above which the objects are downloaded
+		// in chunks, zero if the value from the config should be used.
+		ChunkSize() int64
+		// Limits returns the limits of the whole job and of each host from
+		// which the job downloads, see: limits.go.
+		Limits() (job, host cmn.DlLimits)
+		// Headers returns the headers which are added to each request to the
+		// source (eg. authorization). They are persisted, encrypted, only
+		// with the sync jobs (see: seal.go).
+		Headers() map[string]string
+		// SyncInterval returns the interval after which the job is run
+		// again, zero if the job is run only once (see: sync.go).
+		SyncInterval() time.Duration
+		// CksumList returns the name of the object (in the bucket) with
+		// the expected checksums of the objects and their type, empty if
+		// none (see: cksum.go).
+		CksumList() (objname, cksumType string)
+		// Priority returns the initial priority of the job, it can be
+		// changed while the job is running (see: priority.go).
+		Priority() int
+		// TargetCnt returns the number of targets which download the objects
+		// of the job, zero if all the targets do (see: limits.go).
+		TargetCnt() int
+		// Reset rewinds the job so that GenNext generates all the objects
+		// again in the next run of the sync job.
+		Reset() error
 		// if total length (size) of download job is not known, -1 should be returned
 		Len() int
 	}
 
 	BaseDlJob struct {
-		id          string
-		bck         *cluster.Bck
-		timeout     string
-		description string
+		id           string
+		bck          *cluster.Bck
+		timeout      string
+		description  string
+		manifest     string
+		chunkSize    int64
+		limits       cmn.DlLimits
+		hostLimits   cmn.DlLimits
+		headers      map[string]string
+		syncInterval time.Duration
+		cksumList    string
+		cksumType    string
+		priority     int
 	}
 
 	SliceDlJob struct {
 		BaseDlJob
-		objs    []cmn.DlObj
-		timeout string
-		current int
+		objs      []cmn.DlObj
+		current   int
+		targetCnt int
 	}
 
 	CloudBucketDlJob struct {
@@ -80,6 +119,32 @@ type (
 		AllDispatched atomic.Bool `json:"all_dispatched"`
 
 		FinishedTime atomic.Time `json:"-"`
+		NextSync     atomic.Time `json:"-"` // time of the next run of the sync job, zero if none
+		syncStart    time.Time   // the sync job is run every sync interval since, see: sync.go
+
+		// scheduling related fields, see: priority.go
+		Paused   atomic.Bool  `json:"paused"`
+		Priority atomic.Int32 `json:"priority"`
+
+		// manifest related fields, see: manifest.go
+		Bucket       string `json:"-"`
+		Provider     string `json:"-"`
+		ManifestName string `json:"-"`
+		manifest     jobManifest
+
+		// objects received from the other targets, see: list.go
+		list jobList
+
+		// cluster map at the start of the job (or the run of the sync job) -
+		// only the targets in it take part in the job, see: list.go
+		smap *cluster.Smap
+
+		limiter *jobLimiter // nil if the job has no limits, see: limits.go
+
+		// expected checksums of the objects from the list of checksums,
+		// see: cksum.go
+		cksumType string
+		cksums    map[string]string
 	}
 
 	ListBucketPageCb func(bucket, pageMarker string) (*cmn.BucketList, error)
@@ -91,12 +156,45 @@ func (j *BaseDlJob) Bucket() string      { return j.bck.Name }
 func (j *BaseDlJob) Provider() string    { return j.bck.Provider }
 func (j *BaseDlJob) Timeout() string     { return j.timeout }
 func (j *BaseDlJob) Description() string { return j.description }
-
-func NewBaseDlJob(id string, bck *cluster.Bck, timeout, desc string) *BaseDlJob {
-	return &BaseDlJob{id: id, bck: bck, timeout: timeout, description: desc}
+func (j *BaseDlJob) Manifest() string    { return j.manifest }
+func (j *BaseDlJob) ChunkSize() int64    { return j.chunkSize }
+func (j *BaseDlJob) Limits() (job, host cmn.DlLimits) {
+	return j.limits, j.hostLimits
+}
+func (j *BaseDlJob) Headers() map[string]string  { return j.headers }
+func (j *BaseDlJob) SyncInterval() time.Duration { return j.syncInterval }
+func (j *BaseDlJob) CksumList() (objname, cksumType string) {
+	return j.cksumList, j.cksumType
+}
+func (j *BaseDlJob) Priority() int  { return j.priority }
+func (j *BaseDlJob) TargetCnt() int { return 0 }
+
+func NewBaseDlJob(id string, bck *cluster.Bck, payload *cmn.DlBase) *BaseDlJob {
+	chunkSize, _ := cmn.S2B(payload.ChunkSize) // validated beforehand
+	syncInterval, _ := time.ParseDuration(payload.SyncInterval)
+	priority := payload.Priority
+	if priority == 0 {
+		priority = cmn.DlDefaultPriority
+	}
+	return &BaseDlJob{
+		id:           id,
+		bck:          bck,
+		timeout:      payload.Timeout,
+		description:  payload.Description,
+		manifest:     payload.Manifest,
+		chunkSize:    chunkSize,
+		limits:       payload.Limits,
+		hostLimits:   payload.HostLimits,
+		headers:      payload.Headers,
+		syncInterval: syncInterval,
+		cksumList:    payload.CksumList,
+		cksumType:    payload.CksumType,
+		priority:     priority,
+	}
 }
 
-func (j *SliceDlJob) Len() int { return len(j.objs) }
+func (j *SliceDlJob) Len() int       { return len(j.objs) }
+func (j *SliceDlJob) TargetCnt() int { return j.targetCnt }
 func (j *SliceDlJob) GenNext() (objs []cmn.DlObj, ok bool) {
 	if j.current == len(j.objs) {
 		return []cmn.DlObj{}, false
@@ -113,11 +211,19 @@ func (j *SliceDlJob) GenNext() (objs []cmn.DlObj, ok bool) {
 	return objs, true
 }
 
-func NewSliceDlJob(id string, objs []cmn.DlObj, bck *cluster.Bck, timeout, description string) *SliceDlJob {
+func (j *SliceDlJob) Reset() error {
+	j.current = 0
+	return nil
+}
+
+// NewSliceDlJob creates the job which downloads the given objects (of this
+// target); targetCnt is the number of targets among which all the objects of
+// the job are distributed.
+func NewSliceDlJob(base *BaseDlJob, objs []cmn.DlObj, targetCnt int) *SliceDlJob {
 	return &SliceDlJob{
-		BaseDlJob: BaseDlJob{id: id, bck: bck, timeout: timeout, description: description},
+		BaseDlJob: *base,
 		objs:      objs,
-		timeout:   timeout,
+		targetCnt: targetCnt,
 	}
 }
 
@@ -159,6 +265,13 @@ func (j *CloudBucketDlJob) GetNextObjs() error {
 		Fast:       true,
 		PageSize:   cmn.DefaultListPageSize,
 	}
+	if j.syncInterval > 0 {
+		// Versions (or sizes and checksums, which are ETags of the objects,
+		// when the bucket is not versioned) are needed to find out which
+		// objects have changed.
+		msg.Fast = false
+		msg.Props = strings.Join([]string{cmn.GetPropsVersion, cmn.GetPropsSize, cmn.GetPropsChecksum}, ",")
+	}
 
 	bckList, err, _ := j.t.Cloud().ListBucket(j.ctx, j.bck.Name, msg)
 	if err != nil {
@@ -167,6 +280,7 @@ func (j *CloudBucketDlJob) GetNextObjs() error {
 	j.pageMarker = msg.PageMarker
 
 	objects := make(cmn.SimpleKVs, cmn.DefaultListPageSize)
+	entries := make(map[string]*cmn.BucketEntry, cmn.DefaultListPageSize)
 	smap := j.t.GetSowner().Get()
 	for _, entry := range bckList.Entries {
 		si, err := cluster.HrwTarget(j.bck.MakeUname(entry.Name), smap)
@@ -178,17 +292,28 @@ func (j *CloudBucketDlJob) GetNextObjs() error {
 			continue
 		}
 		objects[entry.Name] = ""
+		entries[entry.Name] = entry
 	}
 
 	dl, err := GetTargetDlObjs(j.t, objects, j.bck, true /* is cloud - FIXME: deprecated */)
 	if err != nil {
 		return err
 	}
+	for i := range dl {
+		entry := entries[dl[i].Objname]
+		dl[i].Version, dl[i].Size, dl[i].ETag = entry.Version, entry.Size, entry.Checksum
+	}
 	j.objs = dl
 
 	return nil
 }
 
+func (j *CloudBucketDlJob) Reset() error {
+	j.wg.Wait()
+	j.pageMarker, j.pagesCnt = "", 0
+	return j.GetNextObjs()
+}
+
 func NewCloudBucketDlJob(ctx context.Context, t cluster.Target, base *BaseDlJob, prefix, suffix string) (*CloudBucketDlJob, error) {
 	job := &CloudBucketDlJob{
 		BaseDlJob:  *base,
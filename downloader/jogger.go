@@ -14,15 +14,28 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 )
 
-const queueChSize = 1000
+const queueChSize = 1000 // max number of queued tasks of the jobs which are not paused
 
 type (
 	queueEntry = map[string]struct{}
 
+	// jobQueue holds the queued tasks of a single job, see: priority.go.
+	jobQueue struct {
+		tasks  []*singleObjectTask
+		pass   uint64
+		paused bool
+	}
+
 	queue struct {
 		sync.RWMutex
-		ch chan *singleObjectTask // for pending downloads
-		m  map[string]queueEntry  // jobID -> set of request uid
+		jobs   map[string]*jobQueue  // jobID -> pending downloads
+		m      map[string]queueEntry // jobID -> set of request uid
+		queued int                   // number of queued tasks of the jobs which are not paused
+		vtime  uint64                // pass of the most recently scheduled job
+
+		readyCh chan struct{} // signals that there may be a task to get
+		spaceCh chan struct{} // signals that there may be space for the task to put
+		stopCh  chan struct{}
 	}
 
 	// Each jogger corresponds to an mpath. All types of download requests
@@ -81,6 +94,7 @@ func (j *jogger) jog() {
 		if exists := j.q.delete(t.request); exists {
 			j.parent.parent.DecPending()
 		}
+		j.parent.parent.checkManifest(t.id)
 	}
 
 	j.q.cleanup()
@@ -102,53 +116,68 @@ func (j *jogger) stop() {
 	<-j.terminateCh
 }
 
-// returns chanel which task should be put into
-func (j *jogger) putCh(t *singleObjectTask) chan<- *singleObjectTask {
-	ok, ch := j.q.putCh(t)
+// put tries to put the task into the queue, returns false if the queue is
+// full (see: queue.spaceCh).
+func (j *jogger) put(t *singleObjectTask) bool {
+	ok, full := j.q.put(t)
 	if ok {
 		j.parent.parent.IncPending()
 	}
-	return ch
+	return !full
 }
 
 func newQueue() *queue {
 	return &queue{
-		ch: make(chan *singleObjectTask, queueChSize),
-		m:  make(map[string]queueEntry),
+		jobs:    make(map[string]*jobQueue),
+		m:       make(map[string]queueEntry),
+		readyCh: make(chan struct{}, 1),
+		spaceCh: make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
 	}
 }
 
-func (q *queue) putCh(t *singleObjectTask) (ok bool, ch chan<- *singleObjectTask) {
+func (q *queue) put(t *singleObjectTask) (ok, full bool) {
 	q.Lock()
+	defer q.Unlock()
 	if q.exists(t.request.id, t.request.uid()) {
 		// If task already exists we should just omit it
-		// hence return chanel which immediately accepts and omits the task
-		q.Unlock()
-		return false, make(chan *singleObjectTask, 1)
+		return false, false
+	}
+	jq, exists := q.jobs[t.id]
+	if !exists {
+		jq = &jobQueue{pass: q.vtime, paused: dlStore.isPaused(t.id)}
+	}
+	if !jq.paused {
+		if q.queued >= queueChSize {
+			return false, true
+		}
+		q.queued++
 	}
+	if !exists {
+		q.jobs[t.id] = jq
+	}
+	jq.tasks = append(jq.tasks, t)
 	q.putToSet(t.id, t.request.uid())
-	q.Unlock()
-
-	return true, q.ch
+	notify(q.readyCh)
+	return true, false
 }
 
-// Get tries to find first task which was not yet Aborted
+// Get waits for the task of the job with the lowest pass which is not paused
+// (see: priority.go), returns nil if the queue has been stopped.
 func (q *queue) get() (foundTask *singleObjectTask) {
 	for foundTask == nil {
-		t, ok := <-q.ch
-		if !ok {
-			foundTask = nil
-			return
+		q.Lock()
+		foundTask = q.pop()
+		q.Unlock()
+		if foundTask != nil {
+			break
 		}
 
-		q.RLock()
-		if q.exists(t.request.id, t.request.uid()) {
-			// NOTE: We do not delete task here but postpone it until the task
-			// has Finished to prevent situation where we put task which is being
-			// downloaded.
-			foundTask = t
+		select {
+		case <-q.readyCh:
+		case <-q.stopCh:
+			return nil
 		}
-		q.RUnlock()
 	}
 
 	timeout := cmn.GCO.Get().Downloader.Timeout
@@ -164,6 +193,94 @@ func (q *queue) get() (foundTask *singleObjectTask) {
 	return
 }
 
+// pop should be called under Lock()
+func (q *queue) pop() *singleObjectTask {
+	select {
+	case <-q.stopCh:
+		return nil
+	default:
+	}
+
+	var (
+		next   *jobQueue
+		nextID string
+	)
+	for id, jq := range q.jobs {
+		if jq.paused || len(jq.tasks) == 0 {
+			continue
+		}
+		if next == nil || lessPass(jq.pass, id, next.pass, nextID) {
+			next, nextID = jq, id
+		}
+	}
+	if next == nil {
+		return nil
+	}
+
+	t := next.tasks[0]
+	next.tasks[0] = nil
+	next.tasks = next.tasks[1:]
+	if len(next.tasks) == 0 {
+		delete(q.jobs, nextID)
+	}
+	q.vtime = next.pass
+	next.pass += jobStride(nextID)
+	q.queued--
+	notify(q.spaceCh)
+
+	// NOTE: We do not delete task from the set here but postpone it until
+	// the task has Finished to prevent situation where we put task which is
+	// being downloaded.
+	return t
+}
+
+// removeJob removes all pending tasks of the job from the queue, returns the
+// number of removed tasks (including the one which is being downloaded).
+func (q *queue) removeJob(jobID string) int {
+	q.Lock()
+	defer q.Unlock()
+	if jq, ok := q.jobs[jobID]; ok {
+		if !jq.paused {
+			q.queued -= len(jq.tasks)
+			notify(q.spaceCh)
+		}
+		delete(q.jobs, jobID)
+	}
+	n := len(q.m[jobID])
+	delete(q.m, jobID)
+	return n
+}
+
+// setPaused pauses or resumes getting the tasks of the job from the queue.
+func (q *queue) setPaused(jobID string, paused bool) {
+	q.Lock()
+	defer q.Unlock()
+	jq, ok := q.jobs[jobID]
+	if !ok || jq.paused == paused {
+		return
+	}
+	jq.paused = paused
+	if paused {
+		q.queued -= len(jq.tasks)
+		notify(q.spaceCh)
+	} else {
+		// Continue from the current virtual time, as if the job has just joined.
+		if jq.pass < q.vtime {
+			jq.pass = q.vtime
+		}
+		q.queued += len(jq.tasks)
+		notify(q.readyCh)
+	}
+}
+
+// pending returns the number of pending tasks of the job (including the
+// one which is being downloaded).
+func (q *queue) pending(jobID string) int {
+	q.RLock()
+	defer q.RUnlock()
+	return len(q.m[jobID])
+}
+
 func (q *queue) delete(req *request) bool {
 	q.Lock()
 	exists := q.exists(req.id, req.uid())
@@ -173,20 +290,24 @@ func (q *queue) delete(req *request) bool {
 }
 
 func (q *queue) stop() {
-	q.RLock()
-	if q.ch != nil {
-		close(q.ch)
-	}
-	q.RUnlock()
+	close(q.stopCh)
 }
 
 func (q *queue) cleanup() {
 	q.Lock()
-	q.ch = nil
+	q.jobs = nil
 	q.m = nil
 	q.Unlock()
 }
 
+// notify signals the channel unless it has been already signaled.
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
 // exists should be called under RLock()
 func (q *queue) exists(jobID, requestUID string) bool {
 	jobM, ok := q.m[jobID]
@@ -0,0 +1,362 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// ================================ Limits =====================================
+//
+// Each job can limit the number of concurrent connections, requests per
+// second and bytes per second - both for the whole job and for each host from
+// which the job downloads (see: cmn.DlLimits). The limits are cluster-wide so
+// each target enforces its share: the limits divided by the number of targets
+// which download the objects of the job (see: DlJob.TargetCnt). The share of
+// connections is rounded down, so that the limit is never exceeded; the proxy
+// rejects the limits of connections lower than the number of targets, so that
+// every target can make at least one connection (see: DlBase.ValidateLimits).
+//
+// When the source responds with 429 (Too Many Requests) or 503 (Service
+// Unavailable) all the requests of the job to the host are held back for the
+// time requested in `Retry-After` header or, if not provided, for the time
+// which grows exponentially with the number of consecutive such responses.
+// This applies to all the jobs, whether they have limits or not.
+//
+// ================================ Limits =====================================
+
+const (
+	// Number of times the request which was throttled by the source is
+	// retried (on top of regular retries).
+	throttleRetryCnt = 10
+	// Backoff used when the source does not provide `Retry-After`.
+	throttleBackoffMin = time.Second
+	throttleBackoffMax = time.Minute
+)
+
+type (
+	// tokenBucket limits the rate of some quantity (requests, bytes). The
+	// bucket holds at most one second worth of tokens.
+	tokenBucket struct {
+		mtx    sync.Mutex
+		rate   float64 // tokens per second
+		tokens float64
+		last   time.Time
+	}
+
+	limiter struct {
+		conns    chan struct{} // nil if unlimited
+		requests *tokenBucket  // nil if unlimited
+		bytes    *tokenBucket  // nil if unlimited
+
+		blockedUntil atomic.Int64 // unix nano, set when the source throttles
+		throttled    atomic.Int32 // number of consecutive throttled responses
+	}
+
+	// jobLimiter enforces the limits of the job on this target.
+	jobLimiter struct {
+		job        *limiter
+		hostLimits cmn.DlLimits
+		targetCnt  int
+
+		mtx   sync.Mutex
+		hosts map[string]*limiter
+	}
+
+	// errThrottled is returned when the source has responded with 429 or 503.
+	errThrottled struct {
+		host       string
+		statusCode int
+		delay      time.Duration
+	}
+
+	limitedReader struct {
+		ctx      context.Context
+		r        io.Reader
+		limiters []*limiter
+	}
+)
+
+func newTokenBucket(rate float64) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &tokenBucket{rate: rate, tokens: rate, last: time.Now()}
+}
+
+// wait takes n tokens from the bucket, waiting until they are available.
+// The tokens can be taken in advance (the bucket goes into debt) so that
+// requests for more tokens than the bucket can hold are also satisfied.
+func (b *tokenBucket) wait(ctx context.Context, n float64) error {
+	b.mtx.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= n
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mtx.Unlock()
+	return sleepCtx(ctx, delay)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newLimiter creates the limiter which enforces the share of the (cluster-wide)
+// limits on this target.
+func newLimiter(limits cmn.DlLimits, targetCnt int) *limiter {
+	if targetCnt < 1 {
+		targetCnt = 1
+	}
+	l := &limiter{}
+	if limits.Connections > 0 {
+		// Every target must be able to make at least one connection - even
+		// if the target has joined the cluster after the limits were validated.
+		conns := cmn.Max(limits.Connections/targetCnt, 1)
+		l.conns = make(chan struct{}, conns)
+	}
+	l.requests = newTokenBucket(float64(limits.RequestsPerSec) / float64(targetCnt))
+	bps, _ := cmn.S2B(limits.BytesPerSec) // validated beforehand
+	l.bytes = newTokenBucket(float64(bps) / float64(targetCnt))
+	return l
+}
+
+// acquire waits until the request can be made. Release must be called once
+// the request (including reading the response) has finished.
+func (l *limiter) acquire(ctx context.Context) (err error) {
+	for {
+		delay := time.Until(time.Unix(0, l.blockedUntil.Load()))
+		if delay <= 0 {
+			break
+		}
+		if err = sleepCtx(ctx, delay); err != nil {
+			return
+		}
+	}
+	if l.conns != nil {
+		select {
+		case l.conns <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if l.requests != nil {
+		if err = l.requests.wait(ctx, 1); err != nil {
+			l.release()
+		}
+	}
+	return
+}
+
+func (l *limiter) release() {
+	if l.conns != nil {
+		<-l.conns
+	}
+}
+
+// throttle holds back all the requests for the given time (or for the backoff
+// time if zero) and returns the time.
+func (l *limiter) throttle(delay time.Duration) time.Duration {
+	cnt := l.throttled.Inc()
+	if delay <= 0 {
+		delay = throttleBackoffMin << uint(cmn.Min(int(cnt)-1, 16))
+		if delay > throttleBackoffMax {
+			delay = throttleBackoffMax
+		}
+	}
+	until := time.Now().Add(delay).UnixNano()
+	for {
+		prev := l.blockedUntil.Load()
+		if prev >= until || l.blockedUntil.CAS(prev, until) {
+			break
+		}
+	}
+	return delay
+}
+
+func newJobLimiter(job DlJob, targetCnt int) *jobLimiter {
+	jobLimits, hostLimits := job.Limits()
+	jl := &jobLimiter{
+		hostLimits: hostLimits,
+		targetCnt:  targetCnt,
+		hosts:      make(map[string]*limiter),
+	}
+	if !jobLimits.IsZero() {
+		jl.job = newLimiter(jobLimits, targetCnt)
+	}
+	return jl
+}
+
+// limiters returns all the limiters which apply to the requests to the host.
+// The limiter of the host is always the last one.
+func (jl *jobLimiter) limiters(host string) []*limiter {
+	limiters := make([]*limiter, 0, 2)
+	if jl.job != nil {
+		limiters = append(limiters, jl.job)
+	}
+	jl.mtx.Lock()
+	l, ok := jl.hosts[host]
+	if !ok {
+		// The limiter is created even if the host is not limited so that
+		// throttling by the host can be tracked.
+		l = newLimiter(jl.hostLimits, jl.targetCnt)
+		jl.hosts[host] = l
+	}
+	jl.mtx.Unlock()
+	return append(limiters, l)
+}
+
+// do makes the request respecting the limits. The returned body reads the
+// response respecting the limits as well and must be closed.
+func (jl *jobLimiter) do(ctx context.Context, req *http.Request) (resp *http.Response, err error) {
+	limiters := jl.limiters(req.URL.Host)
+	for i, l := range limiters {
+		if err = l.acquire(ctx); err != nil {
+			for _, l := range limiters[:i] {
+				l.release()
+			}
+			return nil, err
+		}
+	}
+	release := func() {
+		for _, l := range limiters {
+			l.release()
+		}
+	}
+
+	if resp, err = httpClient.Do(req.WithContext(ctx)); err != nil {
+		if uerr, ok := err.(*url.Error); ok {
+			// The link may contain credentials or signature.
+			uerr.URL = cmn.RedactURL(uerr.URL)
+		}
+		release()
+		return
+	}
+	hostLimiter := limiters[len(limiters)-1]
+	if err = checkThrottled(req.URL.Host, resp); err != nil {
+		resp.Body.Close()
+		release()
+		throttled := err.(*errThrottled)
+		throttled.delay = hostLimiter.throttle(throttled.delay)
+		return nil, err
+	}
+	hostLimiter.throttled.Store(0)
+	resp.Body = &releaseReadCloser{
+		Reader:  &limitedReader{ctx: ctx, r: resp.Body, limiters: limiters},
+		closer:  resp.Body,
+		release: release,
+	}
+	return
+}
+
+// checkThrottled returns errThrottled if the source asks to slow down.
+func checkThrottled(host string, resp *http.Response) error {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil
+	}
+	return &errThrottled{host: host, statusCode: resp.StatusCode, delay: retryAfter(resp)}
+}
+
+// retryAfter parses `Retry-After` header which is either number of seconds
+// or HTTP date. Returns zero if not provided.
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func (e *errThrottled) Error() string {
+	return fmt.Sprintf("%s throttled the request (status code: %d), retrying in %v", e.host, e.statusCode, e.delay)
+}
+
+// limiterErrorMessage returns the message (for the user) describing the error
+// returned by jobLimiter.do.
+func limiterErrorMessage(err error) string {
+	switch e := err.(type) {
+	case *url.Error: // returned by httpClient.Do()
+		return httpClientErrorMessage(e)
+	case *errThrottled:
+		return fmt.Sprintf("Error downloading file from object's location: %s.", e.Error())
+	}
+	return ""
+}
+
+func isThrottled(err error) bool {
+	_, ok := err.(*errThrottled)
+	return ok
+}
+
+func (r *limitedReader) Read(b []byte) (n int, err error) {
+	n, err = r.r.Read(b)
+	if n > 0 {
+		for _, l := range r.limiters {
+			if l.bytes == nil {
+				continue
+			}
+			if errWait := l.bytes.wait(r.ctx, float64(n)); errWait != nil {
+				return n, errWait
+			}
+		}
+	}
+	return
+}
+
+type releaseReadCloser struct {
+	io.Reader
+	closer  io.Closer
+	release func()
+	once    sync.Once
+}
+
+func (r *releaseReadCloser) Close() error {
+	err := r.closer.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// retryThrottled should be called when the attempt has failed. It returns
+// true if the attempt should not be counted because the source has only
+// throttled the request.
+func retryThrottled(err error, throttledCnt *int) bool {
+	if !isThrottled(err) || *throttledCnt >= throttleRetryCnt {
+		return false
+	}
+	*throttledCnt++
+	glog.Warning(err)
+	return true
+}
@@ -0,0 +1,450 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ================================ List =======================================
+//
+// ListDlJob downloads the links which are listed in the object (the list)
+// stored in the cluster - one link per line (see: cmn.DlListBody). The list is
+// divided into pages (of listPageSize bytes) and each page is read only by the
+// target which owns it (according to HRW), as the job progresses. A line
+// belongs to the page in which it starts, so the owner reads past the end of
+// the page to complete its last line and skips the beginning of the page which
+// completes the last line of the previous page.
+//
+// The owner of the page keeps the objects which belong to it and sends the
+// other objects to the targets which they belong to (according to HRW). Once
+// all the pages of a target have been read, the target notifies the other
+// targets - the job finishes on the target once it has read all its pages
+// and has been notified by all the other targets. Only the targets in the
+// cluster map at the start of the job (or the run) take part in it - the
+// targets which have left the cluster since are considered done. The job fails
+// if the other targets cannot be notified or if none of them has made any
+// progress for listWaitTimeout. The runs of the sync job are numbered by the
+// schedule (see: sync.go) so that the notifications of different runs are not
+// mixed up.
+//
+// ================================ List =======================================
+
+const (
+	// Number of bytes of the list which are read at once. Lines longer than
+	// the page are considered invalid.
+	listPageSize = 4 * cmn.MiB
+	// Number of times sending the objects to the target is retried.
+	listSendRetries = 5
+	// Interval at which the job waiting for the other targets checks whether
+	// it has been aborted.
+	listWaitPoll = time.Second
+	// Time after which the job waiting for the other targets fails if none
+	// of them has sent anything.
+	listWaitTimeout = time.Hour
+)
+
+var (
+	_ DlJob = &ListDlJob{}
+)
+
+type (
+	ListDlJob struct {
+		BaseDlJob
+		t       cluster.Target
+		listBck *cluster.Bck
+		listObj string
+		format  string
+
+		size     int64 // size of the list, -1 until the first page is read
+		page     int64 // index of the next page
+		run      int64 // number of the run of the sync job, zero if not sync
+		notified bool  // the other targets have been notified that all the pages have been read
+	}
+
+	// ListObjs are the objects sent by the owner of the page to the target
+	// which they belong to. Done is set when the owner has read all its pages
+	// (and sent all the objects) in the run.
+	ListObjs struct {
+		Run  int64       `json:"run"`
+		Done bool        `json:"done,omitempty"`
+		Objs []cmn.DlObj `json:"objs,omitempty"`
+	}
+
+	// jobList holds the objects of the job received from the other targets,
+	// see: DownloadJobInfo.
+	jobList struct {
+		sync.Mutex
+		objs     []cmn.DlObj      // received and not dispatched yet
+		done     map[string]int64 // target ID -> the last run in which the target has read all its pages
+		notifyCh chan struct{}
+	}
+)
+
+func NewListDlJob(t cluster.Target, base *BaseDlJob, listBck *cluster.Bck, listObj, format string) *ListDlJob {
+	return &ListDlJob{
+		BaseDlJob: *base,
+		t:         t,
+		listBck:   listBck,
+		listObj:   listObj,
+		format:    format,
+		size:      -1,
+	}
+}
+
+func (j *ListDlJob) Len() int { return -1 }
+func (j *ListDlJob) GenNext() (objs []cmn.DlObj, ok bool) {
+	jInfo, err := dlStore.getJob(j.id)
+	if err != nil {
+		return nil, false
+	}
+	for {
+		if objs = jInfo.list.take(); len(objs) > 0 {
+			return objs, true
+		}
+		if j.size < 0 || j.page*listPageSize < j.size {
+			if objs, err = j.nextPage(jInfo.smap); err != nil {
+				err = fmt.Errorf("failed to read list %s/%s, err: %v", j.listBck, j.listObj, err)
+				glog.Error(err)
+				dlStore.persistError(j.id, j.listObj, err.Error())
+				// stop reading the pages but do not keep the other targets waiting
+				j.size = 0
+				continue
+			}
+			if len(objs) > 0 {
+				return objs, true
+			}
+			continue
+		}
+		if !j.notified {
+			j.notified = true
+			if err := j.sendDone(jInfo.smap); err != nil {
+				glog.Errorf("download job %s: %v", j.id, err)
+				dlStore.persistError(j.id, j.listObj, err.Error())
+			}
+			continue
+		}
+		if !j.waitPeers(jInfo) {
+			return nil, false
+		}
+	}
+}
+
+func (j *ListDlJob) Reset() error {
+	j.size, j.page, j.notified = -1, 0, false
+	// The run is numbered by the schedule so that it's the same on all the
+	// targets (unless the target has skipped the run).
+	if jInfo, err := dlStore.getJob(j.id); err == nil && j.syncInterval > 0 {
+		elapsed := time.Since(jInfo.syncStart)
+		j.run = int64(math.Round(float64(elapsed) / float64(j.syncInterval)))
+	}
+	return nil
+}
+
+// nextPage reads the next page of the list if it is owned by this target and
+// returns the objects of the page which belong to this target. The other
+// objects are sent to the targets which they belong to. All the targets use
+// the cluster map of the job so that they agree on the owners.
+func (j *ListDlJob) nextPage(smap *cluster.Smap) ([]cmn.DlObj, error) {
+	if j.size < 0 {
+		size, err := statObject(j.t, j.listBck, j.listObj)
+		if err != nil {
+			return nil, err
+		}
+		j.size = size
+		if j.size == 0 {
+			return nil, nil
+		}
+	}
+	page := j.page
+	j.page++
+	si, err := cluster.HrwTarget(j.listBck.MakeUname(j.listObj)+"#"+strconv.FormatInt(page, 10), smap)
+	if err != nil {
+		return nil, err
+	}
+	if si.ID() != j.t.Snode().ID() {
+		return nil, nil
+	}
+	data, err := j.readPage(page)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := parsePage(data, j.format)
+	if err != nil {
+		return nil, fmt.Errorf("page %d: %v", page, err)
+	}
+	perTarget, err := splitDlObjs(smap, objs, j.bck)
+	if err != nil {
+		return nil, err
+	}
+	for tid, objs := range perTarget {
+		if tid == j.t.Snode().ID() {
+			continue
+		}
+		if err := j.send(smap.GetTarget(tid), &ListObjs{Run: j.run, Objs: objs}); err != nil {
+			return nil, err
+		}
+	}
+	return perTarget[j.t.Snode().ID()], nil
+}
+
+// readPage reads the lines which start in the page.
+func (j *ListDlJob) readPage(page int64) ([]byte, error) {
+	var (
+		offset = page * listPageSize
+		length = int64(listPageSize)
+	)
+	if page > 0 {
+		// The last byte of the previous page tells whether the first line
+		// of the page starts in the page.
+		offset, length = offset-1, length+1
+	}
+	data, err := readRange(j.t, j.listBck, j.listObj, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	end := offset + int64(len(data))
+	if page > 0 {
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			return nil, nil // the page completes the line of one of the previous pages
+		}
+		data = data[idx+1:]
+		if len(data) == 0 {
+			return nil, nil
+		}
+	}
+	// Complete the last line.
+	for end < j.size && len(data) > 0 && data[len(data)-1] != '\n' {
+		next, err := readRange(j.t, j.listBck, j.listObj, end, listPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(next) == 0 {
+			break
+		}
+		end += int64(len(next))
+		if idx := bytes.IndexByte(next, '\n'); idx >= 0 {
+			next = next[:idx+1]
+		} else if len(next) == listPageSize {
+			return nil, fmt.Errorf("line at offset %d is longer than %d bytes", end-int64(len(next)), listPageSize)
+		}
+		data = append(data, next...)
+	}
+	return data, nil
+}
+
+// send sends the objects to the target, retrying if the target has not
+// registered the job yet.
+func (j *ListDlJob) send(si *cluster.Snode, lo *ListObjs) (err error) {
+	if si == nil {
+		return fmt.Errorf("target not found (cluster map has changed)")
+	}
+	var (
+		body  = cmn.MustMarshal(lo)
+		sleep = time.Second
+	)
+	for i := 0; i < listSendRetries; i++ {
+		if err = sendToTarget(j.t, si, cmn.Objects, j.id, body); err == nil {
+			return
+		}
+		glog.Warningf("failed to send objects of download job %s to %s (retrying in %v), err: %v", j.id, si, sleep, err)
+		time.Sleep(sleep)
+		sleep *= 2
+	}
+	return fmt.Errorf("failed to send objects to %s, err: %v", si, err)
+}
+
+// sendDone notifies the other targets of the job that all the pages of this
+// target have been read. The targets which have left the cluster are skipped.
+func (j *ListDlJob) sendDone(smap *cluster.Smap) (err error) {
+	current := j.t.GetSowner().Get()
+	for _, si := range smap.Tmap {
+		if si.ID() == j.t.Snode().ID() || current.GetTarget(si.ID()) == nil {
+			continue
+		}
+		if errSend := j.send(si, &ListObjs{Run: j.run, Done: true}); errSend != nil && err == nil {
+			err = errSend
+		}
+	}
+	return
+}
+
+// waitPeers waits until either objects are received or all the other targets
+// have read their pages; returns false if the job should finish.
+func (j *ListDlJob) waitPeers(jInfo *DownloadJobInfo) bool {
+	deadline := time.Now().Add(listWaitTimeout)
+	for {
+		ch, pending, done := jInfo.list.check(jInfo.smap, j.t.GetSowner().Get(), j.t.Snode().ID(), j.run)
+		if pending {
+			return true
+		}
+		if done {
+			return false
+		}
+		select {
+		case <-ch:
+			deadline = time.Now().Add(listWaitTimeout)
+		case <-time.After(listWaitPoll):
+			if jInfo.Aborted.Load() {
+				return false
+			}
+			if time.Now().After(deadline) {
+				err := fmt.Errorf("timed out waiting for the other targets to read the list (%v)", listWaitTimeout)
+				glog.Errorf("download job %s: %v", j.id, err)
+				dlStore.persistError(j.id, j.listObj, err.Error())
+				return false
+			}
+		}
+	}
+}
+
+// parsePage parses the lines of the page, skipping empty lines and comments.
+func parsePage(data []byte, format string) ([]cmn.DlObj, error) {
+	objs := make([]cmn.DlObj, 0, 64)
+	for i, line := range bytes.Split(data, []byte{'\n'}) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		obj, err := parseListLine(line, format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid line %d: %v", i+1, err)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func parseListLine(line []byte, format string) (obj cmn.DlObj, err error) {
+	switch format {
+	case cmn.DlListFormatCSV:
+		var record []string
+		if record, err = csv.NewReader(bytes.NewReader(line)).Read(); err != nil {
+			return
+		}
+		if len(record) > 2 {
+			return obj, fmt.Errorf("expected link[,objname], got %d fields", len(record))
+		}
+		obj.Link = record[0]
+		if len(record) == 2 {
+			obj.Objname = record[1]
+		}
+	case cmn.DlListFormatJSONL:
+		if err = jsoniter.Unmarshal(line, &obj); err != nil {
+			return
+		}
+	default:
+		obj.Link = string(line)
+	}
+	obj.FromCloud = false
+	err = obj.Validate()
+	return
+}
+
+// splitDlObjs groups the objects by the targets which they belong to (see also:
+// GetTargetDlObjs). Only the first of the objects with the same name is kept.
+func splitDlObjs(smap *cluster.Smap, objs []cmn.DlObj, bck *cluster.Bck) (map[string][]cmn.DlObj, error) {
+	var (
+		perTarget = make(map[string][]cmn.DlObj, smap.CountTargets())
+		seen      = make(cmn.StringSet, len(objs))
+	)
+	for _, obj := range objs {
+		objName, err := cmn.NormalizeObjName(obj.Objname)
+		if err != nil {
+			return nil, err
+		}
+		if seen.Contains(objName) {
+			continue
+		}
+		seen.Add(objName)
+		si, err := cluster.HrwTarget(bck.MakeUname(objName), smap)
+		if err != nil {
+			return nil, err
+		}
+		obj.Objname, obj.Link = objName, cmn.PrependProtocol(obj.Link)
+		perTarget[si.ID()] = append(perTarget[si.ID()], obj)
+	}
+	return perTarget, nil
+}
+
+// ReceiveListObjs registers the objects of the list job sent by the target
+// which owns the page.
+func (d *Downloader) ReceiveListObjs(id, daemonID string, lo *ListObjs) (error, int) {
+	jInfo, err := dlStore.getJob(id)
+	if err != nil {
+		return err, http.StatusNotFound
+	}
+	if jInfo.smap.GetTarget(daemonID) == nil {
+		return fmt.Errorf("%s is not a target of download job %s", daemonID, id), http.StatusBadRequest
+	}
+	jInfo.list.add(daemonID, lo)
+	return nil, http.StatusOK
+}
+
+//
+// jobList
+//
+
+func (jl *jobList) init() {
+	if jl.notifyCh == nil {
+		jl.notifyCh = make(chan struct{}, 1)
+		jl.done = make(map[string]int64)
+	}
+}
+
+func (jl *jobList) add(daemonID string, lo *ListObjs) {
+	jl.Lock()
+	jl.init()
+	jl.objs = append(jl.objs, lo.Objs...)
+	if lo.Done {
+		if run, ok := jl.done[daemonID]; !ok || lo.Run > run {
+			jl.done[daemonID] = lo.Run
+		}
+	}
+	notify(jl.notifyCh)
+	jl.Unlock()
+}
+
+func (jl *jobList) take() (objs []cmn.DlObj) {
+	jl.Lock()
+	objs, jl.objs = jl.objs, nil
+	jl.Unlock()
+	return
+}
+
+// check returns the channel which is notified when objects are received, and
+// whether there are pending objects or all the other targets of the job (in
+// smap) are done. The targets which are not in the current cluster map have
+// left the cluster and are considered done.
+func (jl *jobList) check(smap, current *cluster.Smap, self string, run int64) (ch chan struct{}, pending, done bool) {
+	jl.Lock()
+	defer jl.Unlock()
+	jl.init()
+	if len(jl.objs) > 0 {
+		return jl.notifyCh, true, false
+	}
+	for tid := range smap.Tmap {
+		if tid == self || current.GetTarget(tid) == nil {
+			continue
+		}
+		if r, ok := jl.done[tid]; !ok || r < run {
+			return jl.notifyCh, false, false
+		}
+	}
+	return jl.notifyCh, false, true
+}
@@ -0,0 +1,127 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestParseListLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		format      string
+		wantObjname string
+		wantLink    string
+		wantErr     bool
+	}{
+		{"txt", "http://example.com/dir/a.tar", cmn.DlListFormatTxt, "a.tar", "http://example.com/dir/a.tar", false},
+		{"csv link", "http://example.com/a.tar", cmn.DlListFormatCSV, "a.tar", "http://example.com/a.tar", false},
+		{"csv link and objname", "http://example.com/a.tar,dir/b.tar", cmn.DlListFormatCSV, "dir/b.tar", "http://example.com/a.tar", false},
+		{"csv quoted", `"http://example.com/a,b.tar",c.tar`, cmn.DlListFormatCSV, "c.tar", "http://example.com/a,b.tar", false},
+		{"jsonl", `{"link": "http://example.com/a.tar", "objname": "b.tar"}`, cmn.DlListFormatJSONL, "b.tar", "http://example.com/a.tar", false},
+		{"jsonl without objname", `{"link": "http://example.com/a.tar"}`, cmn.DlListFormatJSONL, "a.tar", "http://example.com/a.tar", false},
+
+		{"csv too many fields", "http://example.com/a.tar,b.tar,c", cmn.DlListFormatCSV, "", "", true},
+		{"csv unterminated quote", `"http://example.com/a.tar,b.tar`, cmn.DlListFormatCSV, "", "", true},
+		{"jsonl malformed", `{"link": "http://example.com/a.tar"`, cmn.DlListFormatJSONL, "", "", true},
+		{"jsonl without link", `{"objname": "b.tar"}`, cmn.DlListFormatJSONL, "", "", true},
+		{"jsonl invalid checksum", `{"link": "http://example.com/a.tar", "cksum_type": "md5", "cksum_value": "xyz"}`, cmn.DlListFormatJSONL, "", "", true},
+		{"txt without objname", "/", cmn.DlListFormatTxt, "", "", true},
+	}
+	for _, tt := range tests {
+		obj, err := parseListLine([]byte(tt.line), tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error: %t, got: %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if obj.Objname != tt.wantObjname || obj.Link != tt.wantLink {
+			t.Errorf("%s: expected %q -> %q, got %q -> %q", tt.name, tt.wantObjname, tt.wantLink, obj.Objname, obj.Link)
+		}
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		format       string
+		wantObjnames []string
+		wantErr      bool
+	}{
+		{"empty", "", cmn.DlListFormatTxt, nil, false},
+		{"blank lines", "\n\n  \nhttp://example.com/a\n\t\nhttp://example.com/b\n\n", cmn.DlListFormatTxt, []string{"a", "b"}, false},
+		{"comments", "# links\nhttp://example.com/a\n  # http://example.com/b\n", cmn.DlListFormatTxt, []string{"a"}, false},
+		{"last line without newline", "http://example.com/a\nhttp://example.com/b", cmn.DlListFormatTxt, []string{"a", "b"}, false},
+		{"crlf", "http://example.com/a\r\nhttp://example.com/b\r\n", cmn.DlListFormatTxt, []string{"a", "b"}, false},
+		{"csv", "http://example.com/a,x\nhttp://example.com/b\n", cmn.DlListFormatCSV, []string{"x", "b"}, false},
+		{"jsonl", "{\"link\": \"http://example.com/a\"}\n\n# comment\n{\"link\": \"http://example.com/b\", \"objname\": \"y\"}", cmn.DlListFormatJSONL, []string{"a", "y"}, false},
+
+		{"malformed line", "http://example.com/a\nhttp://example.com/b,c,d\n", cmn.DlListFormatCSV, nil, true},
+		{"malformed last line", "{\"link\": \"http://example.com/a\"}\n{\"link\":", cmn.DlListFormatJSONL, nil, true},
+	}
+	for _, tt := range tests {
+		objs, err := parsePage([]byte(tt.data), tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error: %t, got: %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if len(objs) != len(tt.wantObjnames) {
+			t.Errorf("%s: expected %d objects, got %d", tt.name, len(tt.wantObjnames), len(objs))
+			continue
+		}
+		for i, obj := range objs {
+			if obj.Objname != tt.wantObjnames[i] {
+				t.Errorf("%s: expected object %q, got %q", tt.name, tt.wantObjnames[i], obj.Objname)
+			}
+		}
+	}
+}
+
+func TestJobListCheck(t *testing.T) {
+	newSmap := func(tids ...string) *cluster.Smap {
+		smap := &cluster.Smap{Tmap: make(cluster.NodeMap, len(tids))}
+		for _, tid := range tids {
+			smap.Tmap[tid] = &cluster.Snode{DaemonID: tid}
+		}
+		return smap
+	}
+	tests := []struct {
+		name     string
+		current  *cluster.Smap
+		done     []string
+		run      int64
+		wantDone bool
+	}{
+		{"none done", newSmap("t1", "t2", "t3"), nil, 0, false},
+		{"some done", newSmap("t1", "t2", "t3"), []string{"t2"}, 0, false},
+		{"all done", newSmap("t1", "t2", "t3"), []string{"t2", "t3"}, 0, true},
+		{"done in previous run", newSmap("t1", "t2", "t3"), []string{"t2", "t3"}, 1, false},
+		{"target joined", newSmap("t1", "t2", "t3", "t4"), []string{"t2", "t3"}, 0, true},
+		{"target left", newSmap("t1", "t2"), []string{"t2"}, 0, true},
+	}
+	for _, tt := range tests {
+		jl := &jobList{}
+		for _, tid := range tt.done {
+			jl.add(tid, &ListObjs{Done: true})
+		}
+		_, pending, done := jl.check(newSmap("t1", "t2", "t3"), tt.current, "t1", tt.run)
+		if pending {
+			t.Errorf("%s: expected no pending objects", tt.name)
+		}
+		if done != tt.wantDone {
+			t.Errorf("%s: expected done: %t, got: %t", tt.name, tt.wantDone, done)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// ================================ Manifest ===================================
+//
+// When the job has a manifest requested, each target collects the objects of
+// the job which it has downloaded (or which already existed). Once all the
+// objects of the job which belong to the target are processed, the target
+// sends them to the target which owns the manifest object (according to HRW
+// and the cluster map of the job - see: DownloadJobInfo.smap). The owner writes
+// the manifest once it has received the objects from all the targets of the
+// job which are still in the cluster. If the targets do not make any progress
+// for manifestWaitTimeout the error is persisted and the manifest is not
+// written.
+//
+// ================================ Manifest ===================================
+
+const (
+	// Number of times sending the objects to the owner of the manifest is retried.
+	manifestSendRetries = 3
+
+	// Maximal time the owner waits for the next target to send its objects.
+	manifestWaitTimeout = time.Hour
+	manifestWaitPoll    = time.Second
+)
+
+type (
+	jobManifest struct {
+		sync.Mutex
+		local    []*cmn.ManifestShard // objects processed by this target
+		all      []*cmn.ManifestShard // objects received from all targets (only on the owner)
+		received cmn.StringSet        // targets which have sent their objects (only on the owner)
+		notifyCh chan struct{}        // signaled when the objects are received (only on the owner)
+		sent     atomic.Bool
+	}
+)
+
+func (is *infoStore) addManifestShard(id string, lom *cluster.LOM) {
+	jInfo, err := is.getJob(id)
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	if jInfo.ManifestName == "" {
+		return
+	}
+
+	shard := &cmn.ManifestShard{
+		Name:    lom.Objname,
+		Size:    lom.Size(),
+		Version: lom.Version(),
+	}
+	shard.SetCksum(lom.Cksum())
+	jInfo.manifest.Lock()
+	jInfo.manifest.local = append(jInfo.manifest.local, shard)
+	jInfo.manifest.Unlock()
+}
+
+// checkManifest sends the objects of the job to the owner of the manifest if
+// all the objects of the job which belong to this target have been processed.
+// It must be called whenever the job makes progress.
+func (d *Downloader) checkManifest(id string) {
+	jInfo, err := dlStore.getJob(id)
+	if err != nil || jInfo.ManifestName == "" || jInfo.Aborted.Load() || !jInfo.AllDispatched.Load() {
+		return
+	}
+	if jInfo.ScheduledCnt.Load() != jInfo.FinishedCnt.Load()+jInfo.ErrorCnt.Load() {
+		return
+	}
+	if !jInfo.manifest.sent.CAS(false, true) {
+		return
+	}
+	go d.sendManifest(jInfo)
+}
+
+func (d *Downloader) sendManifest(jInfo *DownloadJobInfo) {
+	jInfo.manifest.Lock()
+	shards := jInfo.manifest.local
+	jInfo.manifest.local = nil
+	jInfo.manifest.Unlock()
+
+	lom := &cluster.LOM{T: d.t, Objname: jInfo.ManifestName}
+	if err := lom.Init(jInfo.Bucket, jInfo.Provider); err != nil {
+		glog.Errorf("failed to send manifest of download job %s, err: %v", jInfo.ID, err)
+		return
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), jInfo.smap)
+	if err == nil && d.t.GetSowner().Get().GetTarget(si.DaemonID) == nil {
+		err = fmt.Errorf("owner %s has left the cluster", si)
+	}
+	if err != nil {
+		glog.Errorf("failed to send manifest of download job %s, err: %v", jInfo.ID, err)
+		dlStore.persistError(jInfo.ID, jInfo.ManifestName, err.Error())
+		return
+	}
+	if si.DaemonID == d.t.Snode().DaemonID {
+		if err, _ := d.ReceiveManifest(jInfo.ID, si.DaemonID, shards); err != nil {
+			glog.Error(err)
+		}
+		return
+	}
+
+	var (
+		body  = cmn.MustMarshal(shards)
+		sleep = time.Second
+	)
+	for i := 0; i < manifestSendRetries; i++ {
+		if err = sendToTarget(d.t, si, cmn.Manifest, jInfo.ID, body); err == nil {
+			return
+		}
+		glog.Warningf("failed to send manifest of download job %s to %s (retrying in %v), err: %v", jInfo.ID, si, sleep, err)
+		time.Sleep(sleep)
+		sleep *= 2
+	}
+	glog.Errorf("failed to send manifest of download job %s to %s, err: %v", jInfo.ID, si, err)
+	dlStore.persistError(jInfo.ID, jInfo.ManifestName, err.Error())
+}
+
+// ReceiveManifest registers the objects of the job sent by the target. Once
+// all the targets of the job have sent their objects, the manifest is written.
+func (d *Downloader) ReceiveManifest(id, daemonID string, shards []*cmn.ManifestShard) (error, int) {
+	jInfo, err := dlStore.getJob(id)
+	if err != nil {
+		return err, http.StatusNotFound
+	}
+	if jInfo.smap.GetTarget(daemonID) == nil {
+		return fmt.Errorf("%s is not a target of download job %s", daemonID, id), http.StatusBadRequest
+	}
+
+	jInfo.manifest.Lock()
+	if jInfo.manifest.received == nil {
+		jInfo.manifest.received = make(cmn.StringSet)
+		jInfo.manifest.notifyCh = make(chan struct{}, 1)
+		go d.waitManifest(jInfo, jInfo.manifest.notifyCh)
+	}
+	if !jInfo.manifest.received.Contains(daemonID) {
+		jInfo.manifest.received.Add(daemonID)
+		jInfo.manifest.all = append(jInfo.manifest.all, shards...)
+		notify(jInfo.manifest.notifyCh)
+	}
+	jInfo.manifest.Unlock()
+	return nil, http.StatusOK
+}
+
+// waitManifest waits until all the targets of the job have sent their objects
+// and writes the manifest. It gives up if the job has been aborted or reset
+// (ie. notifyCh has changed) or if the targets do not make progress for
+// manifestWaitTimeout.
+func (d *Downloader) waitManifest(jInfo *DownloadJobInfo, notifyCh chan struct{}) {
+	deadline := time.Now().Add(manifestWaitTimeout)
+	for {
+		done, current := jInfo.manifest.check(jInfo.smap, d.t.GetSowner().Get(), notifyCh)
+		if !current {
+			return
+		}
+		if done {
+			d.writeManifest(jInfo)
+			return
+		}
+		select {
+		case <-notifyCh:
+			deadline = time.Now().Add(manifestWaitTimeout)
+		case <-time.After(manifestWaitPoll):
+			if jInfo.Aborted.Load() {
+				return
+			}
+			if time.Now().After(deadline) {
+				err := fmt.Errorf("timed out waiting for the targets to send the manifest (%v)", manifestWaitTimeout)
+				glog.Errorf("download job %s: %v", jInfo.ID, err)
+				dlStore.persistError(jInfo.ID, jInfo.ManifestName, err.Error())
+				return
+			}
+		}
+	}
+}
+
+func (d *Downloader) writeManifest(jInfo *DownloadJobInfo) {
+	jInfo.manifest.Lock()
+	manifest := &cmn.DatasetManifest{
+		JobID:    jInfo.ID,
+		Kind:     cmn.ActDownload,
+		Bucket:   jInfo.Bucket,
+		Provider: jInfo.Provider,
+		Shards:   jInfo.manifest.all,
+	}
+	jInfo.manifest.all = nil
+	jInfo.manifest.Unlock()
+	sort.Slice(manifest.Shards, func(i, j int) bool { return manifest.Shards[i].Name < manifest.Shards[j].Name })
+
+	lom := &cluster.LOM{T: d.t, Objname: jInfo.ManifestName}
+	err := lom.Init(jInfo.Bucket, jInfo.Provider)
+	if err == nil {
+		var (
+			body    = cmn.MustMarshal(manifest)
+			workFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+		)
+		lom.SetAtimeUnix(time.Now().UnixNano())
+		err = d.t.PutObject(workFQN, ioutil.NopCloser(bytes.NewReader(body)), lom, cluster.WarmGet, nil, time.Now())
+	}
+	if err != nil {
+		glog.Errorf("failed to write manifest %s of download job %s, err: %v", lom, jInfo.ID, err)
+		return
+	}
+	glog.Infof("download job %s has written manifest %s with %d objects", jInfo.ID, lom, len(manifest.Shards))
+}
+
+// check returns true if all the targets of the job which are still in the
+// (current) cluster map have sent their objects; current is false if the
+// manifest has been reset since notifyCh was created.
+func (m *jobManifest) check(smap, currentSmap *cluster.Smap, notifyCh chan struct{}) (done, current bool) {
+	m.Lock()
+	defer m.Unlock()
+	if m.notifyCh != notifyCh {
+		return false, false
+	}
+	for tid := range smap.Tmap {
+		if currentSmap.GetTarget(tid) != nil && !m.received.Contains(tid) {
+			return false, true
+		}
+	}
+	return true, true
+}
@@ -0,0 +1,114 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// ================================ Priorities =================================
+//
+// Concurrent jobs share the downloader in proportion to their priorities
+// (see: cmn.DlBase.Priority) so that a huge job does not starve the small
+// ones. The jobs are scheduled by stride scheduling, both by the dispatcher
+// (which dispatches the objects of the jobs to the joggers) and by each
+// jogger's queue (which picks the next task to download):
+//  * each job has a pass which is advanced by the stride of the job
+//    (inversely proportional to its priority) every time the job is
+//    scheduled,
+//  * the job with the lowest pass is scheduled next,
+//  * a job which joins (or rejoins) starts at the pass of the most recently
+//    scheduled job so that it does not monopolize the downloader.
+//
+// The priority can be changed while the job is running, the new priority
+// applies to the next scheduling of the job.
+//
+// Paused jobs are skipped by both the dispatcher and the joggers: their
+// objects are neither dispatched nor downloaded (the tasks which are already
+// being downloaded are finished) until they are resumed. Queued tasks of the
+// paused jobs do not take space in the joggers' queues. The state is kept in
+// the job's info (see: infoStore) so that it outlives the downloader and
+// applies to all subsequent runs of the sync job.
+//
+// ================================ Priorities =================================
+
+const (
+	// Stride of the job with the lowest priority.
+	strideBase = uint64(cmn.DlMaxPriority * 1000)
+)
+
+type (
+	// dispatchJob is the job which is being dispatched by the dispatcher.
+	dispatchJob struct {
+		job  DlJob
+		objs []cmn.DlObj // generated but not yet dispatched objects
+		pass uint64
+	}
+)
+
+// jobStride returns the value by which the pass of the job is advanced every
+// time it is scheduled.
+func jobStride(id string) uint64 {
+	priority := cmn.DlDefaultPriority
+	if jInfo, err := dlStore.getJob(id); err == nil {
+		priority = int(jInfo.Priority.Load())
+	}
+	if priority < cmn.DlMinPriority {
+		priority = cmn.DlMinPriority
+	}
+	return strideBase / uint64(priority)
+}
+
+// lessPass returns true if the job with (pass, id) should be scheduled before
+// the job with (otherPass, otherID). Ties are broken by IDs to make the order
+// deterministic.
+func lessPass(pass uint64, id string, otherPass uint64, otherID string) bool {
+	if pass != otherPass {
+		return pass < otherPass
+	}
+	return id < otherID
+}
+
+// next returns the next object of the job to dispatch, false if all objects
+// of the job have been dispatched.
+func (dj *dispatchJob) next() (obj cmn.DlObj, ok bool) {
+	for len(dj.objs) == 0 {
+		if dj.objs, ok = dj.job.GenNext(); !ok {
+			return obj, false
+		}
+	}
+	obj, dj.objs = dj.objs[0], dj.objs[1:]
+	return obj, true
+}
+
+func (is *infoStore) isPaused(id string) bool {
+	jInfo, err := is.getJob(id)
+	return err == nil && jInfo.Paused.Load()
+}
+
+func (is *infoStore) setPaused(id string, paused bool) error {
+	jInfo, err := is.getJob(id)
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	jInfo.Paused.Store(paused)
+	is.updateSyncJob(id, func(sj *SyncJob) { sj.Paused = paused })
+	return nil
+}
+
+func (is *infoStore) setPriority(id string, priority int) error {
+	jInfo, err := is.getJob(id)
+	if err != nil {
+		glog.Error(err)
+		return err
+	}
+
+	jInfo.Priority.Store(int32(priority))
+	is.updateSyncJob(id, func(sj *SyncJob) { sj.Priority = priority })
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/OneOfOne/xxhash"
+)
+
+// ================================ Resume =====================================
+//
+// Objects downloaded from a link are first written to a partial file which is
+// stored next to the object (see PartialFileType). When the download is
+// interrupted, the next attempt continues from the end of the partial file
+// with a `Range` request. The `If-Range` header (ETag or Last-Modified of the
+// first response) makes sure that the source has not changed in the meantime;
+// otherwise the source responds with the whole content and the download
+// starts from the beginning.
+//
+// The information required to resume the download is persisted in the
+// downloaderDB so that the download can be resumed even after the target has
+// been restarted (when the same object is downloaded from the same link again).
+// The partial file is synced before its size is persisted so that only the
+// data which is surely on the disk is reused. The link is persisted without
+// the signature (of signed URLs) so that no credentials are stored and the
+// download can be resumed with a freshly signed link.
+//
+// Objects smaller than resumeMinSize, and objects which cannot be resumed
+// (the source provides no validator), are streamed directly into the cluster
+// without the partial file - they are downloaded from the beginning again when
+// interrupted.
+//
+// ================================ Resume =====================================
+
+const (
+	// PartialFileType is the content type of the files which hold the
+	// content of the objects which are being downloaded.
+	PartialFileType = "dlpart"
+
+	// Number of bytes after which the partial file is synced and the offset
+	// from which the download can be resumed is persisted.
+	resumeSyncInterval = 64 * cmn.MiB
+	// Objects smaller than this (if their size is known) are not resumed.
+	resumeMinSize = resumeSyncInterval
+	// Time after which the partial downloads which have not been resumed
+	// are removed.
+	resumeExpiration = 7 * 24 * time.Hour
+)
+
+var (
+	_ fs.ContentResolver = &PartialFile{}
+
+	// Query parameters of signed URLs (S3, GCS, Azure) which carry the
+	// signature, credentials or expiration rather than identify the object.
+	signatureParams = cmn.StringSet{
+		"signature": {}, "expires": {}, "awsaccesskeyid": {}, "googleaccessid": {}, "keyname": {},
+		"sig": {}, "se": {}, "st": {}, "sp": {}, "sv": {}, "sr": {}, "spr": {}, "sip": {}, "ss": {}, "srt": {},
+	}
+	signatureParamPrefixes = []string{"x-amz-", "x-goog-"}
+)
+
+type (
+	PartialFile struct{}
+
+	// resumeInfo describes the partially downloaded object.
+	resumeInfo struct {
+		Uname string `json:"uname"`
+		FQN   string `json:"fqn"`
+		Link  string `json:"link"`
+		// ETag or Last-Modified of the source - used in If-Range header.
+		Validator string `json:"validator"`
+		// Number of bytes of the partial file which have been synced.
+		Offset int64 `json:"offset,string"`
+		// Total size of the object, zero if unknown.
+		Size       int64     `json:"size,string"`
+		CksumType  string    `json:"cksum_type,omitempty"`
+		CksumValue string    `json:"cksum_value,omitempty"`
+		Updated    time.Time `json:"updated"`
+		// Chunks of the object if it is downloaded in chunks, see: chunks.go.
+		Chunks []*resumeChunk `json:"chunks,omitempty"`
+		// The object has been stored directly, without the partial file.
+		direct bool
+	}
+)
+
+func (pf *PartialFile) PermToEvict() bool                  { return false }
+func (pf *PartialFile) PermToMove() bool                   { return false }
+func (pf *PartialFile) PermToProcess() bool                { return false }
+func (pf *PartialFile) GenUniqueFQN(base, _ string) string { return base }
+func (pf *PartialFile) ParseUniqueFQN(base string) (orig string, old bool, ok bool) {
+	return base, false, true
+}
+
+// resumeLink returns the link without the query parameters of signed URLs.
+func resumeLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.RawQuery == "" {
+		return link
+	}
+	query := u.Query()
+	for key := range query {
+		lkey := strings.ToLower(key)
+		if signatureParams.Contains(lkey) {
+			query.Del(key)
+			continue
+		}
+		for _, prefix := range signatureParamPrefixes {
+			if strings.HasPrefix(lkey, prefix) {
+				query.Del(key)
+				break
+			}
+		}
+	}
+	u.User = nil
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func resumeKey(uname string) string {
+	return strconv.FormatUint(xxhash.ChecksumString64S(uname, cmn.MLCG32), 16)
+}
+
+// resumeValidator returns the value of If-Range header which can be used to
+// resume the download of the response. Weak ETags cannot be used in If-Range.
+func resumeValidator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// contentRangeStart parses the start of the range from Content-Range header
+// (eg. "bytes 100-199/1000").
+func contentRangeStart(resp *http.Response) (int64, error) {
+	cr := resp.Header.Get("Content-Range")
+	if !strings.HasPrefix(cr, "bytes ") {
+		return 0, fmt.Errorf("invalid Content-Range header: %q", cr)
+	}
+	cr = strings.TrimPrefix(cr, "bytes ")
+	idx := strings.Index(cr, "-")
+	if idx < 0 {
+		return 0, fmt.Errorf("invalid Content-Range header: %q", cr)
+	}
+	return strconv.ParseInt(cr[:idx], 10, 64)
+}
+
+// resumable returns true if the download should go through the partial file
+// so that it can be resumed.
+func (ri *resumeInfo) resumable() bool {
+	return ri.Validator != "" && (ri.Size == 0 || ri.Size >= resumeMinSize)
+}
+
+// reset makes the info describe the download from the beginning.
+func (ri *resumeInfo) reset() {
+	ri.Offset, ri.Size, ri.Validator, ri.Chunks = 0, 0, "", nil
+}
+
+func (ri *resumeInfo) cksum() *cmn.Cksum {
+	if ri.CksumType == "" {
+		return nil
+	}
+	return cmn.NewCksum(ri.CksumType, ri.CksumValue)
+}
+
+func (ri *resumeInfo) setCksum(cksum *cmn.Cksum) {
+	ri.CksumType, ri.CksumValue = "", ""
+	if cksum != nil {
+		ri.CksumType, ri.CksumValue = cksum.Get()
+	}
+}
+
+// loadResumeInfo returns the information about the partial download of the
+// object from the link. If there is none (or it is not usable) the returned
+// info describes the download from the beginning.
+func (is *infoStore) loadResumeInfo(uname, fqn, link string) *resumeInfo {
+	link = resumeLink(link)
+	ri := &resumeInfo{}
+	if err := is.getResumeInfo(resumeKey(uname), ri); err == nil &&
+		ri.Uname == uname && ri.Link == link && ri.FQN == fqn && ri.Validator != "" {
+		if finfo, err := os.Stat(fqn); err == nil && finfo.Size() >= ri.Offset {
+			return ri
+		}
+	}
+	return &resumeInfo{Uname: uname, FQN: fqn, Link: link}
+}
+
+func (is *infoStore) saveResumeInfo(ri *resumeInfo) {
+	ri.Updated = time.Now()
+	if err := is.persistResumeInfo(resumeKey(ri.Uname), ri); err != nil {
+		glog.Error(err)
+	}
+}
+
+// removeResumeInfo removes both the information and the partial file.
+func (is *infoStore) removeResumeInfo(ri *resumeInfo) {
+	is.deleteResumeInfo(resumeKey(ri.Uname))
+	if err := os.Remove(ri.FQN); err != nil && !os.IsNotExist(err) {
+		glog.Error(err)
+	}
+}
+
+// housekeepResume removes partial downloads which have not been resumed for
+// a long time.
+func (is *infoStore) housekeepResume() {
+	infos, err := is.getAllResumeInfo()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	for _, ri := range infos {
+		if time.Since(ri.Updated) > resumeExpiration {
+			is.removeResumeInfo(ri)
+		}
+	}
+}
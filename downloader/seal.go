@@ -0,0 +1,117 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ================================ Seal =======================================
+//
+// The headers of the sources (eg. authorization, cookies) are persisted with
+// the sync jobs only in the encrypted form (AES-GCM). The key is generated by
+// each target when needed for the first time and stored, readable only by the
+// owner, next to the downloader's database (see: sealKeyPath). The headers
+// are never returned in the status or the list of downloads.
+//
+// ================================ Seal =======================================
+
+const (
+	sealKeyPath = "downloader.key" // base name of the file with the key
+	sealKeySize = 32               // AES-256
+)
+
+var (
+	sealAEAD struct {
+		once sync.Once
+		aead cipher.AEAD
+		err  error
+	}
+)
+
+func loadSealKey() (key []byte, err error) {
+	fqn := filepath.Join(cmn.GCO.Get().Confdir, sealKeyPath)
+	if key, err = ioutil.ReadFile(fqn); err == nil {
+		if len(key) != sealKeySize {
+			return nil, fmt.Errorf("invalid key %q: expected %d bytes, got %d", fqn, sealKeySize, len(key))
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	key = make([]byte, sealKeySize)
+	if _, err = io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(fqn, key, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func sealCipher() (cipher.AEAD, error) {
+	sealAEAD.once.Do(func() {
+		var (
+			key   []byte
+			block cipher.Block
+		)
+		if key, sealAEAD.err = loadSealKey(); sealAEAD.err != nil {
+			return
+		}
+		if block, sealAEAD.err = aes.NewCipher(key); sealAEAD.err != nil {
+			return
+		}
+		sealAEAD.aead, sealAEAD.err = cipher.NewGCM(block)
+	})
+	return sealAEAD.aead, sealAEAD.err
+}
+
+// sealHeader encrypts the headers; the nonce is prepended to the result.
+func sealHeader(header http.Header) ([]byte, error) {
+	aead, err := sealCipher()
+	if err != nil {
+		return nil, err
+	}
+	b, err := jsoniter.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(b)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, b, nil), nil
+}
+
+// openHeader decrypts the headers encrypted with sealHeader.
+func openHeader(sealed []byte) (header http.Header, err error) {
+	aead, err := sealCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("sealed headers are too short")
+	}
+	nonce, b := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	if b, err = aead.Open(nil, nonce, b, nil); err != nil {
+		return nil, err
+	}
+	err = jsoniter.Unmarshal(b, &header)
+	return
+}
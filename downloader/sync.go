@@ -0,0 +1,258 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// ================================ Sync =======================================
+//
+// The sync job (see: cmn.DlBase.SyncInterval) is run every sync interval
+// since it has been started, until it is aborted or removed. A run which is
+// due while the previous run is still downloading is skipped. Each run
+// downloads only the objects which do not exist yet or which have changed
+// since they were downloaded:
+//  * cloud objects are compared by version with the existing objects or, if
+//    the cloud bucket is not versioned, by size and ETag (as listed by the
+//    cloud provider) with the existing object and the ETag which has been
+//    listed when the object was downloaded by the sync job,
+//  * objects downloaded from links are requested conditionally (If-None-Match
+//    or If-Modified-Since) with the validator (ETag or Last-Modified) which
+//    the source has returned when the object was downloaded by the sync job.
+//    Objects without a validator are always downloaded again.
+//
+// The request which has started the job is persisted together with the start
+// of the schedule (see: SyncJob) so that the job is restored, and runs on its
+// original schedule, when the target restarts. The downloader does not time
+// out while any run is scheduled.
+//
+// ================================ Sync =======================================
+
+var (
+	// errNotModified is returned when the source of the existing object
+	// responds that the object has not changed.
+	errNotModified = errors.New("not modified")
+)
+
+type (
+	// SyncRequest is the request which has started the sync job: its query,
+	// body and the headers of the sources (see: cmn.DlBase.Headers). The
+	// headers are persisted only in the encrypted form (see: seal.go).
+	SyncRequest struct {
+		Query        url.Values  `json:"query"`
+		Header       http.Header `json:"-"`
+		SealedHeader []byte      `json:"sealed_header,omitempty"`
+		Body         []byte      `json:"body,omitempty"`
+	}
+
+	// SyncJob is persisted for each sync job so that it can be restored when
+	// the target restarts (see: LoadSyncJobs, Downloader.RestoreSyncJob).
+	SyncJob struct {
+		ID      string      `json:"id"`
+		Request SyncRequest `json:"request"`
+		Start   time.Time   `json:"start"` // the job is run every sync interval since start
+
+		// scheduling of the job changed since it has been started, see: priority.go
+		Paused   bool `json:"paused,omitempty"`
+		Priority int  `json:"priority,omitempty"` // zero if not changed
+	}
+
+	// syncInfo is persisted for each object downloaded from a link by
+	// the sync job.
+	syncInfo struct {
+		Uname     string `json:"uname"`
+		Link      string `json:"link"`
+		Validator string `json:"validator"`
+	}
+)
+
+// objChanged returns true if the existing object should be downloaded again
+// by the sync job. Objects downloaded from links are checked by the task.
+func objChanged(obj cmn.DlObj, lom *cluster.LOM) bool {
+	if !obj.FromCloud {
+		return true
+	}
+	if obj.Version != "" {
+		return obj.Version != lom.Version()
+	}
+	if obj.ETag == "" || obj.Size != lom.Size() {
+		return true
+	}
+	// ETag of a cloud object which has not been uploaded in parts is its MD5
+	if cksum := lom.Cksum(); cksum != nil {
+		if ty, val := cksum.Get(); ty == cmn.ChecksumMD5 && val == obj.ETag {
+			return false
+		}
+	}
+	return dlStore.loadSyncValidator(lom.Uname(), obj.Link) != obj.ETag
+}
+
+// setConditional makes the request conditional so that the source responds
+// with 304 (Not Modified) if the object has not changed.
+func setConditional(req *http.Request, validator string) {
+	if strings.HasPrefix(validator, `"`) || strings.HasPrefix(validator, "W/") {
+		req.Header.Set("If-None-Match", validator)
+	} else {
+		req.Header.Set("If-Modified-Since", validator)
+	}
+}
+
+func (is *infoStore) loadSyncValidator(uname, link string) string {
+	si := &syncInfo{}
+	if err := is.getSyncInfo(resumeKey(uname), si); err != nil {
+		if !os.IsNotExist(err) {
+			glog.Error(err)
+		}
+		return ""
+	}
+	if si.Uname != uname || si.Link != resumeLink(link) {
+		return ""
+	}
+	return si.Validator
+}
+
+func (is *infoStore) saveSyncValidator(uname, link, validator string) {
+	key := resumeKey(uname)
+	if validator == "" {
+		is.deleteSyncInfo(key)
+		return
+	}
+	si := &syncInfo{Uname: uname, Link: resumeLink(link), Validator: validator}
+	if err := is.persistSyncInfo(key, si); err != nil {
+		glog.Error(err)
+	}
+}
+
+// LoadSyncJobs returns the persisted sync jobs.
+func LoadSyncJobs() ([]*SyncJob, error) {
+	return dlStore.getAllSyncJobs()
+}
+
+// nextSyncTime returns the first time after now which is a whole number of
+// sync intervals after the start of the sync job.
+func nextSyncTime(start time.Time, interval time.Duration, now time.Time) time.Time {
+	if now.Before(start) {
+		return start
+	}
+	return start.Add((now.Sub(start)/interval + 1) * interval)
+}
+
+func (is *infoStore) saveSyncJob(id string, req *SyncRequest, start time.Time) {
+	sj := &SyncJob{ID: id, Request: *req, Start: start}
+	if err := is.downloaderDB.persistSyncJob(sj); err != nil {
+		glog.Errorf("failed to persist sync download job %s, err: %v", id, err)
+	}
+}
+
+// updateSyncJob updates the persisted sync job, if the job is one.
+func (is *infoStore) updateSyncJob(id string, update func(sj *SyncJob)) {
+	if err := is.downloaderDB.updateSyncJob(id, update); err != nil {
+		glog.Errorf("failed to persist sync download job %s, err: %v", id, err)
+	}
+}
+
+// scheduleSync schedules the next run of the sync job once its run has been
+// dispatched.
+func (d *dispatcher) scheduleSync(job DlJob) {
+	jInfo, err := dlStore.getJob(job.ID())
+	if err != nil {
+		return
+	}
+	if jInfo.Aborted.Load() || d.checkAborted() {
+		jInfo.NextSync.Store(time.Time{})
+		return
+	}
+
+	stopCh := make(chan struct{})
+	d.Lock()
+	d.syncJob[job.ID()] = stopCh
+	d.Unlock()
+	next := nextSyncTime(jInfo.syncStart, job.SyncInterval(), time.Now())
+	jInfo.NextSync.Store(next)
+
+	d.parent.IncPending()
+	go d.waitSync(job, jInfo, next, stopCh)
+}
+
+func (d *dispatcher) waitSync(job DlJob, jInfo *DownloadJobInfo, next time.Time, stopCh chan struct{}) {
+	defer d.parent.DecPending()
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-stopCh:
+			return
+		case <-d.stopCh.Listen():
+			jInfo.NextSync.Store(time.Time{})
+			return
+		}
+		if d.parent.getNumPending(job.ID()) == 0 {
+			break
+		}
+		next = nextSyncTime(jInfo.syncStart, job.SyncInterval(), time.Now())
+		glog.Warningf("download job %s: previous run is still downloading, skipping to %s",
+			job.ID(), next.Format(time.RFC3339))
+		jInfo.NextSync.Store(next)
+		timer.Reset(time.Until(next))
+	}
+
+	d.Lock()
+	if d.syncJob[job.ID()] != stopCh {
+		// canceled in the meantime
+		d.Unlock()
+		return
+	}
+	delete(d.syncJob, job.ID())
+	d.abortJob[job.ID()] = make(chan struct{}, 1)
+	d.Unlock()
+
+	if err := job.Reset(); err != nil {
+		glog.Errorf("failed to sync download job %s, err: %v", job.ID(), err)
+	}
+	dlStore.resetJob(job.ID(), d.parent.t.GetSowner().Get())
+	if objname, _ := job.CksumList(); objname != "" {
+		// The checksums may have changed together with the objects.
+		if cksums, err := d.parent.loadCksums(job); err == nil {
+			dlStore.setCksums(job, cksums)
+		} else {
+			glog.Errorf("download job %s: %v", job.ID(), err)
+			dlStore.persistError(job.ID(), objname, err.Error())
+		}
+	}
+	if glog.V(4) {
+		glog.Infof("starting next run of download job %s", job.ID())
+	}
+	select {
+	case d.dispatchDownloadCh <- job:
+	case <-d.stopCh.Listen():
+		jInfo.NextSync.Store(time.Time{})
+	}
+}
+
+// cancelSync cancels the scheduled run of the job, if any, for good.
+func (d *dispatcher) cancelSync(id string) {
+	d.Lock()
+	if stopCh, ok := d.syncJob[id]; ok {
+		close(stopCh)
+		delete(d.syncJob, id)
+	}
+	d.Unlock()
+	dlStore.deleteSyncJob(id)
+	if jInfo, err := dlStore.getJob(id); err == nil {
+		jInfo.NextSync.Store(time.Time{})
+	}
+}
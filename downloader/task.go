@@ -8,9 +8,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"net/url"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
@@ -18,12 +19,18 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/notif"
 	"github.com/NVIDIA/aistore/stats"
 )
 
 const (
 	// Number of retries when doing a request to external resource.
 	retryCnt = 3
+	// Maximum number of attempts to download an object (or its chunk),
+	// including the attempts which have made progress and are therefore not
+	// counted as retries.
+	maxAttemptCnt = 20
 )
 
 type (
@@ -34,15 +41,51 @@ type (
 		started time.Time
 		ended   time.Time
 
-		currentSize atomic.Int64 // the current size of the file (updated as the download progresses)
-		totalSize   int64        // the total size of the file (nonzero only if Content-Length header was provided by the source of the file)
-		finishedCh  chan error   // when a jogger finishes downloading a dlTask
+		chunkSize   int64             // objects larger than chunk size are downloaded in chunks (0 - use config)
+		limiter     *jobLimiter       // limits of the job, see: limits.go
+		headers     map[string]string // headers added to each request to the source (eg. authorization)
+		sync        bool              // task of the sync job - existing object is downloaded only if it has changed, see: sync.go
+		validator   string            // validator of the source of the existing object (sync job only)
+		cksumType   string            // type of the expected checksum of the object, see: cksum.go
+		cksumValue  string            // expected checksum of the object (empty if none)
+		currentSize atomic.Int64      // the current size of the file (updated as the download progresses)
+		totalSize   int64             // the total size of the file (nonzero only if Content-Length header was provided by the source of the file)
+		finishedCh  chan error        // when a jogger finishes downloading a dlTask
 
 		downloadCtx context.Context    // context with cancel function
 		cancelFunc  context.CancelFunc // used to cancel the download after the request commences
 	}
+
+	// partFile is the partial file of the object which is opened only when
+	// the download goes through it, see: resume.go.
+	partFile struct {
+		fqn  string
+		file *os.File
+	}
 )
 
+func (p *partFile) open() (*os.File, error) {
+	if p.file != nil {
+		return p.file, nil
+	}
+	if err := cmn.CreateDir(filepath.Dir(p.fqn)); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(p.fqn, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	p.file = file
+	return file, nil
+}
+
+func (p *partFile) close() error {
+	if p.file == nil {
+		return nil
+	}
+	return p.file.Close()
+}
+
 func (t *singleObjectTask) download() {
 	var (
 		statusMsg string
@@ -57,8 +100,13 @@ func (t *singleObjectTask) download() {
 		return
 	}
 	if err == nil {
-		t.abort(internalErrorMessage(), errors.New(lom.String()+" already exists"))
-		return
+		if !t.sync {
+			t.abort(internalErrorMessage(), errors.New(lom.String()+" already exists"))
+			return
+		}
+		if !t.obj.FromCloud {
+			t.validator = dlStore.loadSyncValidator(lom.Uname(), t.obj.Link)
+		}
 	}
 
 	if glog.V(4) {
@@ -74,11 +122,22 @@ func (t *singleObjectTask) download() {
 	}
 	t.ended = time.Now()
 
+	if err == errNotModified {
+		if glog.V(4) {
+			glog.Infof("%s has not changed - skipping", t)
+		}
+		if err := lom.Load(); err != nil {
+			t.abort(internalErrorMessage(), err)
+			return
+		}
+		err = nil
+	}
 	if err != nil {
 		t.abort(statusMsg, err)
 		return
 	}
 
+	dlStore.addManifestShard(t.id, lom)
 	if err := dlStore.incFinished(t.id); err != nil {
 		glog.Errorf(err.Error())
 	}
@@ -94,43 +153,271 @@ func (t *singleObjectTask) download() {
 
 func (t *singleObjectTask) downloadLocal(lom *cluster.LOM, started time.Time) (errMsg string, err error) {
 	var (
+		file    *os.File
+		written int64
 		postFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
-		req     *http.Request
-		resp    *http.Response
+		partFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, PartialFileType, "")
+		ri      = dlStore.loadResumeInfo(lom.Uname(), partFQN, t.obj.Link)
+		part    = &partFile{fqn: partFQN}
 	)
 
-	for i := 0; i < retryCnt; i++ {
-		// Create request
-		req, err = http.NewRequest(http.MethodGet, t.obj.Link, nil)
-		if err != nil {
+	// Every attempt continues where the previous one has stopped. The
+	// attempts are counted as retries only when there is no progress at all
+	// (or when the source throttles the requests for too long).
+	throttledCnt := 0
+	for i, attempt := 0, 0; i < retryCnt && attempt < maxAttemptCnt; i, attempt = i+1, attempt+1 {
+		if len(ri.Chunks) > 0 {
+			// Chunks are retried separately.
+			if errMsg, err = t.downloadChunks(part, ri); err != errSourceChanged {
+				break
+			}
+			ri.reset()
+			continue
+		}
+		written, errMsg, err = t.downloadRange(lom, part, ri)
+		if err == errUseChunks {
+			i--
+			continue
+		}
+		if err == nil || err == errNotModified || ri.direct || t.downloadCtx.Err() != nil {
+			break
+		}
+		if retryThrottled(err, &throttledCnt) {
+			i--
 			continue
 		}
+		if written > 0 {
+			i = -1
+		}
+		glog.Warningf("failed to download %s (resuming at %d), err: %v", t, ri.Offset, err)
+	}
+	if err == nil {
+		err = part.close()
+	} else {
+		part.close()
+	}
+	if err == errNotModified {
+		dlStore.removeResumeInfo(ri)
+		return
+	}
+	if err != nil {
+		// Keep the partial file so the download can be resumed later.
+		if errMsg == "" {
+			errMsg = internalErrorMessage()
+		}
+		return
+	}
+	if ri.direct {
+		if !ri.Updated.IsZero() {
+			// left over by the previous download from the source
+			dlStore.removeResumeInfo(ri)
+		}
+		return t.finalize(lom, ri)
+	}
 
-		// Set "User-Agent" header when doing requests to Google Cloud Storage.
-		// This should increase number of connections to GCS.
-		if cmn.IsGoogleStorageURL(req.URL) {
-			req.Header.Add("User-Agent", cmn.GcsUA)
+	if t.cksumValue != "" {
+		if err = verifyCksum(partFQN, t.cksumType, t.cksumValue); err != nil {
+			// Content is corrupted - the download must start from the beginning.
+			dlStore.removeResumeInfo(ri)
+			return err.Error(), err
 		}
+	}
+	if file, err = os.Open(partFQN); err != nil {
+		return internalErrorMessage(), err
+	}
+	// PutObject closes the file and validates the checksum.
+	if err := t.parent.t.PutObject(postFQN, file, lom, cluster.ColdGet, ri.cksum(), started); err != nil {
+		// Content is corrupted - the download must start from the beginning.
+		dlStore.removeResumeInfo(ri)
+		return internalErrorMessage(), err
+	}
+	dlStore.removeResumeInfo(ri)
+	return t.finalize(lom, ri)
+}
 
-		reqWithCtx := req.WithContext(t.downloadCtx)
-		resp, err = httpClient.Do(reqWithCtx)
-		if err != nil {
-			// Error returned by httpClient.Do() is a *url.Error
-			errMsg = httpClientErrorMessage(err.(*url.Error))
-			continue
+// finalize is called once the downloaded object has been stored.
+func (t *singleObjectTask) finalize(lom *cluster.LOM, ri *resumeInfo) (errMsg string, err error) {
+	if err := lom.Load(); err != nil {
+		return internalErrorMessage(), err
+	}
+	if t.sync {
+		dlStore.saveSyncValidator(lom.Uname(), t.obj.Link, ri.Validator)
+	}
+	notif.Notify(lom, cmn.NotifDownload)
+	return "", nil
+}
+
+// putDirect streams the response directly into the object (see: resume.go).
+// If the expected checksum is given, the response is first stored in the
+// partial file and verified so that the corrupted content never replaces the
+// object. Either way, the download is not retried.
+func (t *singleObjectTask) putDirect(lom *cluster.LOM, part *partFile, resp *http.Response, ri *resumeInfo) (written int64, errMsg string, err error) {
+	var (
+		body    io.ReadCloser
+		postFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut)
+		r       = &progressReader{
+			r: resp.Body,
+			reporter: func(n int64) {
+				written += n
+				t.currentSize.Add(n)
+			},
 		}
-		if resp.StatusCode >= http.StatusBadRequest {
-			errMsg = httpRequestErrorMessage(t.obj.Link, resp)
-			err = fmt.Errorf("status code: %d", resp.StatusCode)
-			continue
+	)
+	t.currentSize.Store(0)
+	t.totalSize = ri.Size
+	ri.direct = true
+	body = r
+	if t.cksumValue != "" {
+		defer func() {
+			if errRemove := os.Remove(part.fqn); errRemove != nil && !os.IsNotExist(errRemove) {
+				glog.Error(errRemove)
+			}
+		}()
+		if errMsg, err = t.spoolVerified(part, r); err != nil {
+			return
+		}
+		if body, err = os.Open(part.fqn); err != nil {
+			return written, internalErrorMessage(), err
 		}
+	}
+	// PutObject closes the body.
+	if err = t.parent.t.PutObject(postFQN, body, lom, cluster.ColdGet, ri.cksum(), t.started); err != nil {
+		return written, internalErrorMessage(), err
+	}
+	return
+}
 
-		break // no error, we can proceed
+// spoolVerified writes the whole content into the partial file and verifies
+// its expected checksum.
+func (t *singleObjectTask) spoolVerified(part *partFile, r io.Reader) (errMsg string, err error) {
+	file, err := part.open()
+	if err != nil {
+		return internalErrorMessage(), err
+	}
+	if err = file.Truncate(0); err != nil {
+		return internalErrorMessage(), err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return internalErrorMessage(), err
 	}
+	h := newCksumHash(t.cksumType)
+	buf, slab := memsys.GMM().AllocDefault()
+	_, err = io.CopyBuffer(io.MultiWriter(file, h), r, buf)
+	slab.Free(buf)
+	if err != nil {
+		return
+	}
+	if actual := cmn.HashToStr(h); actual != t.cksumValue {
+		err = fmt.Errorf("%s checksum mismatch: expected %s, got %s", t.cksumType, t.cksumValue, actual)
+		return err.Error(), err
+	}
+	return
+}
 
+// newRequest creates the request to the source with the headers of the job.
+func (t *singleObjectTask) newRequest() (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, t.obj.Link, nil)
 	if err != nil {
+		return nil, err
+	}
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	// Set "User-Agent" header when doing requests to Google Cloud Storage.
+	// This should increase number of connections to GCS.
+	if cmn.IsGoogleStorageURL(req.URL) {
+		req.Header.Add("User-Agent", cmn.GcsUA)
+	}
+	return req, nil
+}
+
+// downloadRange downloads the rest of the object (starting from the offset
+// of resume info) and appends it to the partial file.
+func (t *singleObjectTask) downloadRange(lom *cluster.LOM, part *partFile, ri *resumeInfo) (written int64, errMsg string, err error) {
+	var (
+		req  *http.Request
+		resp *http.Response
+		file *os.File
+	)
+	if req, err = t.newRequest(); err != nil {
 		return
 	}
+	if ri.Validator == "" {
+		ri.Offset = 0
+	} else if ri.Offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", ri.Offset))
+		req.Header.Set("If-Range", ri.Validator)
+	}
+	if ri.Offset == 0 && t.validator != "" {
+		setConditional(req, t.validator)
+	}
+
+	resp, err = t.limiter.do(t.downloadCtx, req)
+	if err != nil {
+		errMsg = limiterErrorMessage(err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && t.validator != "":
+		return 0, "", errNotModified
+	case resp.StatusCode == http.StatusPartialContent:
+		var start int64
+		if start, err = contentRangeStart(resp); err == nil && start != ri.Offset {
+			err = fmt.Errorf("unexpected Content-Range start: %d (expected: %d)", start, ri.Offset)
+		}
+		if err != nil {
+			// Something is wrong with the source - start from the beginning.
+			ri.Offset, ri.Validator = 0, ""
+			return
+		}
+		if cksum := getCksum(t.obj.Link, resp); cksum != nil {
+			ri.setCksum(cksum)
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && ri.Offset > 0 && ri.Offset == ri.Size:
+		// The partial file already contains the whole object.
+		t.currentSize.Store(ri.Offset)
+		t.totalSize = ri.Size
+		return
+	case resp.StatusCode >= http.StatusBadRequest:
+		errMsg = httpRequestErrorMessage(t.obj.Link, resp)
+		err = fmt.Errorf("status code: %d", resp.StatusCode)
+		if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			ri.Offset, ri.Validator = 0, ""
+		}
+		return
+	default:
+		// Source does not support ranges or the content has changed: start
+		// from the beginning.
+		ri.Offset = 0
+		ri.Validator = resumeValidator(resp)
+		ri.Size = 0
+		if resp.ContentLength > 0 {
+			ri.Size = resp.ContentLength
+		}
+		ri.setCksum(getCksum(t.obj.Link, resp))
+		if t.useChunks(resp, ri) {
+			dlStore.saveResumeInfo(ri)
+			return 0, "", errUseChunks
+		}
+		if !ri.resumable() {
+			return t.putDirect(lom, part, resp, ri)
+		}
+		dlStore.saveResumeInfo(ri)
+	}
+
+	if file, err = part.open(); err != nil {
+		return 0, internalErrorMessage(), err
+	}
+	if err = file.Truncate(ri.Offset); err != nil {
+		return
+	}
+	if _, err = file.Seek(ri.Offset, io.SeekStart); err != nil {
+		return
+	}
+	t.currentSize.Store(ri.Offset)
+	t.totalSize = ri.Size
 
 	// Create a custom reader to monitor progress every time we read from response body stream
 	progressReader := &progressReader{
@@ -140,16 +427,32 @@ func (t *singleObjectTask) downloadLocal(lom *cluster.LOM, started time.Time) (e
 		},
 	}
 
-	t.setTotalSize(resp)
-
-	cksum := getCksum(t.obj.Link, resp)
-	if err := t.parent.t.PutObject(postFQN, progressReader, lom, cluster.ColdGet, cksum, started); err != nil {
-		return internalErrorMessage(), err
+	buf, slab := memsys.GMM().AllocDefault()
+	defer slab.Free(buf)
+	for {
+		var n int64
+		n, err = io.CopyBuffer(file, io.LimitReader(progressReader, resumeSyncInterval), buf)
+		written += n
+		if n > 0 && ri.Validator != "" {
+			if err := file.Sync(); err != nil {
+				return written, internalErrorMessage(), err
+			}
+			ri.Offset += n
+			dlStore.saveResumeInfo(ri)
+		} else {
+			ri.Offset += n
+		}
+		if err != nil {
+			return
+		}
+		if n < resumeSyncInterval {
+			break
+		}
 	}
-	if err := lom.Load(); err != nil {
-		return internalErrorMessage(), err
+	if ri.Size > 0 && ri.Offset != ri.Size {
+		err = fmt.Errorf("downloaded %d bytes, expected: %d", ri.Offset, ri.Size)
 	}
-	return "", nil
+	return
 }
 
 func (t *singleObjectTask) setTotalSize(resp *http.Response) {
@@ -160,9 +463,26 @@ func (t *singleObjectTask) setTotalSize(resp *http.Response) {
 }
 
 func (t *singleObjectTask) downloadCloud(lom *cluster.LOM) (string, error) {
-	if err, _ := t.parent.t.GetCold(t.downloadCtx, lom, true /* prefetch */); err != nil {
+	var err error
+	if t.cksumValue != "" {
+		// The checksum is verified before the object gets stored.
+		var errCksum error
+		err, _ = t.parent.t.GetColdVerified(t.downloadCtx, lom, func(workFQN string) error {
+			errCksum = verifyCksum(workFQN, t.cksumType, t.cksumValue)
+			return errCksum
+		})
+		if errCksum != nil {
+			return errCksum.Error(), errCksum
+		}
+	} else {
+		err, _ = t.parent.t.GetCold(t.downloadCtx, lom, true /* prefetch */)
+	}
+	if err != nil {
 		return internalErrorMessage(), err
 	}
+	if t.sync && t.obj.Version == "" {
+		dlStore.saveSyncValidator(lom.Uname(), t.obj.Link, t.obj.ETag)
+	}
 	return "", nil
 }
 
@@ -5,6 +5,14 @@
 package downloader
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 )
@@ -21,6 +29,11 @@ func BuildDownloaderInput(t cluster.Target, id string, bck *cluster.Bck,
 	dlBody.Provider = bck.Provider
 	dlBody.Timeout = payload.Timeout
 	dlBody.Description = payload.Description
+	dlBody.Manifest = payload.Manifest
+	dlBody.ChunkSize = payload.ChunkSize
+	dlBody.Limits = payload.Limits
+	dlBody.HostLimits = payload.HostLimits
+	dlBody.Headers = payload.Headers
 
 	dlBody.Objs, err = GetTargetDlObjs(t, objects, bck, cloud)
 	return dlBody, err
@@ -57,3 +70,125 @@ func GetTargetDlObjs(t cluster.Target, objects cmn.SimpleKVs, bck *cluster.Bck,
 
 	return dlObjs, nil
 }
+
+// CountTargets returns the number of targets among which the objects are
+// distributed (according to HRW).
+func CountTargets(t cluster.Target, objects cmn.SimpleKVs, bck *cluster.Bck) (int, error) {
+	var (
+		smap    = t.GetSowner().Get()
+		targets = make(map[string]struct{}, smap.CountTargets())
+	)
+	for objName := range objects {
+		objName, err := cmn.NormalizeObjName(objName)
+		if err != nil {
+			return 0, err
+		}
+		si, err := cluster.HrwTarget(bck.MakeUname(objName), smap)
+		if err != nil {
+			return 0, err
+		}
+		targets[si.ID()] = struct{}{}
+	}
+	return len(targets), nil
+}
+
+// statObject returns the size of the object stored in the cluster.
+func statObject(t cluster.Target, bck *cluster.Bck, objname string) (int64, error) {
+	si, err := cluster.HrwTarget(bck.MakeUname(objname), t.GetSowner().Get())
+	if err != nil {
+		return 0, err
+	}
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodHead,
+		Base:   si.URL(cmn.NetworkIntraControl),
+		Path:   cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, objname),
+		Query:  url.Values{cmn.URLParamProvider: []string{bck.Provider}},
+	}
+	req, err := reqArgs.Req()
+	if err != nil {
+		return 0, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return 0, fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+	return strconv.ParseInt(resp.Header.Get(cmn.HeaderObjSize), 10, 64)
+}
+
+// readRange reads (at most) length bytes of the object stored in the cluster
+// starting at the offset.
+func readRange(t cluster.Target, bck *cluster.Bck, objname string, offset, length int64) ([]byte, error) {
+	query := url.Values{}
+	query.Set(cmn.URLParamOffset, strconv.FormatInt(offset, 10))
+	query.Set(cmn.URLParamLength, strconv.FormatInt(length, 10))
+	r, err := openObject(t, bck, objname, query)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// sendToTarget sends the body to the downloader of the target (see: manifest.go
+// and list.go), what is the last element of the path.
+func sendToTarget(t cluster.Target, si *cluster.Snode, what, id string, body []byte) error {
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodPut,
+		Header: http.Header{cmn.HeaderCallerID: []string{t.Snode().DaemonID}},
+		Base:   si.URL(cmn.NetworkIntraControl),
+		Path:   cmn.URLPath(cmn.Version, cmn.Download, what),
+		Query:  url.Values{cmn.URLParamID: []string{id}},
+		Body:   body,
+	}
+	req, err := reqArgs.Req()
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// openObject opens the object stored in the cluster for reading (from the
+// target which stores it). The query may restrict the range of the object
+// (offset and length).
+func openObject(t cluster.Target, bck *cluster.Bck, objname string, query url.Values) (io.ReadCloser, error) {
+	si, err := cluster.HrwTarget(bck.MakeUname(objname), t.GetSowner().Get())
+	if err != nil {
+		return nil, err
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set(cmn.URLParamProvider, bck.Provider)
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodGet,
+		Base:   si.URL(cmn.NetworkIntraData),
+		Path:   cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, objname),
+		Query:  query,
+	}
+	req, err := reqArgs.Req()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		b, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("status code: %d, %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+	return resp.Body, nil
+}
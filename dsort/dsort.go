@@ -190,6 +190,11 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction, cfg *cmn.D
 
 		expectedUncompressedSize := uint64(float64(lom.Size()) / m.avgCompressionRatio())
 		toDisk := m.dsorter.preShardExtraction(expectedUncompressedSize)
+		if m.rs.Algorithm.oversamples() {
+			// Duplicated records are loaded more than once, whereas records
+			// extracted to memory are freed after the first load.
+			toDisk = true
+		}
 
 		beforeExtraction := time.Now()
 		reader := io.NewSectionReader(f, 0, lom.Size())
@@ -299,6 +304,18 @@ ExtractAllShards:
 	return nil
 }
 
+// createShardIndex creates the index sidecar of the shard if it was requested.
+// The index is created by the target which stores the shard.
+func (m *Manager) createShardIndex(lom *cluster.LOM) error {
+	if !m.rs.ShardIndex || m.rs.DryRun || m.rs.StreamOutput {
+		return nil
+	}
+	lom.Lock(false)
+	_, err := extract.CreateShardIndex(lom)
+	lom.Unlock(false)
+	return err
+}
+
 func (m *Manager) createShard(s *extract.Shard) (err error) {
 	var (
 		loadContent = m.dsorter.loadContent()
@@ -338,18 +355,26 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 	beforeCreation := time.Now()
 
 	var (
-		wg   = &sync.WaitGroup{}
-		r, w = io.Pipe()
-		n    int64
+		wg          = &sync.WaitGroup{}
+		r, w        = io.Pipe()
+		n           int64
+		ctx, cancel = m.abortContext()
 	)
+	defer cancel()
 	wg.Add(1)
 	go func() {
 		var err error
-		if !m.rs.DryRun {
+		if m.rs.DryRun {
+			n, err = io.Copy(ioutil.Discard, r)
+		} else if m.rs.StreamOutput {
+			n, err = m.streamToCloud(ctx, lom, r)
+		} else {
 			err = m.ctx.t.PutObject(workFQN, r, lom, cluster.WarmGet, nil, beforeCreation)
 			n = lom.Size()
-		} else {
-			n, err = io.Copy(ioutil.Discard, r)
+		}
+		if err != nil {
+			// unblock the shard creation which would otherwise wait on the pipe forever
+			r.CloseWithError(err)
 		}
 		errCh <- err
 		wg.Done()
@@ -388,7 +413,9 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 	// according to HRW, send it there. Since it doesn't really matter
 	// if we have an extra copy of the object local to this target, we
 	// optimize for performance by not removing the object now.
-	if si.DaemonID != m.ctx.node.DaemonID && !m.rs.DryRun {
+	//
+	// NOTE: Shards streamed to the cloud are not stored locally at all.
+	if si.DaemonID != m.ctx.node.DaemonID && !m.rs.DryRun && !m.rs.StreamOutput {
 		lom.Lock(false)
 		defer lom.Unlock(false)
 
@@ -428,12 +455,20 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 		if err := <-errCh; err != nil {
 			return err
 		}
+	} else if si.DaemonID == m.ctx.node.DaemonID {
+		if err = m.createShardIndex(lom); err != nil {
+			return err
+		}
 	}
 
 exit:
+	m.addCreatedShard(s, lom, n)
+
 	metrics.Lock()
 	metrics.CreatedCnt++
-	if si.DaemonID != m.ctx.node.DaemonID {
+	if m.rs.StreamOutput && !m.rs.DryRun {
+		metrics.StreamedShardCnt++
+	} else if si.DaemonID != m.ctx.node.DaemonID {
 		metrics.MovedShardCnt++
 	}
 	if m.Metrics.extended {
@@ -726,6 +761,8 @@ func (m *Manager) distributeShardRecords(maxSize int64) error {
 		errCh = make(chan error, m.smap.CountTargets())
 	)
 
+	// Every target gets the metadata - also the ones which do not create any
+	// shards - since send order is required to adjust the references.
 	for _, d := range m.smap.Tmap {
 		shardsToTarget[d] = nil
 		sendOrder[d.DaemonID] = make(map[string]*extract.Shard, 100)
@@ -345,15 +345,14 @@ func (ds *dsorterGeneral) loadContent() extract.LoadContentFunc {
 				}
 				f.Close()
 			case extract.SGLStoreType:
-				v, ok := ds.m.recManager.RecordContents().Load(fullContentPath)
-				cmn.AssertMsg(ok, fullContentPath)
-				ds.m.recManager.RecordContents().Delete(fullContentPath)
-				sgl := v.(*memsys.SGL)
-				if n, err = io.CopyBuffer(w, sgl, buf); err != nil {
+				r, _ := ds.loadSGL(fullContentPath)
+				n, err = io.CopyBuffer(w, r, buf)
+				if sgl, ok := r.(*memsys.SGL); ok {
 					sgl.Free()
+				}
+				if err != nil {
 					return written, errors.WithMessage(err, "(sgl) copy local content failed")
 				}
-				sgl.Free()
 			case extract.DiskStoreType:
 				f, err := os.Open(fullContentPath)
 				if err != nil {
@@ -572,13 +571,12 @@ func (ds *dsorterGeneral) makeRecvRequestFunc() transport.Receive {
 				ds.m.abort(err)
 			}
 		case extract.SGLStoreType:
-			v, ok := ds.m.recManager.RecordContents().Load(fullContentPath)
-			cmn.AssertMsg(ok, fullContentPath)
-			ds.m.recManager.RecordContents().Delete(fullContentPath)
-			sgl := v.(*memsys.SGL)
-			respHdr.ObjAttrs.Size = sgl.Size()
-			if err := ds.streams.response.SendV(respHdr, sgl, ds.responseCallback, unsafe.Pointer(&beforeSend) /* cmpl ptr */, fromNode); err != nil {
-				sgl.Free()
+			r, size := ds.loadSGL(fullContentPath)
+			respHdr.ObjAttrs.Size = size
+			if err := ds.streams.response.SendV(respHdr, r, ds.responseCallback, unsafe.Pointer(&beforeSend) /* cmpl ptr */, fromNode); err != nil {
+				if sgl, ok := r.(*memsys.SGL); ok {
+					sgl.Free()
+				}
 				ds.m.abort(err)
 			}
 		case extract.DiskStoreType:
@@ -604,6 +602,22 @@ func (ds *dsorterGeneral) makeRecvRequestFunc() transport.Receive {
 	}
 }
 
+// loadSGL returns the content of the record object stored in memory. Usually
+// the content is loaded exactly once so the SGL is removed from the record
+// manager and must be freed by the caller. Records can be duplicated by the
+// stratified sampling though - then the content is shared by the duplicates
+// and only a reader is returned (the SGL is freed on cleanup).
+func (ds *dsorterGeneral) loadSGL(fullContentPath string) (cmn.ReadOpenCloser, int64) {
+	v, ok := ds.m.recManager.RecordContents().Load(fullContentPath)
+	cmn.AssertMsg(ok, fullContentPath)
+	sgl := v.(*memsys.SGL)
+	if ds.m.rs.Algorithm.Kind == SortKindStratified {
+		return memsys.NewReader(sgl), sgl.Size()
+	}
+	ds.m.recManager.RecordContents().Delete(fullContentPath)
+	return sgl, sgl.Size()
+}
+
 func (ds *dsorterGeneral) responseCallback(hdr transport.Header, rc io.ReadCloser, x unsafe.Pointer, err error) {
 	if ds.m.Metrics.extended {
 		dur := time.Since(*(*time.Time)(x))
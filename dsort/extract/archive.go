@@ -0,0 +1,127 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+var (
+	_ RecordExtractor = &archiveWalker{}
+)
+
+type (
+	// WalkArchiveFunc is called for each regular file (member) of the archive.
+	// The reader is valid only until the function returns.
+	WalkArchiveFunc func(name string, r cmn.ReadSizer) error
+
+	archiveWalker struct {
+		cb WalkArchiveFunc
+	}
+)
+
+func (aw *archiveWalker) ExtractRecordWithBuffer(args extractRecordArgs) (int64, error) {
+	if err := aw.cb(args.recordName, args.r); err != nil {
+		return 0, err
+	}
+	return args.r.Size(), nil
+}
+
+func isTarGz(archiveName string) bool {
+	return strings.HasSuffix(archiveName, ".tar.gz") || strings.HasSuffix(archiveName, ".tgz")
+}
+
+func archiveExtractCreator(archiveName string) ExtractCreator {
+	switch {
+	case isTarGz(archiveName):
+		return NewTargzExtractCreator()
+	case strings.HasSuffix(archiveName, ".tar"):
+		return NewTarExtractCreator()
+	case strings.HasSuffix(archiveName, ".zip"):
+		return NewZipExtractCreator()
+	default:
+		return nil
+	}
+}
+
+// SupportsArchive returns true if the object with given name is an archive
+// which members can be listed and extracted.
+func SupportsArchive(archiveName string) bool {
+	return archiveExtractCreator(archiveName) != nil
+}
+
+// CleanMemberName returns the normalized name of the archive member (leading
+// `./` and redundant separators removed). Empty name is returned for
+// directory entries which should be skipped. An error is returned if the
+// member would escape the destination (prefix) it is extracted to, ie. the
+// name is absolute or refers to the parent directory.
+func CleanMemberName(name string) (string, error) {
+	if name == "" || path.IsAbs(name) {
+		return "", fmt.Errorf("invalid archive member name %q", name)
+	}
+	if strings.HasSuffix(name, "/") {
+		return "", nil
+	}
+	cleaned := path.Clean(strings.TrimPrefix(name, "./"))
+	if cleaned == "." {
+		return "", nil
+	}
+	for _, elem := range strings.Split(cleaned, "/") {
+		if elem == ".." {
+			return "", fmt.Errorf("invalid archive member name %q: contains %q", name, elem)
+		}
+	}
+	return cleaned, nil
+}
+
+// WalkArchive calls the function for each member of the archive, in order in
+// which they are stored. The format of the archive is determined by its name
+// (fqn.ObjName). If the function does not read the member, it is skipped.
+func WalkArchive(fqn fs.ParsedFQN, r *io.SectionReader, cb WalkArchiveFunc) error {
+	ec := archiveExtractCreator(fqn.ObjName)
+	if ec == nil {
+		return fmt.Errorf("%q is not supported archive, expected one of: .tar, .tgz, .tar.gz, .zip", fqn.ObjName)
+	}
+	// Extracting compressed tarball creates the decompressed copy of it on
+	// the disk which is unnecessary here - members can be read directly
+	// from the decompressed stream.
+	if isTarGz(fqn.ObjName) {
+		return walkTarGz(r, cb)
+	}
+	_, _, err := ec.ExtractShard(fqn, r, &archiveWalker{cb: cb}, false /*toDisk*/)
+	return err
+}
+
+func walkTarGz(r io.Reader, cb WalkArchiveFunc) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := cb(header.Name, cmn.NewSizedReader(tr, header.Size)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WalkArchive", func() {
+	files := map[string][]byte{
+		"a.cls":     []byte("1"),
+		"a.jpg":     bytes.Repeat([]byte("a"), 1000),
+		"dir/b.txt": bytes.Repeat([]byte("b"), 513),
+	}
+
+	createTar := func(w io.Writer) {
+		tw := tar.NewWriter(w)
+		Expect(tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755})).NotTo(HaveOccurred())
+		for name, content := range files {
+			Expect(tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Typeflag: tar.TypeReg, Mode: 0644})).NotTo(HaveOccurred())
+			_, err := tw.Write(content)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).NotTo(HaveOccurred())
+	}
+
+	walk := func(name string, b []byte) map[string][]byte {
+		walked := make(map[string][]byte)
+		r := io.NewSectionReader(bytes.NewReader(b), 0, int64(len(b)))
+		err := WalkArchive(fs.ParsedFQN{ObjName: name}, r, func(name string, r cmn.ReadSizer) error {
+			content, err := ioutil.ReadAll(r)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(HaveLen(int(r.Size())))
+			walked[name] = content
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return walked
+	}
+
+	It("should walk tar archive", func() {
+		buf := &bytes.Buffer{}
+		createTar(buf)
+		Expect(walk("archive.tar", buf.Bytes())).To(Equal(files))
+	})
+
+	It("should walk compressed tar archive", func() {
+		buf := &bytes.Buffer{}
+		gzw := gzip.NewWriter(buf)
+		createTar(gzw)
+		Expect(gzw.Close()).NotTo(HaveOccurred())
+		Expect(walk("archive.tgz", buf.Bytes())).To(Equal(files))
+		Expect(walk("archive.tar.gz", buf.Bytes())).To(Equal(files))
+	})
+
+	It("should walk zip archive", func() {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		for name, content := range files {
+			w, err := zw.Create(name)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write(content)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(zw.Close()).NotTo(HaveOccurred())
+		Expect(walk("archive.zip", buf.Bytes())).To(Equal(files))
+	})
+
+	It("should skip unread members and stop on error", func() {
+		buf := &bytes.Buffer{}
+		createTar(buf)
+		r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 0, int64(buf.Len()))
+
+		cnt := 0
+		err := WalkArchive(fs.ParsedFQN{ObjName: "archive.tar"}, r, func(string, cmn.ReadSizer) error {
+			cnt++
+			if cnt == 2 {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		})
+		Expect(err).To(Equal(io.ErrUnexpectedEOF))
+		Expect(cnt).To(Equal(2))
+	})
+
+	It("should not walk unsupported archive", func() {
+		Expect(SupportsArchive("archive.rar")).To(BeFalse())
+		err := WalkArchive(fs.ParsedFQN{ObjName: "archive.rar"}, io.NewSectionReader(bytes.NewReader(nil), 0, 0), nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should clean member names", func() {
+		for name, cleaned := range map[string]string{
+			"a.jpg":         "a.jpg",
+			"dir/b.txt":     "dir/b.txt",
+			"dir/..b/c":     "dir/..b/c",
+			"./a.jpg":       "a.jpg",
+			"dir//b.txt":    "dir/b.txt",
+			"dir/./b.txt":   "dir/b.txt",
+			"dir/x/../b.tx": "dir/b.tx",
+			"./":            "",
+			"dir/":          "",
+		} {
+			got, err := CleanMemberName(name)
+			Expect(err).NotTo(HaveOccurred(), name)
+			Expect(got).To(Equal(cleaned), name)
+		}
+	})
+
+	It("should reject unsafe member names", func() {
+		for _, name := range []string{"", "/etc/passwd", "../a.jpg", "./../a.jpg", "dir/../../a.jpg", "dir/../.."} {
+			_, err := CleanMemberName(name)
+			Expect(err).To(HaveOccurred(), name)
+		}
+	})
+
+	It("should walk malicious archives with unsafe member names", func() {
+		var (
+			malicious = []string{"../../etc/passwd", "/abs/path", "dir/../../escape"}
+			tarBuf    = &bytes.Buffer{}
+			zipBuf    = &bytes.Buffer{}
+			tw        = tar.NewWriter(tarBuf)
+			zw        = zip.NewWriter(zipBuf)
+		)
+		for _, name := range malicious {
+			Expect(tw.WriteHeader(&tar.Header{Name: name, Size: 1, Typeflag: tar.TypeReg, Mode: 0644})).NotTo(HaveOccurred())
+			_, err := tw.Write([]byte("x"))
+			Expect(err).NotTo(HaveOccurred())
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: name})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte("x"))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).NotTo(HaveOccurred())
+		Expect(zw.Close()).NotTo(HaveOccurred())
+
+		for name, b := range map[string][]byte{"archive.tar": tarBuf.Bytes(), "archive.zip": zipBuf.Bytes()} {
+			walked := walk(name, b)
+			Expect(walked).To(HaveLen(len(malicious)))
+			for member := range walked {
+				_, err := CleanMemberName(member)
+				Expect(err).To(HaveOccurred(), member)
+			}
+		}
+	})
+})
@@ -0,0 +1,138 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/filetype"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ RecordExtractor = &indexBuilder{}
+)
+
+type (
+	// ShardIndex maps the names of the records (files in the archive) to
+	// their location in the shard so that a single record can be read with
+	// a range request. It is stored as a sidecar next to the shard.
+	ShardIndex struct {
+		// Size and modification time of the shard from which the index
+		// was created - used to detect if the index is stale.
+		Size    int64                        `json:"size,string"`
+		ModTime int64                        `json:"mtime,string"`
+		Records map[string]*ShardIndexRecord `json:"records"`
+	}
+
+	ShardIndexRecord struct {
+		Offset int64 `json:"offset,string"`
+		Size   int64 `json:"size,string"`
+	}
+
+	indexBuilder struct {
+		index *ShardIndex
+	}
+)
+
+// ExtractRecordWithBuffer only registers the location of the record. Records
+// which cannot be read directly from the shard (eg. compressed zip entries)
+// have no offset and are skipped.
+func (ib *indexBuilder) ExtractRecordWithBuffer(args extractRecordArgs) (int64, error) {
+	if args.offset > 0 {
+		ib.index.Records[args.recordName] = &ShardIndexRecord{Offset: args.offset, Size: args.r.Size()}
+	}
+	return args.r.Size(), nil
+}
+
+// SupportsShardIndex returns true if the shard with given name can be indexed.
+// Only the archives which are not compressed as a whole can be indexed.
+func SupportsShardIndex(shardName string) bool {
+	return indexExtractCreator(shardName) != nil
+}
+
+func indexExtractCreator(shardName string) ExtractCreator {
+	switch filepath.Ext(shardName) {
+	case ".tar":
+		return NewTarExtractCreator()
+	case ".zip":
+		return NewZipExtractCreator()
+	default:
+		return nil
+	}
+}
+
+func shardIndexFQN(lom *cluster.LOM) string {
+	return fs.CSM.GenContentParsedFQN(lom.ParsedFQN, filetype.ShardIndexType, "")
+}
+
+func buildShardIndex(fqn fs.ParsedFQN, r *io.SectionReader) (*ShardIndex, error) {
+	ec := indexExtractCreator(fqn.ObjName)
+	if ec == nil {
+		return nil, fmt.Errorf("shard index is supported only for .tar and .zip archives, got %q", fqn.ObjName)
+	}
+	ib := &indexBuilder{index: &ShardIndex{
+		Size:    r.Size(),
+		Records: make(map[string]*ShardIndexRecord),
+	}}
+	if _, _, err := ec.ExtractShard(fqn, r, ib, true); err != nil {
+		return nil, err
+	}
+	return ib.index, nil
+}
+
+// CreateShardIndex builds the index of the shard and stores it as a sidecar.
+// The shard must be loaded and locked by the caller.
+func CreateShardIndex(lom *cluster.LOM) (*ShardIndex, error) {
+	f, err := os.Open(lom.FQN)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	finfo, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := buildShardIndex(lom.ParsedFQN, io.NewSectionReader(f, 0, finfo.Size()))
+	if err != nil {
+		return nil, errors.Errorf("failed to index %s, err: %v", lom, err)
+	}
+	index.ModTime = finfo.ModTime().UnixNano()
+	if err := cmn.LocalSave(shardIndexFQN(lom), index, true /*compress*/); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// LoadShardIndex returns the index of the shard. If the sidecar does not
+// exist or is stale (the shard has been overwritten in the meantime) the index
+// is created anew. The shard must be loaded and locked by the caller.
+func LoadShardIndex(lom *cluster.LOM) (*ShardIndex, error) {
+	finfo, err := os.Stat(lom.FQN)
+	if err != nil {
+		return nil, err
+	}
+	index := &ShardIndex{}
+	err = cmn.LocalLoad(shardIndexFQN(lom), index, true /*decompress*/)
+	if err == nil && index.Size == finfo.Size() && index.ModTime == finfo.ModTime().UnixNano() {
+		return index, nil
+	}
+	return CreateShardIndex(lom)
+}
+
+// RemoveShardIndex removes the sidecar of the shard, if exists.
+func RemoveShardIndex(lom *cluster.LOM) error {
+	if err := os.Remove(shardIndexFQN(lom)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
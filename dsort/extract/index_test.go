@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ShardIndex", func() {
+	files := map[string][]byte{
+		"a.cls":     []byte("1"),
+		"a.jpg":     bytes.Repeat([]byte("a"), 1000),
+		"dir/b.txt": bytes.Repeat([]byte("b"), 513),
+	}
+
+	readRecord := func(r *io.SectionReader, record *ShardIndexRecord) []byte {
+		b := make([]byte, record.Size)
+		_, err := r.ReadAt(b, record.Offset)
+		Expect(err).NotTo(HaveOccurred())
+		return b
+	}
+
+	It("should index tar shard", func() {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		for name, content := range files {
+			Expect(tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Typeflag: tar.TypeReg, Mode: 0644})).NotTo(HaveOccurred())
+			_, err := tw.Write(content)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).NotTo(HaveOccurred())
+
+		r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 0, int64(buf.Len()))
+		index, err := buildShardIndex(fs.ParsedFQN{ObjName: "shard.tar"}, r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.Size).To(Equal(int64(buf.Len())))
+		Expect(index.Records).To(HaveLen(len(files)))
+		for name, content := range files {
+			Expect(index.Records).To(HaveKey(name))
+			Expect(readRecord(r, index.Records[name])).To(Equal(content))
+		}
+	})
+
+	It("should index tar shard with extended headers", func() {
+		var (
+			paxName = strings.Repeat("d", 130)
+			gnuName = strings.Repeat("g", 130)
+			buf     = &bytes.Buffer{}
+			tw      = tar.NewWriter(buf)
+			content = map[string][]byte{
+				paxName: []byte("pax"),
+				"short": []byte("short"),
+				gnuName: bytes.Repeat([]byte("x"), 600),
+			}
+		)
+		for _, hdr := range []*tar.Header{
+			{Name: paxName, Format: tar.FormatPAX},
+			{Name: "short"},
+			{Name: gnuName, Format: tar.FormatGNU},
+		} {
+			hdr.Typeflag, hdr.Mode, hdr.Size = tar.TypeReg, 0644, int64(len(content[hdr.Name]))
+			Expect(tw.WriteHeader(hdr)).NotTo(HaveOccurred())
+			_, err := tw.Write(content[hdr.Name])
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).NotTo(HaveOccurred())
+
+		r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 0, int64(buf.Len()))
+		index, err := buildShardIndex(fs.ParsedFQN{ObjName: "shard.tar"}, r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.Records).To(HaveLen(len(content)))
+		for name, b := range content {
+			Expect(index.Records).To(HaveKey(name))
+			Expect(readRecord(r, index.Records[name])).To(Equal(b))
+		}
+	})
+
+	It("should index only stored entries of zip shard", func() {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		for name, content := range files {
+			method := zip.Store
+			if name == "a.jpg" {
+				method = zip.Deflate
+			}
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write(content)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(zw.Close()).NotTo(HaveOccurred())
+
+		r := io.NewSectionReader(bytes.NewReader(buf.Bytes()), 0, int64(buf.Len()))
+		index, err := buildShardIndex(fs.ParsedFQN{ObjName: "shard.zip"}, r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.Records).To(HaveLen(len(files) - 1))
+		Expect(index.Records).NotTo(HaveKey("a.jpg"))
+		Expect(readRecord(r, index.Records["a.cls"])).To(Equal(files["a.cls"]))
+		Expect(readRecord(r, index.Records["dir/b.txt"])).To(Equal(files["dir/b.txt"]))
+	})
+
+	It("should not index compressed tar shard", func() {
+		Expect(SupportsShardIndex("shard.tgz")).To(BeFalse())
+		Expect(SupportsShardIndex("shard.tar.gz")).To(BeFalse())
+		Expect(SupportsShardIndex("shard.tar")).To(BeTrue())
+		Expect(SupportsShardIndex("shard.zip")).To(BeTrue())
+
+		_, err := buildShardIndex(fs.ParsedFQN{ObjName: "shard.tgz"}, io.NewSectionReader(bytes.NewReader(nil), 0, 0))
+		Expect(err).To(HaveOccurred())
+	})
+})
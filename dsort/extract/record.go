@@ -6,6 +6,9 @@ package extract
 
 import (
 	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"unsafe"
 
@@ -58,6 +61,9 @@ type (
 		Key      interface{} `json:"k"` // Used to determine the sorting order.
 		Name     string      `json:"n"` // Name which uniquely identifies record across all shards.
 		DaemonID string      `json:"d"` // ID of the target which maintains the contents for this record.
+		// Number of the duplicate if the record has been duplicated by
+		// oversampling, zero for the original record (see: DupName).
+		Dup int `json:"u,omitempty"`
 		// All objects associated with given record. Record can be composed of
 		// multiple objects which have the same name but different extension.
 		Objects []*RecordObj `json:"o"`
@@ -73,6 +79,14 @@ type (
 	}
 )
 
+// DupName returns the name of the object (file in the archive) of the n-th
+// duplicate of the record: `~n` suffix is inserted before the extension so
+// the duplicates are distinct members of the output shard.
+func DupName(name string, n int) string {
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "~" + strconv.Itoa(n) + ext
+}
+
 // Merges two records into single one. It is required for records to have the
 // same Name. Since records should only differ on objects this is the thing that
 // is actually merged.
@@ -153,6 +167,21 @@ func (r *Records) Insert(records ...*Record) {
 	r.Unlock()
 }
 
+// Replace replaces all the records with the given ones. It is used by the
+// algorithms which not only reorder the records but can also drop or duplicate
+// some of them.
+func (r *Records) Replace(records []*Record) {
+	r.Lock()
+	r.arr = records
+	r.m = make(map[string]*Record, len(records))
+	r.totalObjectCount = 0
+	for _, record := range records {
+		r.m[record.Name] = record
+		r.totalObjectCount += len(record.Objects)
+	}
+	r.Unlock()
+}
+
 func (r *Records) DeleteDup(name, ext string) {
 	cmn.Assert(r.Exists(name, ext))
 	r.Lock()
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateShard", func() {
+	const (
+		memberName = "dir/a.jpg"
+		recordName = "shard|dir/a"
+	)
+	content := []byte("content of the record")
+
+	// duplicate creates the record and its two duplicates as produced by oversampling
+	duplicate := func(obj *RecordObj) *Shard {
+		records := NewRecords(3)
+		for dup := 0; dup < 3; dup++ {
+			rec := &Record{Key: "a", Name: recordName, Objects: []*RecordObj{obj}, Dup: dup}
+			if dup > 0 {
+				rec.Name = DupName(recordName, dup)
+			}
+			records.Insert(rec)
+		}
+		return &Shard{Name: "out.tar", Records: records}
+	}
+
+	loadFrom := func(b []byte) LoadContentFunc {
+		return func(w io.Writer, _ *Record, obj *RecordObj) (int64, error) {
+			n, err := w.Write(b[obj.Offset-obj.MetadataSize : obj.Offset+obj.Size])
+			return int64(n), err
+		}
+	}
+
+	tarMembers := func(r io.Reader) (names []string) {
+		tr := tar.NewReader(r)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			b := make([]byte, header.Size)
+			_, err = io.ReadFull(tr, b)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b).To(Equal(content))
+			names = append(names, header.Name)
+		}
+	}
+
+	expectedNames := []string{memberName, "dir/a~1.jpg", "dir/a~2.jpg"}
+
+	It("should rename duplicated records stored in memory or on disk", func() {
+		metadata := cmn.MustMarshal(tarFileHeader{Name: memberName, Typeflag: tar.TypeReg, Mode: 0644})
+		b := append(metadata, content...)
+		obj := &RecordObj{
+			StoreType:    SGLStoreType,
+			Offset:       int64(len(metadata)),
+			MetadataSize: int64(len(metadata)),
+			Size:         int64(len(content)),
+			Extension:    ".jpg",
+		}
+		for _, ec := range []ExtractCreator{NewTarExtractCreator(), NewTargzExtractCreator()} {
+			buf := &bytes.Buffer{}
+			_, err := ec.CreateShard(duplicate(obj), buf, loadFrom(b))
+			Expect(err).NotTo(HaveOccurred())
+
+			var r io.Reader = buf
+			if ec.UsingCompression() {
+				r, err = gzip.NewReader(buf)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(tarMembers(r)).To(Equal(expectedNames))
+		}
+	})
+
+	It("should rename duplicated records stored at offset", func() {
+		src := &bytes.Buffer{}
+		tw := tar.NewWriter(src)
+		Expect(tw.WriteHeader(&tar.Header{Name: memberName, Size: int64(len(content)), Typeflag: tar.TypeReg, Mode: 0644})).NotTo(HaveOccurred())
+		_, err := tw.Write(content)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tw.Close()).NotTo(HaveOccurred())
+
+		r := io.NewSectionReader(bytes.NewReader(src.Bytes()), 0, int64(src.Len()))
+		index, err := buildShardIndex(fs.ParsedFQN{ObjName: "shard.tar"}, r)
+		Expect(err).NotTo(HaveOccurred())
+		obj := &RecordObj{
+			StoreType:    OffsetStoreType,
+			Offset:       index.Records[memberName].Offset,
+			MetadataSize: tarBlockSize,
+			Size:         int64(len(content)),
+			Extension:    ".jpg",
+		}
+
+		buf := &bytes.Buffer{}
+		_, err = NewTarExtractCreator().CreateShard(duplicate(obj), buf, loadFrom(src.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tarMembers(buf)).To(Equal(expectedNames))
+	})
+
+	It("should rename duplicated records in zip shard", func() {
+		metadata := cmn.MustMarshal(zipFileHeader{Name: memberName})
+		b := append(metadata, content...)
+		obj := &RecordObj{
+			StoreType:    SGLStoreType,
+			Offset:       int64(len(metadata)),
+			MetadataSize: int64(len(metadata)),
+			Size:         int64(len(content)),
+			Extension:    ".jpg",
+		}
+		buf := &bytes.Buffer{}
+		_, err := NewZipExtractCreator().CreateShard(duplicate(obj), buf, loadFrom(b))
+		Expect(err).NotTo(HaveOccurred())
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		Expect(err).NotTo(HaveOccurred())
+		names := make([]string, 0, len(zr.File))
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		Expect(names).To(Equal(expectedNames))
+	})
+})
@@ -6,6 +6,7 @@ package extract
 
 import (
 	"archive/tar"
+	"bytes"
 	"io"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
@@ -53,6 +54,10 @@ type tarRecordDataReader struct {
 	written      int64
 	metadataBuf  []byte
 	tarWriter    *tar.Writer
+	// raw is set when the metadata is the tar header as stored in the shard
+	// rather than marshaled tarFileHeader (see: OffsetStoreType).
+	raw bool
+	dup int // non-zero: the header gets renamed (see: DupName)
 }
 
 func newTarFileHeader(header *tar.Header) tarFileHeader {
@@ -92,11 +97,34 @@ func newTarRecordDataReader() *tarRecordDataReader {
 	return rd
 }
 
-func (rd *tarRecordDataReader) reinit(tw *tar.Writer, size int64, metadataSize int64) {
+func (rd *tarRecordDataReader) reinit(tw *tar.Writer, size, metadataSize int64, raw bool, dup int) {
 	rd.tarWriter = tw
 	rd.written = 0
 	rd.size = size
 	rd.metadataSize = metadataSize
+	rd.raw = raw
+	rd.dup = dup
+}
+
+func (rd *tarRecordDataReader) header() (*tar.Header, error) {
+	var header *tar.Header
+	if rd.raw {
+		var err error
+		if header, err = tar.NewReader(bytes.NewReader(rd.metadataBuf[:rd.metadataSize])).Next(); err != nil {
+			return nil, err
+		}
+		header.Format = tar.FormatUnknown // the new name may not fit USTAR
+	} else {
+		var metadata tarFileHeader
+		if err := jsoniter.Unmarshal(rd.metadataBuf[:rd.metadataSize], &metadata); err != nil {
+			return nil, err
+		}
+		header = metadata.toTarHeader(rd.size)
+	}
+	if rd.dup > 0 {
+		header.Name = DupName(header.Name, rd.dup)
+	}
+	return header, nil
 }
 
 func (rd *tarRecordDataReader) free() {
@@ -119,12 +147,10 @@ func (rd *tarRecordDataReader) Write(p []byte) (int, error) {
 		copy(rd.metadataBuf[rd.written:], p[:remainingMetadataSize])
 		rd.written += remainingMetadataSize
 		p = p[remainingMetadataSize:]
-		var metadata tarFileHeader
-		if err := jsoniter.Unmarshal(rd.metadataBuf[:rd.metadataSize], &metadata); err != nil {
+		header, err := rd.header()
+		if err != nil {
 			return int(remainingMetadataSize), err
 		}
-
-		header := metadata.toTarHeader(rd.size)
 		if err := rd.tarWriter.WriteHeader(header); err != nil {
 			return int(remainingMetadataSize), err
 		}
@@ -155,7 +181,7 @@ func (t *tarExtractCreator) ExtractShard(fqn fs.ParsedFQN, r *io.SectionReader,
 	buf := slab.Alloc()
 	defer slab.Free(buf)
 
-	offset := int64(0)
+	var offset int64
 	for {
 		header, err = tr.Next()
 		if err == io.EOF {
@@ -167,7 +193,11 @@ func (t *tarExtractCreator) ExtractShard(fqn fs.ParsedFQN, r *io.SectionReader,
 		metadata := newTarFileHeader(header)
 		bmeta := cmn.MustMarshal(metadata)
 
-		offset += t.MetadataSize()
+		// the reader is positioned at the body - unlike counting 512-byte
+		// headers, this accounts for the extended (PAX, GNU long name) ones
+		if offset, err = r.Seek(0, io.SeekCurrent); err != nil {
+			return extractedSize, extractedCount, err
+		}
 
 		if header.Typeflag == tar.TypeDir {
 			// We can safely ignore this case because we do `MkdirAll` anyway
@@ -202,9 +232,6 @@ func (t *tarExtractCreator) ExtractShard(fqn fs.ParsedFQN, r *io.SectionReader,
 
 		extractedSize += size
 		extractedCount++
-
-		// .tar format pads all block to 512 bytes
-		offset += paddedSize(header.Size)
 	}
 }
 
@@ -230,8 +257,8 @@ func (t *tarExtractCreator) CreateShard(s *Shard, tarball io.Writer, loadContent
 
 	for _, rec := range s.Records.All() {
 		for _, obj := range rec.Objects {
-			switch obj.StoreType {
-			case OffsetStoreType:
+			switch {
+			case obj.StoreType == OffsetStoreType && rec.Dup == 0:
 				if needFlush {
 					// We now will write directly to the tarball file so we need
 					// to flush everything what we have written so far.
@@ -254,8 +281,10 @@ func (t *tarExtractCreator) CreateShard(s *Shard, tarball io.Writer, loadContent
 					n += diff
 				}
 				cmn.Dassert(diff >= 0 && diff < 512, pkgName)
-			case SGLStoreType, DiskStoreType:
-				rdReader.reinit(tw, obj.Size, obj.MetadataSize)
+			case obj.StoreType == OffsetStoreType, obj.StoreType == SGLStoreType, obj.StoreType == DiskStoreType:
+				// NOTE: the header of the duplicated record must be renamed so
+				// it cannot be copied from the original shard as is
+				rdReader.reinit(tw, obj.Size, obj.MetadataSize, obj.StoreType == OffsetStoreType, rec.Dup)
 				if n, err = loadContent(rdReader, rec, obj); err != nil {
 					return written + n, err
 				}
@@ -143,8 +143,8 @@ func (t *targzExtractCreator) CreateShard(s *Shard, tarball io.Writer, loadConte
 
 	for _, rec := range s.Records.All() {
 		for _, obj := range rec.Objects {
-			switch obj.StoreType {
-			case OffsetStoreType:
+			switch {
+			case obj.StoreType == OffsetStoreType && rec.Dup == 0:
 				if needFlush {
 					// We now will write directly to the tarball file so we need
 					// to flush everything what we have written so far.
@@ -167,8 +167,10 @@ func (t *targzExtractCreator) CreateShard(s *Shard, tarball io.Writer, loadConte
 					n += diff
 				}
 				cmn.Dassert(diff >= 0 && diff < 512, pkgName)
-			case SGLStoreType, DiskStoreType:
-				rdReader.reinit(tw, obj.Size, obj.MetadataSize)
+			case obj.StoreType == OffsetStoreType, obj.StoreType == SGLStoreType, obj.StoreType == DiskStoreType:
+				// NOTE: the header of the duplicated record must be renamed so
+				// it cannot be copied from the original shard as is
+				rdReader.reinit(tw, obj.Size, obj.MetadataSize, obj.StoreType == OffsetStoreType, rec.Dup)
 				if n, err = loadContent(rdReader, rec, obj); err != nil {
 					return written + n, err
 				}
@@ -36,6 +36,7 @@ type (
 		metadataBuf  []byte
 		header       zipFileHeader
 		zipWriter    *zip.Writer
+		dup          int // non-zero: the file gets renamed (see: DupName)
 
 		writer io.Writer
 	}
@@ -47,11 +48,12 @@ func newZipRecordDataReader() *zipRecordDataReader {
 	return rd
 }
 
-func (rd *zipRecordDataReader) reinit(zw *zip.Writer, size int64, metadataSize int64) {
+func (rd *zipRecordDataReader) reinit(zw *zip.Writer, size, metadataSize int64, dup int) {
 	rd.zipWriter = zw
 	rd.written = 0
 	rd.size = size
 	rd.metadataSize = metadataSize
+	rd.dup = dup
 }
 
 func (rd *zipRecordDataReader) free() {
@@ -79,6 +81,9 @@ func (rd *zipRecordDataReader) Write(p []byte) (int, error) {
 			return int(remainingMetadataSize), err
 		}
 
+		if rd.dup > 0 {
+			metadata.Name = DupName(metadata.Name, rd.dup)
+		}
 		rd.header = metadata
 		writer, err := rd.zipWriter.Create(rd.header.Name)
 		if err != nil {
@@ -143,6 +148,16 @@ func (z *zipExtractCreator) ExtractShard(fqn fs.ParsedFQN, r *io.SectionReader,
 				extractMethod = ExtractToDisk
 			}
 
+			// Only the body of the file which is not compressed can be
+			// accessed directly in the shard.
+			var offset int64
+			if header.Method == zip.Store {
+				if offset, err = f.DataOffset(); err != nil {
+					file.Close()
+					return extractedSize, extractedCount, err
+				}
+			}
+
 			args := extractRecordArgs{
 				shardName:     fqn.ObjName,
 				fileType:      fqn.ContentType,
@@ -150,6 +165,7 @@ func (z *zipExtractCreator) ExtractShard(fqn fs.ParsedFQN, r *io.SectionReader,
 				r:             cmn.NewSizedReader(file, int64(header.UncompressedSize64)),
 				metadata:      bmeta,
 				extractMethod: extractMethod,
+				offset:        offset,
 				buf:           buf,
 			}
 			if size, err = extractor.ExtractRecordWithBuffer(args); err != nil {
@@ -180,7 +196,7 @@ func (z *zipExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadC
 	rdReader := newZipRecordDataReader()
 	for _, rec := range s.Records.All() {
 		for _, obj := range rec.Objects {
-			rdReader.reinit(zw, obj.Size, obj.MetadataSize)
+			rdReader.reinit(zw, obj.Size, obj.MetadataSize, rec.Dup)
 			if n, err = loadContent(rdReader, rec, obj); err != nil {
 				return written + n, err
 			}
@@ -14,13 +14,19 @@ import (
 const (
 	DSortFileType     = cmn.DSortNameLowercase
 	DSortWorkfileType = cmn.DSortNameLowercase + "w"
+	// ShardIndexType is the content type of shard index sidecars. Index is
+	// stored next to the shard and can be always rebuilt from it.
+	ShardIndexType = "shidx"
 
 	WorkfileRecvShard   = "recv-shard"
 	WorkfileCreateShard = "create-shard"
+	WorkfileManifest    = "manifest"
+	WorkfileStreamShard = "stream-shard"
 )
 
 var (
 	_ fs.ContentResolver = &DSortFile{}
+	_ fs.ContentResolver = &ShardIndexFile{}
 )
 
 type DSortFile struct{}
@@ -32,3 +38,13 @@ func (df *DSortFile) GenUniqueFQN(base, _ string) string { return base }
 func (df *DSortFile) ParseUniqueFQN(base string) (orig string, old bool, ok bool) {
 	return base, false, true
 }
+
+type ShardIndexFile struct{}
+
+func (sf *ShardIndexFile) PermToEvict() bool                  { return true }
+func (sf *ShardIndexFile) PermToMove() bool                   { return false }
+func (sf *ShardIndexFile) PermToProcess() bool                { return false }
+func (sf *ShardIndexFile) GenUniqueFQN(base, _ string) string { return base }
+func (sf *ShardIndexFile) ParseUniqueFQN(base string) (orig string, old bool, ok bool) {
+	return base, false, true
+}
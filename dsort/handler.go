@@ -488,7 +488,7 @@ func (m *Manager) startDSort() {
 
 	glog.Info("broadcasting finished ack to other targets")
 	path := cmn.URLPath(cmn.Version, cmn.Sort, cmn.FinishedAck, m.ManagerUUID, m.ctx.node.DaemonID)
-	broadcast(http.MethodPut, path, nil, nil, ctx.smap.Get().Tmap, ctx.node)
+	broadcast(http.MethodPut, path, nil, m.createdShardsBody(), ctx.smap.Get().Tmap, ctx.node)
 }
 
 // shardsHandler is the handler for the HTTP endpoint /v1/sort/shards.
@@ -524,6 +524,7 @@ func shardsHandler(managers *ManagerGroup) http.HandlerFunc {
 			cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("could not unmarshal request body, err: %v", err), http.StatusInternalServerError)
 			return
 		}
+		dsortManager.updateRefsAfterSampling()
 		dsortManager.startShardCreation <- struct{}{}
 	}
 }
@@ -708,6 +709,21 @@ func finishedAckHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Body contains the shards created by the target - used for the manifest.
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("could not read request body, err: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if len(body) > 0 {
+		var shards []*cmn.ManifestShard
+		if err := js.Unmarshal(body, &shards); err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("could not unmarshal request body, err: %v", err), http.StatusInternalServerError)
+			return
+		}
+		dsortManager.addReceivedShards(shards)
+	}
+
 	dsortManager.updateFinishedAck(daemonID)
 }
 
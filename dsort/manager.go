@@ -138,6 +138,11 @@ type (
 		}
 		creationPhase struct {
 			metadata creationPhaseMetadata
+			manifest struct {
+				sync.Mutex
+				created  []*cmn.ManifestShard // shards created by this target
+				received []*cmn.ManifestShard // shards created by other targets
+			}
 		}
 		finishedAck struct {
 			mu sync.Mutex
@@ -354,6 +359,12 @@ func (m *Manager) finalCleanup() {
 	glog.Infof("%s %s has started a final cleanup", cmn.DSortName, m.ManagerUUID)
 	now := time.Now()
 
+	if !m.aborted() {
+		if err := m.writeManifest(); err != nil {
+			glog.Error(err)
+		}
+	}
+
 	if err := m.cleanupStreams(); err != nil {
 		glog.Error(err)
 	}
@@ -370,6 +381,8 @@ func (m *Manager) finalCleanup() {
 
 	m.creationPhase.metadata.SendOrder = nil
 	m.creationPhase.metadata.Shards = nil
+	m.creationPhase.manifest.created = nil
+	m.creationPhase.manifest.received = nil
 
 	m.finishedAck.m = nil
 
@@ -436,7 +449,7 @@ func (m *Manager) setExtractCreator() (err error) {
 	)
 
 	switch m.rs.Algorithm.Kind {
-	case SortKindContent:
+	case SortKindContent, SortKindStratified:
 		keyExtractor, err = extract.NewContentKeyExtractor(m.rs.Algorithm.FormatType, m.rs.Algorithm.Extension)
 	case SortKindMD5:
 		keyExtractor, err = extract.NewMD5KeyExtractor()
@@ -525,6 +538,30 @@ func (m *Manager) incrementRef(by int64) {
 	m.refCount.Add(by)
 }
 
+// updateRefsAfterSampling adjusts the reference counter when the algorithm
+// drops or duplicates records. The counter was incremented by the number of
+// extracted record objects but now it must reflect the number of record objects
+// which will be actually loaded from this target (recorded in send order).
+// It must be called on every target holding the records, not only the ones
+// which create the shards (see: distributeShardRecords).
+func (m *Manager) updateRefsAfterSampling() {
+	if m.rs.Algorithm.Kind != SortKindStratified {
+		return
+	}
+
+	var toLoad int64
+	for _, shard := range m.creationPhase.metadata.SendOrder {
+		for _, record := range shard.Records.All() {
+			toLoad += int64(len(record.Objects))
+		}
+	}
+
+	m.Metrics.Extraction.Lock()
+	extracted := m.Metrics.Extraction.ExtractedRecordCnt
+	m.Metrics.Extraction.Unlock()
+	m.incrementRef(toLoad - extracted)
+}
+
 // decrementRef decrements reference counter. If it is 0 or below and dsort has
 // already finished returns true. Otherwise, false is returned.
 func (m *Manager) decrementRef(by int64) {
@@ -658,6 +695,10 @@ func (m *Manager) makeRecvShardFunc() transport.Receive {
 			m.abort(err)
 			return
 		}
+		if err := m.createShardIndex(lom); err != nil {
+			m.abort(err)
+			return
+		}
 	}
 }
 
@@ -7,6 +7,7 @@ package dsort
 
 import (
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/fs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -47,3 +48,43 @@ var _ = Describe("Init", func() {
 		Expect(m.extractCreator.UsingCompression()).To(BeTrue())
 	})
 })
+
+var _ = Describe("updateRefsAfterSampling", func() {
+	newRecords := func(names ...string) *extract.Records {
+		records := extract.NewRecords(len(names))
+		for _, name := range names {
+			records.Insert(&extract.Record{Name: name, Objects: []*extract.RecordObj{{Extension: ".jpg"}}})
+		}
+		return records
+	}
+
+	It("should adjust references to the records loaded from the target", func() {
+		m := &Manager{
+			rs:      &ParsedRequestSpec{Algorithm: &SortAlgorithm{Kind: SortKindStratified}},
+			Metrics: newMetrics("", false),
+		}
+		m.Metrics.Extraction.ExtractedRecordCnt = 3
+		m.incrementRef(3)
+
+		// `c` has been dropped and `a` duplicated twice
+		m.creationPhase.metadata.SendOrder = map[string]*extract.Shard{
+			"out-0.tar": {Name: "out-0.tar", Records: newRecords("a", "b", extract.DupName("a", 1))},
+			"out-1.tar": {Name: "out-1.tar", Records: newRecords(extract.DupName("a", 2))},
+		}
+		m.updateRefsAfterSampling()
+		Expect(m.refCount.Load()).To(Equal(int64(4)))
+	})
+
+	It("should release all references when target holds no records to load", func() {
+		m := &Manager{
+			rs:      &ParsedRequestSpec{Algorithm: &SortAlgorithm{Kind: SortKindStratified}},
+			Metrics: newMetrics("", false),
+		}
+		m.Metrics.Extraction.ExtractedRecordCnt = 2
+		m.incrementRef(2)
+
+		m.creationPhase.metadata.SendOrder = map[string]*extract.Shard{}
+		m.updateRefsAfterSampling()
+		Expect(m.refCount.Load()).To(Equal(int64(0)))
+	})
+})
@@ -0,0 +1,230 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ *
+ */
+package dsort
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/dsort/filetype"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/OneOfOne/xxhash"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Number of times the upload of the streamed output object is retried
+	// from the work file (see: streamToCloud).
+	streamOutputRetries = 3
+)
+
+type (
+	// countingReader counts the number of bytes consumed from the reader.
+	countingReader struct {
+		r io.Reader
+		n int64
+	}
+)
+
+func (cr *countingReader) Read(b []byte) (n int, err error) {
+	n, err = cr.r.Read(b)
+	cr.n += int64(n)
+	return
+}
+
+// addCreatedShard records the shard created by this target so it can be later
+// included in the manifest.
+func (m *Manager) addCreatedShard(s *extract.Shard, lom *cluster.LOM, size int64) {
+	if m.rs.OutputManifest == "" {
+		return
+	}
+	shard := &cmn.ManifestShard{
+		Name:        s.Name,
+		Size:        size,
+		Version:     lom.Version(),
+		RecordCount: s.Records.Len(),
+		Records:     make([]*cmn.ManifestRecord, 0, s.Records.Len()),
+	}
+	shard.SetCksum(lom.Cksum())
+	for _, record := range s.Records.All() {
+		mr := &cmn.ManifestRecord{
+			Name:    manifestRecordName(record.Name),
+			Objects: make([]*cmn.ManifestRecordObj, 0, len(record.Objects)),
+		}
+		for _, obj := range record.Objects {
+			mr.Objects = append(mr.Objects, &cmn.ManifestRecordObj{Extension: obj.Extension, Size: obj.Size})
+		}
+		shard.Records = append(shard.Records, mr)
+	}
+
+	m.creationPhase.manifest.Lock()
+	m.creationPhase.manifest.created = append(m.creationPhase.manifest.created, shard)
+	m.creationPhase.manifest.Unlock()
+}
+
+// addReceivedShards records the shards created by other target. The shards
+// are received together with the finished ack.
+func (m *Manager) addReceivedShards(shards []*cmn.ManifestShard) {
+	m.creationPhase.manifest.Lock()
+	m.creationPhase.manifest.received = append(m.creationPhase.manifest.received, shards...)
+	m.creationPhase.manifest.Unlock()
+}
+
+// createdShardsBody returns the body for finished ack which contains the shards
+// created by this target. It is nil if no manifest was requested.
+func (m *Manager) createdShardsBody() []byte {
+	if m.rs.OutputManifest == "" {
+		return nil
+	}
+	m.creationPhase.manifest.Lock()
+	body, err := js.Marshal(m.creationPhase.manifest.created)
+	m.creationPhase.manifest.Unlock()
+	cmn.AssertNoErr(err)
+	return body
+}
+
+// writeManifest puts the manifest into the output bucket. It must be called
+// only after all targets acknowledged finishing the dSort, ie. all shards
+// were created. Only the target to which the manifest belongs (according to
+// HRW) writes it.
+func (m *Manager) writeManifest() error {
+	if m.rs.OutputManifest == "" {
+		return nil
+	}
+
+	lom := &cluster.LOM{T: m.ctx.t, Objname: m.rs.OutputManifest}
+	if err := lom.Init(m.rs.OutputBucket, m.rs.OutputProvider); err != nil {
+		return err
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), m.ctx.smap.Get())
+	if err != nil {
+		return err
+	}
+	if si.DaemonID != m.ctx.node.DaemonID {
+		return nil
+	}
+
+	m.creationPhase.manifest.Lock()
+	manifest := &cmn.DatasetManifest{
+		JobID:    m.ManagerUUID,
+		Kind:     cmn.DSortNameLowercase,
+		Bucket:   m.rs.OutputBucket,
+		Provider: m.rs.OutputProvider,
+		Shards:   make([]*cmn.ManifestShard, 0, len(m.creationPhase.manifest.created)+len(m.creationPhase.manifest.received)),
+	}
+	manifest.Shards = append(manifest.Shards, m.creationPhase.manifest.created...)
+	manifest.Shards = append(manifest.Shards, m.creationPhase.manifest.received...)
+	m.creationPhase.manifest.Unlock()
+	sort.Slice(manifest.Shards, func(i, j int) bool { return manifest.Shards[i].Name < manifest.Shards[j].Name })
+
+	body, err := js.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	if m.rs.StreamOutput {
+		ctx, cancel := m.abortContext()
+		_, err = m.streamToCloud(ctx, lom, bytes.NewReader(body))
+		cancel()
+	} else {
+		workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, filetype.DSortWorkfileType, filetype.WorkfileManifest)
+		lom.SetAtimeUnix(time.Now().UnixNano())
+		err = m.ctx.t.PutObject(workFQN, ioutil.NopCloser(bytes.NewReader(body)), lom, cluster.WarmGet, nil, time.Now())
+	}
+	if err != nil {
+		return errors.Errorf("failed to write manifest %s, err: %v", lom, err)
+	}
+	glog.Infof("%s %s has written manifest %s with %d shards", cmn.DSortName, m.ManagerUUID, lom, len(manifest.Shards))
+	return nil
+}
+
+// manifestRecordName returns the name of the record as it is stored in the
+// shard: the record's unique name is prefixed with the name of the input shard
+// (see: RecordManager.genRecordUniqueName). Records duplicated by oversampling
+// keep their `~N` suffix so they can be told apart.
+func manifestRecordName(uniqueName string) string {
+	if idx := strings.IndexByte(uniqueName, '|'); idx >= 0 {
+		return uniqueName[idx+1:]
+	}
+	return uniqueName
+}
+
+// abortContext returns the context which is cancelled when dsort is aborted.
+// The returned cancel function must be called to release the resources.
+func (m *Manager) abortContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-m.listenAborted():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// streamToCloud uploads the object directly to the cloud bucket so that it is
+// never stored in the bucket locally; the memory usage is bounded by the cloud
+// provider's upload buffers. While being uploaded, the object is spooled to
+// a work file from which the failed upload is retried, up to
+// streamOutputRetries times. The work file is removed once done. The checksum
+// of the object is computed on the fly since it is not known in advance.
+func (m *Manager) streamToCloud(ctx context.Context, lom *cluster.LOM, r io.Reader) (size int64, err error) {
+	var (
+		version string
+		file    *os.File
+		hasher  = xxhash.New64()
+		workFQN = fs.CSM.GenContentParsedFQN(lom.ParsedFQN, filetype.DSortWorkfileType, filetype.WorkfileStreamShard)
+		sleep   = time.Second
+	)
+	if file, err = cmn.CreateFile(workFQN); err != nil {
+		return 0, err
+	}
+	defer func() {
+		file.Close()
+		if errRemove := cmn.RemoveFile(workFQN); errRemove != nil {
+			glog.Errorf("failed to remove %s, err: %v", workFQN, errRemove)
+		}
+	}()
+
+	cr := &countingReader{r: io.TeeReader(r, io.MultiWriter(hasher, file))}
+	version, err, _ = m.ctx.t.Cloud().PutObj(ctx, cr, lom)
+	for i := 0; err != nil; i++ {
+		if i >= streamOutputRetries || ctx.Err() != nil {
+			return cr.n, err
+		}
+		// spool the rest of the object which the failed upload has not consumed
+		if i == 0 {
+			if _, errSpool := io.Copy(ioutil.Discard, cr); errSpool != nil {
+				return cr.n, errSpool
+			}
+		}
+		glog.Warningf("failed to upload %s (retrying in %v), err: %v", lom, sleep, err)
+		select {
+		case <-time.After(sleep):
+		case <-ctx.Done():
+			return cr.n, err
+		}
+		sleep *= 2
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return cr.n, err
+		}
+		version, err, _ = m.ctx.t.Cloud().PutObj(ctx, io.LimitReader(file, cr.n), lom)
+	}
+	lom.SetVersion(version)
+	lom.SetCksum(cmn.NewCksum(cmn.ChecksumXXHash, cmn.HashToStr(hasher)))
+	return cr.n, nil
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ *
+ */
+
+// Package dsort provides APIs for distributed archive file shuffling.
+package dsort
+
+import (
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manifest", func() {
+	Context("manifestRecordName", func() {
+		It("should strip the input shard name", func() {
+			Expect(manifestRecordName("shard-1|record-1")).To(Equal("record-1"))
+			Expect(manifestRecordName("shard-1|dir/record-1~2")).To(Equal("dir/record-1~2"))
+			Expect(manifestRecordName("record-1")).To(Equal("record-1"))
+		})
+	})
+
+	Context("addCreatedShard", func() {
+		var (
+			shard = &extract.Shard{
+				Name: "output-1.tar",
+				Records: createRecordsFrom(
+					&extract.Record{Name: "input-1|a", Objects: []*extract.RecordObj{
+						{Extension: ".cls", Size: 10},
+						{Extension: ".jpg", Size: 1024},
+					}},
+					&extract.Record{Name: "input-2|b", Objects: []*extract.RecordObj{
+						{Extension: ".cls", Size: 12},
+					}},
+				),
+			}
+			lom = &cluster.LOM{Objname: shard.Name}
+		)
+
+		It("should not record shard when manifest was not requested", func() {
+			m := &Manager{rs: &ParsedRequestSpec{}}
+			m.addCreatedShard(shard, lom, 2048)
+			Expect(m.creationPhase.manifest.created).To(BeEmpty())
+			Expect(m.createdShardsBody()).To(BeNil())
+		})
+
+		It("should record shard together with its records", func() {
+			m := &Manager{rs: &ParsedRequestSpec{OutputManifest: "manifest.json"}}
+			m.addCreatedShard(shard, lom, 2048)
+
+			Expect(m.creationPhase.manifest.created).To(HaveLen(1))
+			created := m.creationPhase.manifest.created[0]
+			Expect(created.Name).To(Equal("output-1.tar"))
+			Expect(created.Size).To(Equal(int64(2048)))
+			Expect(created.RecordCount).To(Equal(2))
+			Expect(created.Records).To(Equal([]*cmn.ManifestRecord{
+				{Name: "a", Objects: []*cmn.ManifestRecordObj{{Extension: ".cls", Size: 10}, {Extension: ".jpg", Size: 1024}}},
+				{Name: "b", Objects: []*cmn.ManifestRecordObj{{Extension: ".cls", Size: 12}}},
+			}))
+
+			var shards []*cmn.ManifestShard
+			Expect(js.Unmarshal(m.createdShardsBody(), &shards)).NotTo(HaveOccurred())
+			Expect(shards).To(Equal(m.creationPhase.manifest.created))
+		})
+	})
+})
+
+func createRecordsFrom(records ...*extract.Record) *extract.Records {
+	r := extract.NewRecords(len(records))
+	r.Insert(records...)
+	return r
+}
@@ -166,6 +166,10 @@ type ShardCreation struct {
 	// data. Sometimes is faster to create shard on specific target and send it
 	// via network than create shard on destination target.
 	MovedShardCnt int64 `json:"moved_shard_count,string"`
+	// StreamedShardCnt describes number of shards that have been uploaded
+	// directly to the cloud bucket (see: RequestSpec.StreamOutput). They are
+	// not counted as moved.
+	StreamedShardCnt int64 `json:"streamed_shard_count,string"`
 	// RequestStats describes time statistics about request to other target.
 	RequestStats *TimeStats `json:"req_stats,omitempty"`
 	// ResponseStats describes time statistics about response to other target.
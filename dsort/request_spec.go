@@ -45,6 +45,9 @@ var (
 	errInvalidAlgorithmKind      = fmt.Errorf("invalid algorithm kind, should be one of: %+v", supportedAlgorithms)
 	errInvalidSeed               = errors.New("invalid seed provided, should be int")
 	errInvalidAlgorithmExtension = errors.New("invalid extension provided, should be in format: .ext")
+	errStreamOutputNotCloud      = errors.New("streaming output is supported only for cloud output bucket")
+	errShardIndexNotSupported    = errors.New("shard index is supported only for .tar and .zip extensions and cannot be used with streaming output")
+	errInvalidAlgorithmWeights   = fmt.Errorf("invalid weights provided, should be non-negative and used only with %q algorithm kind", SortKindStratified)
 )
 
 var (
@@ -97,6 +100,9 @@ type RequestSpec struct {
 	CreateConcLimit  int           `json:"create_concurrency_limit"`  // Default: DefaultConcLimit
 	StreamMultiplier int           `json:"stream_multiplier"`         // Default: transport.IntraBundleMultiplier
 	ExtendedMetrics  bool          `json:"extended_metrics"`          // Default: false
+	StreamOutput     bool          `json:"stream_output"`             // Default: false
+	OutputManifest   string        `json:"output_manifest"`           // Default: "" (no manifest)
+	ShardIndex       bool          `json:"shard_index"`               // Default: false
 
 	// debug
 	DSorterType string `json:"dsorter_type"`
@@ -123,6 +129,9 @@ type ParsedRequestSpec struct {
 	CreateConcLimit  int                   `json:"create_concurrency_limit"`  // TODO: should be removed
 	StreamMultiplier int                   `json:"stream_multiplier"`         // TODO: should be removed
 	ExtendedMetrics  bool                  `json:"extended_metrics"`
+	StreamOutput     bool                  `json:"stream_output"`
+	OutputManifest   string                `json:"output_manifest"`
+	ShardIndex       bool                  `json:"shard_index"`
 
 	// debug
 	DSorterType string `json:"dsorter_type"`
@@ -135,12 +144,18 @@ type SortAlgorithm struct {
 	// Kind: alphanumeric, content
 	Decreasing bool `json:"decreasing"`
 
-	// Kind: shuffle
+	// Kind: shuffle, stratified
 	Seed string `json:"seed"` // seed provided to random generator
 
-	// Kind: content
+	// Kind: content, stratified
 	Extension  string `json:"extension"`
 	FormatType string `json:"format_type"`
+
+	// Kind: stratified
+	// Maps the class label (content of the file with `Extension`) to the
+	// sampling weight of the class: weight < 1 drops (undersamples) records,
+	// weight > 1 duplicates (oversamples) them. Default weight is 1.
+	Weights map[string]float64 `json:"weights,omitempty"`
 }
 
 // Parse returns a non-nil error if a RequestSpec is invalid. When RequestSpec
@@ -164,6 +179,11 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 	if parsedRS.OutputProvider == "" {
 		parsedRS.OutputProvider = cmn.AIS
 	}
+	if rs.StreamOutput && !cmn.IsProviderCloud(parsedRS.OutputProvider) {
+		return nil, errStreamOutputNotCloud
+	}
+	parsedRS.StreamOutput = rs.StreamOutput
+	parsedRS.OutputManifest = strings.TrimSpace(rs.OutputManifest)
 
 	var err error
 	parsedRS.InputFormat, err = parseInputFormat(rs.InputFormat)
@@ -176,6 +196,11 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 	}
 	parsedRS.Extension = rs.Extension
 
+	if rs.ShardIndex && (rs.StreamOutput || !extract.SupportsShardIndex(rs.Extension)) {
+		return nil, errShardIndexNotSupported
+	}
+	parsedRS.ShardIndex = rs.ShardIndex
+
 	parsedRS.OutputShardSize, err = cmn.S2B(rs.OutputShardSize)
 	if err != nil {
 		return nil, err
@@ -275,7 +300,16 @@ func parseAlgorithm(algo SortAlgorithm) (parsedAlgo *SortAlgorithm, err error) {
 		}
 	}
 
-	if algo.Kind == SortKindContent {
+	if len(algo.Weights) > 0 && algo.Kind != SortKindStratified {
+		return nil, errInvalidAlgorithmWeights
+	}
+	for _, weight := range algo.Weights {
+		if weight < 0 {
+			return nil, errInvalidAlgorithmWeights
+		}
+	}
+
+	if algo.Kind == SortKindContent || algo.Kind == SortKindStratified {
 		algo.Extension = strings.TrimSpace(algo.Extension)
 		if algo.Extension == "" {
 			return nil, errInvalidAlgorithmExtension
@@ -295,6 +329,25 @@ func parseAlgorithm(algo SortAlgorithm) (parsedAlgo *SortAlgorithm, err error) {
 	return &algo, nil
 }
 
+// weight returns the sampling weight of the class with given label.
+func (algo *SortAlgorithm) weight(label string) float64 {
+	if weight, ok := algo.Weights[label]; ok {
+		return weight
+	}
+	return 1
+}
+
+// oversamples returns true if the algorithm can duplicate the records. The
+// contents of such records will be loaded more than once.
+func (algo *SortAlgorithm) oversamples() bool {
+	for _, weight := range algo.Weights {
+		if weight > 1 {
+			return true
+		}
+	}
+	return false
+}
+
 func validateOrderFileURL(orderURL string) (empty, valid bool) {
 	if orderURL == "" {
 		return true, true
@@ -8,6 +8,7 @@ package dsort
 
 import (
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/fs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -208,6 +209,29 @@ var _ = Describe("RequestSpec", func() {
 			Expect(parsed.CreateConcLimit).To(BeEquivalentTo(0))
 			Expect(parsed.ExtractConcLimit).To(BeEquivalentTo(0))
 		})
+
+		It("should parse spec with stratified algorithm and weights", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm: SortAlgorithm{
+					Kind:       SortKindStratified,
+					Extension:  ".cls",
+					FormatType: extract.FormatTypeInt,
+					Weights:    map[string]float64{"1": 0.5, "2": 3},
+				},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(parsed.Algorithm.Kind).To(Equal(SortKindStratified))
+			Expect(parsed.Algorithm.weight("1")).To(Equal(0.5))
+			Expect(parsed.Algorithm.weight("3")).To(Equal(1.0))
+			Expect(parsed.Algorithm.oversamples()).To(BeTrue())
+		})
 	})
 
 	Context("request specs which shall NOT pass", func() {
@@ -352,5 +376,69 @@ var _ = Describe("RequestSpec", func() {
 			Expect(err).Should(HaveOccurred())
 			Expect(err).To(Equal(errNegativeConcurrencyLimit))
 		})
+
+		It("should fail due to streaming output to ais bucket", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				OutputProvider:  cmn.AIS,
+				StreamOutput:    true,
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errStreamOutputNotCloud))
+		})
+
+		It("should fail due to shard index requested for compressed shards", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       ExtTgz,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				ShardIndex:      true,
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errShardIndexNotSupported))
+		})
+
+		It("should fail due to weights specified for non-stratified algorithm", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindShuffle, Weights: map[string]float64{"1": 2}},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errInvalidAlgorithm))
+		})
+
+		It("should fail due to negative weight specified", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm: SortAlgorithm{
+					Kind:       SortKindStratified,
+					Extension:  ".cls",
+					FormatType: extract.FormatTypeString,
+					Weights:    map[string]float64{"cat": -1},
+				},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errInvalidAlgorithm))
+		})
 	})
 })
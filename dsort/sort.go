@@ -5,6 +5,8 @@
 package dsort
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
 	"sort"
 	"strconv"
@@ -21,13 +23,24 @@ const (
 	SortKindMD5          = "md5"
 	SortKindShuffle      = "shuffle" // shuffle randomly, can be used with seed to get reproducible results
 	SortKindContent      = "content" // sort by content of given file
+	// shuffle randomly but distribute records evenly across output shards
+	// according to their class (content of given file), optionally over- or
+	// undersampling each class
+	SortKindStratified = "stratified"
 )
 
 var (
-	supportedAlgorithms = []string{sortKindEmpty, SortKindAlphanumeric, SortKindMD5, SortKindShuffle, SortKindContent, SortKindNone}
+	supportedAlgorithms = []string{sortKindEmpty, SortKindAlphanumeric, SortKindMD5, SortKindShuffle, SortKindContent, SortKindStratified, SortKindNone}
 )
 
 type (
+	// stratifiedRecord is a record with its relative position within its
+	// class - used to interleave the classes.
+	stratifiedRecord struct {
+		record *extract.Record
+		pos    float64
+	}
+
 	alphaByKey struct {
 		*extract.Records
 		decreasing bool
@@ -76,6 +89,8 @@ func sortRecords(r *extract.Records, algo *SortAlgorithm) (err error) {
 			j := rand.Intn(i + 1)
 			r.Swap(i, j)
 		}
+	} else if algo.Kind == SortKindStratified {
+		return stratifyRecords(r, algo)
 	} else {
 		keys := &alphaByKey{r, algo.Decreasing, algo.FormatType, nil}
 		sort.Sort(keys)
@@ -87,3 +102,92 @@ func sortRecords(r *extract.Records, algo *SortAlgorithm) (err error) {
 
 	return nil
 }
+
+// stratifyRecords shuffles records so that each class (determined by the
+// Record.Key) is spread evenly across all records. Then any contiguous range of
+// records (and therefore any output shard) contains classes in roughly the same
+// proportions as the whole dataset. Before interleaving, each class is
+// resampled according to its weight: weight < 1 drops records, weight > 1
+// duplicates them.
+func stratifyRecords(r *extract.Records, algo *SortAlgorithm) error {
+	var (
+		seed    = time.Now().Unix()
+		classes = make(map[string][]*extract.Record)
+		labels  = make([]string, 0, 10)
+	)
+	if algo.Seed != "" {
+		var err error
+		seed, err = strconv.ParseInt(algo.Seed, 10, 64)
+		cmn.AssertNoErr(err)
+	}
+
+	for _, record := range r.All() {
+		if record.Key == nil {
+			return fmt.Errorf("key is missing for %q", record.Name)
+		}
+		label := classLabel(record.Key, algo.FormatType)
+		if _, ok := classes[label]; !ok {
+			labels = append(labels, label)
+		}
+		classes[label] = append(classes[label], record)
+	}
+	// Iterating over a map is random so we need to sort the labels to make
+	// the result reproducible with given seed.
+	sort.Strings(labels)
+
+	var (
+		rnd    = rand.New(rand.NewSource(seed))
+		result = make([]stratifiedRecord, 0, r.Len())
+	)
+	for _, label := range labels {
+		records := classes[label]
+		rnd.Shuffle(len(records), func(i, j int) { records[i], records[j] = records[j], records[i] })
+		records = resampleClass(records, algo.weight(label))
+		for i, record := range records {
+			result = append(result, stratifiedRecord{
+				record: record,
+				pos:    (float64(i) + rnd.Float64()) / float64(len(records)),
+			})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].pos < result[j].pos })
+
+	records := make([]*extract.Record, len(result))
+	for i, sr := range result {
+		records[i] = sr.record
+	}
+	r.Replace(records)
+	return nil
+}
+
+// resampleClass changes the number of records in the (already shuffled) class
+// to `len(records) * weight`. Duplicated records share the objects with the
+// original record but get a unique name - the objects are also renamed in the
+// output shards (see: extract.DupName).
+func resampleClass(records []*extract.Record, weight float64) []*extract.Record {
+	n := int(math.Round(float64(len(records)) * weight))
+	if n <= len(records) {
+		return records[:n]
+	}
+	resampled := make([]*extract.Record, 0, n)
+	resampled = append(resampled, records...)
+	for i := len(records); i < n; i++ {
+		orig := records[i%len(records)]
+		dup := *orig
+		dup.Dup = i / len(records)
+		dup.Name = extract.DupName(orig.Name, dup.Dup)
+		resampled = append(resampled, &dup)
+	}
+	return resampled
+}
+
+// classLabel returns the string representation of the key which is used to
+// group the records into classes and to look up the class weights.
+func classLabel(key interface{}, formatType string) string {
+	// Integers can be parsed as float64 when records were sent between the
+	// targets, and then formatting them would result in exponent notation.
+	if f, ok := key.(float64); ok && formatType == extract.FormatTypeInt {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return fmt.Sprintf("%v", key)
+}
@@ -87,6 +87,53 @@ var _ = Describe("SortRecords", func() {
 		Expect(fm).To(Equal(expected))
 	})
 
+	It("should distribute classes evenly when stratified algorithm specified", func() {
+		fm := extract.NewRecords(100)
+		for i := 0; i < 100; i++ {
+			class := "a"
+			if i >= 75 {
+				class = "b"
+			}
+			fm.Insert(&extract.Record{Key: class, Name: fmt.Sprintf("%d", i)})
+		}
+
+		err := sortRecords(fm, &SortAlgorithm{Kind: SortKindStratified, Seed: "1010102", FormatType: extract.FormatTypeString})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fm.Len()).To(Equal(100))
+
+		// Every consecutive 20 records should keep the 3:1 class ratio.
+		for start := 0; start < 100; start += 20 {
+			count := 0
+			for _, r := range fm.All()[start : start+20] {
+				if r.Key == "b" {
+					count++
+				}
+			}
+			Expect(count).To(BeNumerically("~", 5, 1))
+		}
+	})
+
+	It("should resample classes according to weights when stratified algorithm specified", func() {
+		fm := createRecords(int64(1), int64(2), int64(3), int64(4))
+		fm.Insert(&extract.Record{Key: float64(1), Name: "one"}, &extract.Record{Key: int64(2), Name: "two"})
+
+		err := sortRecords(fm, &SortAlgorithm{
+			Kind:       SortKindStratified,
+			FormatType: extract.FormatTypeInt,
+			Weights:    map[string]float64{"1": 2, "2": 0.5, "3": 0},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		classes := make(map[string]int)
+		names := make(map[string]struct{})
+		for _, r := range fm.All() {
+			classes[classLabel(r.Key, extract.FormatTypeInt)]++
+			names[r.Name] = struct{}{}
+		}
+		Expect(classes).To(Equal(map[string]int{"1": 4, "2": 1, "4": 1}))
+		Expect(names).To(HaveLen(fm.Len()))
+	})
+
 	It("should return error when some keys are missing", func() {
 		fm := createRecords("def", "abc")
 		fm.All()[0].Key = nil
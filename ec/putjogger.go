@@ -19,6 +19,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/notif"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/OneOfOne/xxhash"
 	"github.com/klauspost/reedsolomon"
@@ -100,6 +101,9 @@ func (c *putJogger) ec(req *Request) error {
 	}
 	if err == nil {
 		c.parent.stats.updateObjTime(time.Since(req.putTime))
+		if req.Action == ActSplit {
+			notif.Notify(req.LOM, cmn.NotifECEncode)
+		}
 	}
 	return err
 }
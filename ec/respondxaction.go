@@ -218,6 +218,7 @@ func (r *XactRespond) DispatchResp(iReq IntraReq, bck *cluster.Bck, objName stri
 			}
 			lom.SetVersion(objAttrs.Version)
 			lom.SetAtimeUnix(objAttrs.Atime)
+			lom.SetWtimeUnix(objAttrs.Wtime)
 			lom.SetSize(objAttrs.Size)
 			if objAttrs.CksumType != "" {
 				lom.SetCksum(cmn.NewCksum(objAttrs.CksumType, objAttrs.CksumValue))
@@ -197,6 +197,7 @@ func (r *xactECBase) newReplicaResponse(attrs *transport.ObjectAttrs, fqn string
 	attrs.Size = lom.Size()
 	attrs.Version = lom.Version()
 	attrs.Atime = lom.Atime().UnixNano()
+	attrs.Wtime = lom.WtimeUnix()
 	if lom.Cksum() != nil {
 		attrs.CksumType, attrs.CksumValue = lom.Cksum().Get()
 	}
@@ -383,6 +384,7 @@ func (r *xactECBase) writeRemote(daemonIDs []string, lom *cluster.LOM, src *data
 		Size:    src.size,
 		Version: lom.Version(),
 		Atime:   lom.Atime().UnixNano(),
+		Wtime:   lom.WtimeUnix(),
 	}
 	if src.metadata != nil && src.metadata.SliceID != 0 {
 		// for a slice read everything from slice's metadata
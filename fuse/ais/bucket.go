@@ -5,22 +5,62 @@
 package ais
 
 import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cmn"
 )
 
 type Bucket struct {
 	name      string
+	provider  string
 	apiParams api.BaseParams
 }
 
-func NewBucket(name string, apiParams api.BaseParams) *Bucket {
+func NewBucket(name, provider string, apiParams api.BaseParams) *Bucket {
 	return &Bucket{
 		name:      name,
+		provider:  provider,
 		apiParams: apiParams,
 	}
 }
 
+func (bck *Bucket) Name() string     { return bck.name }
+func (bck *Bucket) Provider() string { return bck.provider }
+
+func (bck *Bucket) query() url.Values {
+	return url.Values{cmn.URLParamProvider: []string{bck.provider}}
+}
+
+func (bck *Bucket) Exists() (exists bool, err error) {
+	_, err = api.HeadBucket(bck.apiParams, bck.name, bck.query())
+	if err == nil {
+		return true, nil
+	}
+	var httpErr *cmn.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound {
+		return false, nil
+	}
+	return false, newBucketIOError(err, "Exists")
+}
+
+func (bck *Bucket) Create() (err error) {
+	if err = api.CreateBucket(bck.apiParams, bck.name); err != nil {
+		err = newBucketIOError(err, "Create")
+	}
+	return
+}
+
+func (bck *Bucket) Destroy() (err error) {
+	if err = api.DestroyBucket(bck.apiParams, bck.name); err != nil {
+		err = newBucketIOError(err, "Destroy")
+	}
+	return
+}
+
 func (bck *Bucket) ListObjects(prefix, pageMarker string, pageSize int) (objs []*Object, newPageMarker string, err error) {
 	selectMsg := &cmn.SelectMsg{
 		Prefix:     prefix,
@@ -28,7 +68,7 @@ func (bck *Bucket) ListObjects(prefix, pageMarker string, pageSize int) (objs []
 		PageMarker: pageMarker,
 		PageSize:   pageSize,
 	}
-	listResult, err := api.ListBucketFast(bck.apiParams, bck.name, selectMsg)
+	listResult, err := api.ListBucketFast(bck.apiParams, bck.name, selectMsg, bck.query())
 	if err != nil {
 		return nil, "", newBucketIOError(err, "ListObjects")
 	}
@@ -41,10 +81,28 @@ func (bck *Bucket) ListObjects(prefix, pageMarker string, pageSize int) (objs []
 	return
 }
 
+// Changes returns the changes of the bucket namespace that follow `token`,
+// waiting for them up to `wait` (see api.GetBucketChanges).
+func (bck *Bucket) Changes(token string, wait time.Duration) (changes *cmn.BucketChanges, err error) {
+	changes, err = api.GetBucketChanges(bck.apiParams, bck.name, bck.provider, token, wait)
+	if err != nil {
+		err = newBucketIOError(err, "Changes")
+	}
+	return
+}
+
 func (bck *Bucket) DeleteObject(objName string) (err error) {
-	err = api.DeleteObject(bck.apiParams, bck.name, objName, "")
+	err = api.DeleteObject(bck.apiParams, bck.name, objName, bck.provider)
 	if err != nil {
 		err = newBucketIOError(err, "DeleteObject", objName)
 	}
 	return
 }
+
+func (bck *Bucket) RenameObject(oldName, newName string) (err error) {
+	err = api.RenameObject(bck.apiParams, bck.name, oldName, newName, bck.provider)
+	if err != nil {
+		err = newBucketIOError(err, "RenameObject", oldName)
+	}
+	return
+}
@@ -0,0 +1,34 @@
+// Package ais implements an AIStore client.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+type Cluster struct {
+	apiParams api.BaseParams
+}
+
+func NewCluster(apiParams api.BaseParams) *Cluster {
+	return &Cluster{apiParams: apiParams}
+}
+
+func (c *Cluster) Bucket(name, provider string) *Bucket {
+	return NewBucket(name, provider, c.apiParams)
+}
+
+// ListBuckets returns names of the buckets of the given provider (cmn.AIS or cmn.Cloud).
+func (c *Cluster) ListBuckets(provider string) (names []string, err error) {
+	bucketNames, err := api.GetBucketNames(c.apiParams, provider)
+	if err != nil {
+		return nil, newBucketIOError(err, "ListBuckets")
+	}
+	if cmn.IsProviderAIS(provider) {
+		return bucketNames.AIS, nil
+	}
+	return bucketNames.Cloud, nil
+}
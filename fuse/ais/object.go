@@ -17,6 +17,7 @@ import (
 type Object struct {
 	apiParams api.BaseParams // FIXME: it is quite a big struct and should be removed
 	bucket    string         // FIXME: bucket name is static so we should not have it as a field
+	provider  string
 	Name      string
 	Size      int64
 	Atime     time.Time
@@ -31,16 +32,36 @@ func NewObject(objName string, bucket *Bucket, sizes ...int64) *Object {
 	return &Object{
 		apiParams: bucket.apiParams,
 		bucket:    bucket.name,
+		provider:  bucket.provider,
 		Name:      objName,
 		Size:      size,
 		Atime:     time.Now(),
 	}
 }
 
+func (obj *Object) Bucket() string   { return obj.bucket }
+func (obj *Object) Provider() string { return obj.provider }
+
+// Renamed returns a copy of the object with the new name.
+func (obj *Object) Renamed(newName string) *Object {
+	renamed := *obj
+	renamed.Name = newName
+	return &renamed
+}
+
+func (obj *Object) Head() (props *cmn.ObjectProps, err error) {
+	props, err = api.HeadObject(obj.apiParams, obj.bucket, obj.provider, obj.Name)
+	if err != nil {
+		err = newObjectIOError(err, "Head", obj.Name)
+	}
+	return
+}
+
 func (obj *Object) Put(r cmn.ReadOpenCloser) (err error) {
 	putArgs := api.PutObjectArgs{
 		BaseParams: obj.apiParams,
 		Bucket:     obj.bucket,
+		Provider:   obj.provider,
 		Object:     obj.Name,
 		Reader:     r,
 	}
@@ -53,6 +74,7 @@ func (obj *Object) Put(r cmn.ReadOpenCloser) (err error) {
 
 func (obj *Object) GetChunk(w io.Writer, offset int64, length int64) (n int64, err error) {
 	query := url.Values{}
+	query.Add(cmn.URLParamProvider, obj.provider)
 	query.Add(cmn.URLParamOffset, strconv.FormatInt(offset, 10))
 	query.Add(cmn.URLParamLength, strconv.FormatInt(length, 10))
 	objArgs := api.GetObjectInput{
@@ -71,6 +93,7 @@ func (obj *Object) Append(r cmn.ReadOpenCloser, prevHandle string, size int64) (
 	appendArgs := api.AppendArgs{
 		BaseParams: obj.apiParams,
 		Bucket:     obj.bucket,
+		Provider:   obj.provider,
 		Object:     obj.Name,
 		Handle:     prevHandle,
 		Reader:     r,
@@ -88,6 +111,7 @@ func (obj *Object) Flush(handle string) (err error) {
 	appendArgs := api.AppendArgs{
 		BaseParams: obj.apiParams,
 		Bucket:     obj.bucket,
+		Provider:   obj.provider,
 		Object:     obj.Name,
 		Handle:     handle,
 	}
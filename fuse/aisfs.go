@@ -31,10 +31,11 @@ VERSION:
 	{{ .Version }}
 
 USAGE:
-	{{ .Name }} [OPTION...] BUCKET MOUNTPOINT
+	{{ .Name }} [OPTION...] [BUCKET] MOUNTPOINT
 
 ARGUMENTS:
-	BUCKET      bucket name
+	BUCKET      bucket name; if omitted, the whole cluster is mounted with
+	            a directory for each ais bucket and cloud buckets under 'cloud/'
 	MOUNTPOINT  empty directory for mounting the file system
 
 OPTIONS: (must appear before arguments, see USAGE)
@@ -109,7 +110,7 @@ func dispatchSignalHandlers(mountPath string, mntCfg *fuse.MountConfig, serverCf
 				}
 				mntCfg.ErrorLogger.Printf("Failed to unmount upon SIGINT: %v", err)
 			case syscall.SIGHUP:
-				cfg, err := loadConfig(serverCfg.BucketName)
+				cfg, err := loadConfig(mountName(serverCfg.BucketName))
 				if err != nil {
 					mntCfg.ErrorLogger.Printf("Failed to reload config upon SIGHUP: %v", err)
 					break
@@ -181,6 +182,7 @@ func appMain(c *cli.Context) (err error) {
 		cfg       *Config
 		cluURL    string
 		bucket    string
+		provider  string
 		mountDir  string
 		mountPath string
 		errorLog  *log.Logger
@@ -198,9 +200,6 @@ func appMain(c *cli.Context) (err error) {
 	}
 
 	if c.NArg() < 1 {
-		return missingArgumentsError("BUCKET", "MOUNTPOINT")
-	}
-	if c.NArg() < 2 {
 		return missingArgumentsError("MOUNTPOINT")
 	}
 	if c.NArg() > 2 {
@@ -208,8 +207,10 @@ func appMain(c *cli.Context) (err error) {
 	}
 
 	flags = parseFlags(c)
-	bucket = c.Args().Get(0)
-	mountDir = c.Args().Get(1)
+	if c.NArg() == 2 {
+		bucket = c.Args().Get(0)
+	}
+	mountDir = c.Args().Get(c.NArg() - 1)
 
 	mountPath, err = filepath.Abs(mountDir)
 	if err != nil {
@@ -244,40 +245,46 @@ func appMain(c *cli.Context) (err error) {
 	}
 
 	// Try to load existing config from file or use default one.
-	cfg, err = loadConfig(bucket)
+	cfg, err = loadConfig(mountName(bucket))
 	if err != nil {
 		return
 	}
 
 	// Validate and test cluster URL.
-	cluURL, err = determineClusterURL(c, cfg, bucket)
+	cluURL, provider, err = determineClusterURL(c, cfg, bucket)
 	if err != nil {
 		return
 	}
 
-	errorLog, err = prepareLogFile(cfg.Log.ErrorFile, "ERROR: ", bucket)
+	errorLog, err = prepareLogFile(cfg.Log.ErrorFile, "ERROR: ", mountName(bucket))
 	if err != nil {
 		return
 	}
 
 	// If cfg.Log.DebugFile == "" no debug logging is performed.
 	if cfg.Log.DebugFile != "" {
-		debugLog, err = prepareLogFile(cfg.Log.DebugFile, "DEBUG: ", bucket)
+		debugLog, err = prepareLogFile(cfg.Log.DebugFile, "DEBUG: ", mountName(bucket))
 		if err != nil {
 			return
 		}
 	}
 
 	// Useful message describing some fs params, printed only if --wait flag was given by the user.
-	fmt.Fprintf(c.App.Writer, "Connecting to proxy at %q\nMounting bucket %q to %q\nuid %d\ngid %d\n",
-		cluURL, bucket, mountPath, fsowner.UID, fsowner.GID)
+	if bucket != "" {
+		fmt.Fprintf(c.App.Writer, "Connecting to proxy at %q\nMounting bucket %q to %q\nuid %d\ngid %d\n",
+			cluURL, bucket, mountPath, fsowner.UID, fsowner.GID)
+	} else {
+		fmt.Fprintf(c.App.Writer, "Connecting to proxy at %q\nMounting cluster to %q\nuid %d\ngid %d\n",
+			cluURL, mountPath, fsowner.UID, fsowner.GID)
+	}
 
 	// Init a server configuration object.
 	serverCfg := &fs.ServerConfig{
-		MountPath:  mountPath,
-		AISURL:     cluURL,
-		BucketName: bucket,
-		Owner:      fsowner,
+		MountPath:      mountPath,
+		AISURL:         cluURL,
+		BucketName:     bucket,
+		BucketProvider: provider,
+		Owner:          fsowner,
 	}
 	cfg.writeTo(serverCfg)
 
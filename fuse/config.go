@@ -14,7 +14,13 @@ import (
 	"github.com/NVIDIA/aistore/fuse/fs"
 )
 
-const configDirName = fs.Name
+const (
+	configDirName = fs.Name
+
+	// Name (in place of the bucket name) of the config and log files when
+	// the whole cluster is mounted.
+	clusterMountName = "cluster"
+)
 
 var defaultConfig = Config{
 	Cluster: ClusterConfig{
@@ -38,19 +44,27 @@ var defaultConfig = Config{
 		// Determines the size of chunks that we write with append. The only exception
 		// when we write less is Flush (end-of-file).
 		WriteBufSize: cmn.MiB,
+		// Empty value means the default directory for temporary files.
+		SpillDir: "",
 	},
 	// By default we allow unlimited memory to be used by the cache.
 	MemoryLimit: "0B",
+	// By default the block cache is disabled (no directory).
+	BlockCache: BlockCacheConfig{
+		Dir:       "",
+		SizeLimit: "10GiB",
+	},
 }
 
 type (
 	Config struct {
-		Cluster     ClusterConfig  `json:"cluster"`
-		Timeout     TimeoutConfig  `json:"timeout"`
-		Periodic    PeriodicConfig `json:"periodic"`
-		Log         LogConfig      `json:"log"`
-		IO          IOConfig       `json:"io"`
-		MemoryLimit string         `json:"memory_limit"`
+		Cluster     ClusterConfig    `json:"cluster"`
+		Timeout     TimeoutConfig    `json:"timeout"`
+		Periodic    PeriodicConfig   `json:"periodic"`
+		Log         LogConfig        `json:"log"`
+		IO          IOConfig         `json:"io"`
+		MemoryLimit string           `json:"memory_limit"`
+		BlockCache  BlockCacheConfig `json:"block_cache"`
 	}
 
 	ClusterConfig struct {
@@ -75,7 +89,13 @@ type (
 	}
 
 	IOConfig struct {
-		WriteBufSize int64 `json:"write_buf_size"`
+		WriteBufSize int64  `json:"write_buf_size"`
+		SpillDir     string `json:"spill_dir"`
+	}
+
+	BlockCacheConfig struct {
+		Dir       string `json:"dir"`
+		SizeLimit string `json:"size_limit"`
 	}
 )
 
@@ -98,11 +118,22 @@ func (c *Config) validate() (err error) {
 	if c.IO.WriteBufSize < 0 {
 		return fmt.Errorf("invalid io.write_buf_size value: %d: expected non-negative value", c.IO.WriteBufSize)
 	}
+	if c.IO.SpillDir != "" && !filepath.IsAbs(c.IO.SpillDir) {
+		return fmt.Errorf("invalid io.spill_dir format %q: path needs to be absolute", c.IO.SpillDir)
+	}
 	if v, err := cmn.S2B(c.MemoryLimit); err != nil {
 		return fmt.Errorf("invalid memory_limit value: %q: %v", c.MemoryLimit, err)
 	} else if v < 0 {
 		return fmt.Errorf("invalid memory_limit value: %q: expected non-negative value", c.MemoryLimit)
 	}
+	if c.BlockCache.Dir != "" && !filepath.IsAbs(c.BlockCache.Dir) {
+		return fmt.Errorf("invalid block_cache.dir format %q: path needs to be absolute", c.BlockCache.Dir)
+	}
+	if v, err := cmn.S2B(c.BlockCache.SizeLimit); err != nil {
+		return fmt.Errorf("invalid block_cache.size_limit value: %q: %v", c.BlockCache.SizeLimit, err)
+	} else if c.BlockCache.Dir != "" && v <= 0 {
+		return fmt.Errorf("invalid block_cache.size_limit value: %q: expected positive value", c.BlockCache.SizeLimit)
+	}
 	return nil
 }
 
@@ -113,6 +144,15 @@ func (c *Config) writeTo(srvCfg *fs.ServerConfig) {
 	srvCfg.SyncInterval.Store(c.Periodic.SyncInterval)
 	srvCfg.MemoryLimit.Store(uint64(memoryLimit))
 	srvCfg.MaxWriteBufSize.Store(c.IO.WriteBufSize)
+	// NOTE: changing the block cache and spill directories requires remount.
+	if srvCfg.BlockCacheDir == "" {
+		srvCfg.BlockCacheDir = c.BlockCache.Dir
+	}
+	if srvCfg.SpillDir == "" {
+		srvCfg.SpillDir = c.IO.SpillDir
+	}
+	blockCacheLimit, _ := cmn.S2B(c.BlockCache.SizeLimit)
+	srvCfg.BlockCacheLimit.Store(blockCacheLimit)
 }
 
 func loadConfig(bucket string) (cfg *Config, err error) {
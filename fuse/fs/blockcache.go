@@ -0,0 +1,235 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/fuse/ais"
+)
+
+// Theory of operation
+//
+// Block cache is an optional on-disk cache of the blocks read from the cluster
+// (see blockBuffer). Every block is stored as a separate file named after the
+// hash of the bucket and object name, its version and checksum (as returned by HEAD) and
+// the block offset and length. Thus, a new version of the object never hits
+// the blocks of the older one - the latter simply age out.
+//
+// When the total size of the cached blocks exceeds the limit, the least
+// recently used blocks get evicted. The cache is persistent: upon restart it
+// loads the blocks found in its directory, ordered by modification time which
+// is updated on every access.
+
+const blockWorkfilePattern = "blk*.tmp"
+
+type (
+	blockCache struct {
+		dir string
+		cfg *ServerConfig
+
+		// Guard
+		mu    sync.Mutex
+		size  int64
+		lru   *list.List               // front is the most recently used block
+		index map[string]*list.Element // key => *cachedBlock
+	}
+
+	cachedBlock struct {
+		key  string
+		size int64
+	}
+)
+
+func newBlockCache(cfg *ServerConfig) (*blockCache, error) {
+	c := &blockCache{
+		dir:   cfg.BlockCacheDir,
+		cfg:   cfg,
+		lru:   list.New(),
+		index: make(map[string]*list.Element),
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create block cache directory %q: %v", c.dir, err)
+	}
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("failed to load block cache from %q: %v", c.dir, err)
+	}
+	return c, nil
+}
+
+// load populates the cache with the blocks stored by the previous runs.
+func (c *blockCache) load() error {
+	type fileInfo struct {
+		key   string
+		size  int64
+		mtime time.Time
+	}
+	var files []fileInfo
+	err := filepath.Walk(c.dir, func(fqn string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		// Remove leftovers of interrupted writes.
+		if matched, _ := filepath.Match(blockWorkfilePattern, fi.Name()); matched {
+			os.Remove(fqn)
+			return nil
+		}
+		if len(fi.Name()) != 2*sha256.Size || c.fqn(fi.Name()) != fqn {
+			return nil // not a block
+		}
+		files = append(files, fileInfo{key: fi.Name(), size: fi.Size(), mtime: fi.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].mtime.Before(files[j].mtime) })
+	c.mu.Lock()
+	for _, f := range files {
+		c.index[f.key] = c.lru.PushFront(&cachedBlock{key: f.key, size: f.size})
+		c.size += f.size
+	}
+	c.evict()
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *blockCache) key(obj *ais.Object, version, cksum string, offset, length int64) string {
+	h := sha256.Sum256([]byte(strings.Join([]string{
+		obj.Provider(), obj.Bucket(), obj.Name, version, cksum, fmt.Sprint(offset), fmt.Sprint(length),
+	}, "\x00")))
+	return hex.EncodeToString(h[:])
+}
+
+func (c *blockCache) fqn(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+// LOCKS(c.mu)
+func (c *blockCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Get writes the cached block to `w`. Returns false if the block is not cached.
+// LOCKS(c.mu)
+func (c *blockCache) Get(key string, w io.Writer) (n int64, ok bool, err error) {
+	c.mu.Lock()
+	el, ok := c.index[key]
+	if ok {
+		c.lru.MoveToFront(el)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	fqn := c.fqn(key)
+	f, err := os.Open(fqn)
+	if err != nil {
+		// The block is gone (eg. removed by the user) - treat it as a miss.
+		c.remove(key)
+		return 0, false, nil
+	}
+	n, err = io.Copy(w, f)
+	f.Close()
+	if err != nil {
+		c.remove(key)
+		return
+	}
+	now := time.Now()
+	os.Chtimes(fqn, now, now)
+	return
+}
+
+// Load writes the block to `w` from the cache or, on miss, from `loadBlock`
+// which output is then cached.
+// LOCKS(c.mu)
+func (c *blockCache) Load(key string, w io.Writer, loadBlock func(w io.Writer) (int64, error)) (n int64, err error) {
+	var ok bool
+	if n, ok, err = c.Get(key, w); ok {
+		return
+	}
+
+	f, err := ioutil.TempFile(c.dir, blockWorkfilePattern)
+	if err != nil {
+		// Failing to cache is not fatal - just read through.
+		return loadBlock(w)
+	}
+	if n, err = loadBlock(io.MultiWriter(w, f)); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return
+	}
+	if err := c.put(key, f, n); err != nil {
+		os.Remove(f.Name())
+	}
+	return n, nil
+}
+
+// LOCKS(c.mu)
+func (c *blockCache) put(key string, f *os.File, size int64) (err error) {
+	if err = f.Close(); err != nil {
+		return
+	}
+	fqn := c.fqn(key)
+	if err = os.MkdirAll(filepath.Dir(fqn), 0700); err != nil {
+		return
+	}
+	if err = os.Rename(f.Name(), fqn); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	if el, ok := c.index[key]; ok {
+		// Concurrently loaded by another handle - the file has been replaced.
+		c.size -= el.Value.(*cachedBlock).size
+		c.lru.Remove(el)
+	}
+	c.index[key] = c.lru.PushFront(&cachedBlock{key: key, size: size})
+	c.size += size
+	c.evict()
+	c.mu.Unlock()
+	return
+}
+
+// LOCKS(c.mu)
+func (c *blockCache) remove(key string) {
+	c.mu.Lock()
+	if el, ok := c.index[key]; ok {
+		c.removeEntry(el)
+	}
+	c.mu.Unlock()
+}
+
+// REQUIRES_LOCK(c.mu)
+func (c *blockCache) evict() {
+	limit := c.cfg.BlockCacheLimit.Load()
+	for c.size > limit && c.lru.Len() > 0 {
+		c.removeEntry(c.lru.Back())
+	}
+}
+
+// REQUIRES_LOCK(c.mu)
+func (c *blockCache) removeEntry(el *list.Element) {
+	block := el.Value.(*cachedBlock)
+	os.Remove(c.fqn(block.key))
+	c.lru.Remove(el)
+	delete(c.index, block.key)
+	c.size -= block.size
+}
@@ -0,0 +1,135 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fuse/ais"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BlockCache", func() {
+	var (
+		dir   string
+		cfg   *ServerConfig
+		cache *blockCache
+
+		obj = ais.NewObject("obj", ais.NewBucket("bucket", cmn.AIS, api.BaseParams{}))
+	)
+
+	const blockSize = 1024
+
+	loadFunc := func(content byte, loads *int) func(w io.Writer) (int64, error) {
+		return func(w io.Writer) (int64, error) {
+			*loads++
+			n, err := w.Write(bytes.Repeat([]byte{content}, blockSize))
+			return int64(n), err
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "blockcache")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg = &ServerConfig{BlockCacheDir: dir}
+		cfg.BlockCacheLimit.Store(4 * blockSize)
+		cache, err = newBlockCache(cfg)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should load block only once", func() {
+		var (
+			loads int
+			key   = cache.key(obj, "1", "abc", 0, blockSize)
+		)
+		for i := 0; i < 3; i++ {
+			buf := &bytes.Buffer{}
+			n, err := cache.Load(key, buf, loadFunc('a', &loads))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(BeEquivalentTo(blockSize))
+			Expect(buf.Bytes()).To(Equal(bytes.Repeat([]byte{'a'}, blockSize)))
+		}
+		Expect(loads).To(Equal(1))
+		Expect(cache.Size()).To(BeEquivalentTo(blockSize))
+	})
+
+	It("should not hit block of another object version", func() {
+		var loads int
+		cache.Load(cache.key(obj, "1", "abc", 0, blockSize), ioutil.Discard, loadFunc('a', &loads))
+
+		buf := &bytes.Buffer{}
+		_, err := cache.Load(cache.key(obj, "2", "def", 0, blockSize), buf, loadFunc('b', &loads))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.Bytes()).To(Equal(bytes.Repeat([]byte{'b'}, blockSize)))
+		Expect(loads).To(Equal(2))
+	})
+
+	It("should not cache block on load error", func() {
+		key := cache.key(obj, "1", "abc", 0, blockSize)
+		_, err := cache.Load(key, ioutil.Discard, func(w io.Writer) (int64, error) {
+			return 0, errors.New("failed")
+		})
+		Expect(err).To(HaveOccurred())
+		_, ok, _ := cache.Get(key, ioutil.Discard)
+		Expect(ok).To(BeFalse())
+		Expect(cache.Size()).To(BeZero())
+	})
+
+	It("should evict least recently used blocks", func() {
+		var (
+			loads int
+			keys  []string
+		)
+		for i := int64(0); i < 4; i++ {
+			key := cache.key(obj, "1", "abc", i*blockSize, blockSize)
+			cache.Load(key, ioutil.Discard, loadFunc('a', &loads))
+			keys = append(keys, key)
+		}
+		// Access the first block so the second one becomes the least recently used.
+		_, ok, _ := cache.Get(keys[0], ioutil.Discard)
+		Expect(ok).To(BeTrue())
+
+		cache.Load(cache.key(obj, "1", "abc", 4*blockSize, blockSize), ioutil.Discard, loadFunc('a', &loads))
+		Expect(cache.Size()).To(BeEquivalentTo(4 * blockSize))
+
+		_, ok, _ = cache.Get(keys[1], ioutil.Discard)
+		Expect(ok).To(BeFalse())
+		for _, key := range []string{keys[0], keys[2], keys[3]} {
+			_, ok, _ = cache.Get(key, ioutil.Discard)
+			Expect(ok).To(BeTrue())
+		}
+	})
+
+	It("should load blocks persisted by previous run", func() {
+		var (
+			loads int
+			key   = cache.key(obj, "1", "abc", 0, blockSize)
+		)
+		cache.Load(key, ioutil.Discard, loadFunc('a', &loads))
+
+		cache, err := newBlockCache(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cache.Size()).To(BeEquivalentTo(blockSize))
+
+		buf := &bytes.Buffer{}
+		_, ok, err := cache.Get(key, buf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(buf.Bytes()).To(Equal(bytes.Repeat([]byte{'a'}, blockSize)))
+	})
+})
@@ -7,6 +7,8 @@ package fs
 import (
 	"errors"
 	"io"
+	"io/ioutil"
+	"os"
 
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/memsys"
@@ -15,6 +17,10 @@ import (
 const (
 	maxBlockSize = memsys.MaxSlabSize
 	minBlockSize = cmn.PageSize
+
+	// Files staged for random writes are kept in memory up to this size,
+	// larger ones get spilled to a local file.
+	maxStagingMemSize = 8 * cmn.MiB
 )
 
 type (
@@ -30,6 +36,15 @@ type (
 	writeBuffer struct {
 		sgl *memsys.SGL
 	}
+
+	// stagingBuffer keeps the entire content of a file that is written at
+	// arbitrary offsets (or truncated) until it is uploaded as a whole.
+	stagingBuffer struct {
+		buf      []byte
+		file     *os.File // spill file, nil while the content is kept in memory
+		spillDir string
+		size     int64
+	}
 )
 
 // Panics if blockSize has an invalid value, see memsys.(*Mem2).NewSGL
@@ -64,6 +79,10 @@ func (b *blockBuffer) EnsureBlock(blockNo int64, loadBlock loadBlockFunc) (err e
 	return
 }
 
+func (b *blockBuffer) Invalidate() {
+	b.valid = false
+}
+
 func (b *blockBuffer) ReadAt(p []byte, offset int64) (n int, err error) {
 	cmn.Assert(b.sgl != nil)
 	if !b.valid {
@@ -85,3 +104,122 @@ func (b *writeBuffer) size() int64                 { return b.sgl.Size() }
 func (b *writeBuffer) reset()                      { b.sgl.Reset() }
 func (b *writeBuffer) write(p []byte) (int, error) { return b.sgl.Write(p) }
 func (b *writeBuffer) free()                       { b.sgl.Free() }
+
+func newStagingBuffer(spillDir string) *stagingBuffer {
+	return &stagingBuffer{spillDir: spillDir}
+}
+
+func (b *stagingBuffer) Size() int64 { return b.size }
+
+// Write appends data at the end of the buffer.
+func (b *stagingBuffer) Write(p []byte) (int, error) {
+	return b.WriteAt(p, b.size)
+}
+
+func (b *stagingBuffer) WriteAt(p []byte, offset int64) (n int, err error) {
+	end := offset + int64(len(p))
+	if b.file == nil && end > maxStagingMemSize {
+		if err = b.spill(); err != nil {
+			return 0, err
+		}
+	}
+	if b.file != nil {
+		n, err = b.file.WriteAt(p, offset)
+	} else {
+		b.grow(end)
+		n = copy(b.buf[offset:], p)
+	}
+	if end > b.size {
+		b.size = end
+	}
+	return
+}
+
+func (b *stagingBuffer) ReadAt(p []byte, offset int64) (n int, err error) {
+	if offset >= b.size {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.size-offset {
+		p = p[:b.size-offset]
+		err = io.EOF
+	}
+	if b.file != nil {
+		var rerr error
+		if n, rerr = b.file.ReadAt(p, offset); rerr != nil {
+			err = rerr
+		}
+		return
+	}
+	n = copy(p, b.buf[offset:b.size])
+	return
+}
+
+func (b *stagingBuffer) Truncate(size int64) (err error) {
+	if b.file == nil && size > maxStagingMemSize {
+		if err = b.spill(); err != nil {
+			return
+		}
+	}
+	if b.file != nil {
+		err = b.file.Truncate(size)
+	} else if size > b.size {
+		b.grow(size)
+	} else {
+		b.buf = b.buf[:size]
+	}
+	if err == nil {
+		b.size = size
+	}
+	return
+}
+
+// reader returns the content of the buffer to be uploaded.
+func (b *stagingBuffer) reader() (cmn.ReadOpenCloser, error) {
+	if b.file != nil {
+		return cmn.NewFileSectionHandle(b.file, 0, b.size, 0)
+	}
+	return cmn.NewByteHandle(b.buf[:b.size]), nil
+}
+
+func (b *stagingBuffer) free() {
+	if b.file != nil {
+		b.file.Close()
+		os.Remove(b.file.Name())
+		b.file = nil
+	}
+	b.buf = nil
+	b.size = 0
+}
+
+// grow extends the in-memory buffer with zeros.
+func (b *stagingBuffer) grow(size int64) {
+	if size <= int64(len(b.buf)) {
+		return
+	}
+	if size <= int64(cap(b.buf)) {
+		tail := b.buf[len(b.buf):size]
+		for i := range tail {
+			tail[i] = 0
+		}
+		b.buf = b.buf[:size]
+		return
+	}
+	buf := make([]byte, size, cmn.MaxI64(size, 2*int64(cap(b.buf))))
+	copy(buf, b.buf)
+	b.buf = buf
+}
+
+// spill moves the content of the buffer to a local file.
+func (b *stagingBuffer) spill() (err error) {
+	if b.file, err = ioutil.TempFile(b.spillDir, "aisfs-spill-"); err != nil {
+		return
+	}
+	if _, err = b.file.Write(b.buf[:b.size]); err != nil {
+		b.file.Close()
+		os.Remove(b.file.Name())
+		b.file = nil
+		return
+	}
+	b.buf = nil
+	return
+}
@@ -0,0 +1,86 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StagingBuffer", func() {
+	var (
+		dir string
+		buf *stagingBuffer
+	)
+
+	readAll := func() []byte {
+		r, err := buf.reader()
+		Expect(err).NotTo(HaveOccurred())
+		rc, err := r.Open()
+		Expect(err).NotTo(HaveOccurred())
+		b, err := ioutil.ReadAll(rc)
+		Expect(err).NotTo(HaveOccurred())
+		return b
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "staging")
+		Expect(err).NotTo(HaveOccurred())
+		buf = newStagingBuffer(dir)
+	})
+
+	AfterEach(func() {
+		buf.free()
+		os.RemoveAll(dir)
+	})
+
+	It("should write at arbitrary offsets", func() {
+		buf.Write([]byte("0123456789"))
+		buf.WriteAt([]byte("abc"), 3)
+		buf.WriteAt([]byte("xy"), 12)
+		Expect(buf.Size()).To(BeEquivalentTo(14))
+		Expect(readAll()).To(Equal([]byte("012abc6789\x00\x00xy")))
+
+		p := make([]byte, 8)
+		n, err := buf.ReadAt(p, 10)
+		Expect(err).To(Equal(io.EOF))
+		Expect(p[:n]).To(Equal([]byte("\x00\x00xy")))
+	})
+
+	It("should truncate", func() {
+		buf.Write([]byte("0123456789"))
+		Expect(buf.Truncate(4)).NotTo(HaveOccurred())
+		Expect(readAll()).To(Equal([]byte("0123")))
+
+		Expect(buf.Truncate(6)).NotTo(HaveOccurred())
+		Expect(readAll()).To(Equal([]byte("0123\x00\x00")))
+	})
+
+	It("should spill large file to disk", func() {
+		buf.Write([]byte("0123456789"))
+		buf.WriteAt([]byte("xy"), maxStagingMemSize)
+		Expect(buf.file).NotTo(BeNil())
+		Expect(buf.Size()).To(BeEquivalentTo(maxStagingMemSize + 2))
+
+		b := readAll()
+		Expect(b[:10]).To(Equal([]byte("0123456789")))
+		Expect(b[maxStagingMemSize:]).To(Equal([]byte("xy")))
+		Expect(bytes.Count(b[10:maxStagingMemSize], []byte{0})).To(Equal(maxStagingMemSize - 10))
+
+		Expect(buf.Truncate(5)).NotTo(HaveOccurred())
+		Expect(readAll()).To(Equal([]byte("01234")))
+
+		fqn := buf.file.Name()
+		buf.free()
+		_, err := os.Stat(fqn)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
@@ -25,8 +25,11 @@ import (
 //
 // Cache is a flat structure that keeps all information about current files and
 // directories. It works like an oracle - it knows whether the file/directory
-// exists or not. The cache updates/refreshes once in while to keep everything
-// in sync with the AIS state.
+// exists or not. The cache keeps in sync with the AIS state by following the
+// change feed of the bucket (see api.GetBucketChanges) and applying the changes
+// incrementally. The whole namespace is relisted only when the feed reports
+// that some changes were lost, or when the feed is not available (in which
+// case the cache refreshes once in a while).
 //
 // The cache itself is built on top of N sync.Maps which we access by hash of
 // the key (name/path). The values in the maps are `cacheEntry`. When we
@@ -41,6 +44,9 @@ import (
 const (
 	entryFileTy = entryType(fuseutil.DT_File)
 	entryDirTy  = entryType(fuseutil.DT_Directory)
+
+	// Max time to wait for the changes of the bucket in a single request.
+	maxChangesWait = time.Minute
 )
 
 var (
@@ -88,6 +94,9 @@ type (
 
 		cfg *ServerConfig
 
+		// Set when the bucket is no longer mounted (eg. destroyed via rmdir).
+		stopped atomic.Bool
+
 		// Determines if the cache was able to read whole namespace into memory.
 		// In case we do have all objects in memory we can enable some of the
 		// performance improvements.
@@ -118,29 +127,105 @@ func newNsCache(bck *ais.Bucket, logger *log.Logger, cfg *ServerConfig) (*namesp
 		Type:  fuseutil.DirentType(c.root.Ty()),
 	}
 
+	if cfg.SyncInterval.Load() == 0 {
+		return c, c.refresh()
+	}
+
+	// Get the position in the change feed before listing so that no change
+	// made during the listing is missed.
+	token := c.changesToken(logger)
 	err := c.refresh()
+	if err != nil {
+		token = ""
+	}
+	go c.sync(logger, token)
 
-	if cfg.SyncInterval.Load() > 0 {
-		go func() {
-			for {
-				interval := cfg.SyncInterval.Load()
-				if interval == 0 {
-					// Someone disabled the syncing.
-					return
-				}
+	return c, err
+}
 
-				time.Sleep(interval)
-				logger.Printf("syncing with AIS...")
-				if err := c.refresh(); err != nil {
-					logger.Printf("failed to sync, err: %v", err)
-				} else {
-					logger.Printf("syncing has finished successfully")
-				}
+// sync keeps the cache in sync with AIS: it follows the change feed of the
+// bucket or, if not available, periodically refreshes the whole cache.
+func (c *namespaceCache) sync(logger *log.Logger, token string) {
+	for {
+		interval := c.cfg.SyncInterval.Load()
+		if interval == 0 || c.stopped.Load() {
+			// Someone disabled the syncing or the cache is gone.
+			return
+		}
+
+		if token == "" {
+			time.Sleep(interval)
+			if c.stopped.Load() {
+				return
 			}
-		}()
+			token = c.fullSync(logger)
+			continue
+		}
+
+		changes, err := c.bck.Changes(token, cmn.MinDur(interval, maxChangesWait))
+		if c.stopped.Load() {
+			return
+		}
+		if err != nil {
+			logger.Printf("failed to get changes, err: %v", err)
+			token = ""
+			continue
+		}
+		if changes.Reset {
+			logger.Printf("changes have been lost")
+			token = c.fullSync(logger)
+			continue
+		}
+		c.applyChanges(changes.Changes)
+		token = changes.Token
 	}
+}
 
-	return c, err
+// fullSync refreshes the whole cache and returns the position in the change
+// feed preceding the refresh (empty if the refresh or the feed failed).
+func (c *namespaceCache) fullSync(logger *log.Logger) (token string) {
+	token = c.changesToken(logger)
+	logger.Printf("syncing with AIS...")
+	if err := c.refresh(); err != nil {
+		logger.Printf("failed to sync, err: %v", err)
+		return ""
+	}
+	logger.Printf("syncing has finished successfully")
+	return token
+}
+
+func (c *namespaceCache) changesToken(logger *log.Logger) string {
+	changes, err := c.bck.Changes("", 0)
+	if err != nil {
+		logger.Printf("change feed is not available, err: %v", err)
+		return ""
+	}
+	return changes.Token
+}
+
+func (c *namespaceCache) applyChanges(changes []cmn.BucketChange) {
+	for _, change := range changes {
+		switch change.Type {
+		case cmn.ChangeCreated:
+			id := invalidInodeID
+			if exists, _, entry := c.exists(change.Name); exists && entry.Ty() == entryFileTy {
+				id = entry.ID()
+			}
+			c.add(entryFileTy, dtAttrs{
+				id:   id,
+				path: change.Name,
+				obj:  ais.NewObject(change.Name, c.bck, change.Size),
+			})
+		case cmn.ChangeDeleted:
+			c.remove(change.Name)
+		case cmn.ChangeRenamed:
+			c.rename(change.Name, change.NewName)
+		}
+	}
+}
+
+func (c *namespaceCache) stop() {
+	c.stopped.Store(true)
 }
 
 func (c *namespaceCache) newFileEntry(dta dtAttrs) cacheEntry {
@@ -265,6 +350,57 @@ func (c *namespaceCache) remove(p string) {
 	wg.Wait()
 }
 
+// rename moves the entry `oldPath` to `newPath` preserving its inode ID. In
+// case of directory all entries with prefix `oldPath` are moved as well.
+func (c *namespaceCache) rename(oldPath, newPath string) {
+	// Fast path for file
+	if !strings.HasSuffix(oldPath, separator) {
+		m := c.getCache(oldPath)
+		v, exists := m.Load(oldPath)
+		if !exists {
+			return
+		}
+		m.Delete(oldPath)
+		e := v.(cacheEntry)
+		c.add(entryFileTy, dtAttrs{id: e.ID(), path: newPath, obj: e.Object().Renamed(newPath)})
+		return
+	}
+
+	// Slow path for directory - collect and remove all entries with prefix
+	// `oldPath` and then add them with prefix `newPath`.
+	var (
+		mtx     sync.Mutex
+		entries = make(map[string]cacheEntry)
+		wg      = &sync.WaitGroup{}
+	)
+	for i := 0; i < cmn.MultiSyncMapCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			m := c.getCacheByIdx(i)
+			m.Range(func(k, v interface{}) bool {
+				name := k.(string)
+				if strings.HasPrefix(name, oldPath) {
+					mtx.Lock()
+					entries[name] = v.(cacheEntry)
+					mtx.Unlock()
+					m.Delete(k)
+				}
+				return true
+			})
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	for name, e := range entries {
+		dta := dtAttrs{id: e.ID(), path: newPath + strings.TrimPrefix(name, oldPath)}
+		if e.Ty() == entryFileTy {
+			dta.obj = e.Object().Renamed(dta.path)
+		}
+		c.add(e.Ty(), dta)
+	}
+}
+
 func (c *namespaceCache) exists(p string) (exists bool, res EntryLookupResult, entry cacheEntry) {
 	root := c.root
 	if p == "" {
@@ -8,7 +8,9 @@ import (
 	"net/http"
 
 	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fuse/ais"
+	"github.com/jacobsa/fuse/fuseops"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -28,7 +30,7 @@ var _ = Describe("Cache", func() {
 		)
 
 		BeforeEach(func() {
-			bck = ais.NewBucket("empty", api.BaseParams{
+			bck = ais.NewBucket("empty", "", api.BaseParams{
 				Client: http.DefaultClient,
 				URL:    "",
 			})
@@ -150,6 +152,89 @@ var _ = Describe("Cache", func() {
 			})
 		})
 
+		Describe("rename", func() {
+			It("should rename file in cache", func() {
+				var newPath = "a/d"
+
+				cache.add(entryFileTy, dtAttrs{
+					id:   fuseops.InodeID(10),
+					path: fpath,
+					obj:  ais.NewObject(fpath, bck, 1024),
+				})
+
+				cache.rename(fpath, newPath)
+				exists, _, _ := cache.exists(fpath)
+				Expect(exists).To(BeFalse())
+
+				exists, res, entry := cache.exists(newPath)
+				Expect(exists).To(BeTrue())
+				Expect(res.Object.Name).To(Equal(newPath))
+				Expect(res.Object.Size).To(BeEquivalentTo(1024))
+				Expect(entry.ID()).To(BeEquivalentTo(10))
+			})
+
+			It("should rename nonempty directory in cache", func() {
+				var (
+					newPath    = "d/"
+					filesPaths = []string{"d", "e/f"}
+				)
+
+				cache.add(entryDirTy, dtAttrs{
+					id:   fuseops.InodeID(10),
+					path: dpath,
+				})
+				for _, filePath := range filesPaths {
+					cache.add(entryFileTy, dtAttrs{
+						id:   invalidInodeID,
+						path: dpath + filePath,
+					})
+				}
+
+				cache.rename(dpath, newPath)
+				exists, _, _ := cache.exists(dpath)
+				Expect(exists).To(BeFalse())
+				exists, _, entry := cache.exists(newPath)
+				Expect(exists).To(BeTrue())
+				Expect(entry.ID()).To(BeEquivalentTo(10))
+
+				for _, filePath := range filesPaths {
+					exists, _, _ := cache.exists(dpath + filePath)
+					Expect(exists).To(BeFalse())
+					exists, res, _ := cache.exists(newPath + filePath)
+					Expect(exists).To(BeTrue())
+					Expect(res.Object.Name).To(Equal(newPath + filePath))
+				}
+				exists, _, _ = cache.exists(newPath + "e/")
+				Expect(exists).To(BeTrue())
+			})
+		})
+
+		Describe("applyChanges", func() {
+			It("should apply changes of the bucket", func() {
+				cache.add(entryFileTy, dtAttrs{id: 10, path: fpath, obj: ais.NewObject(fpath, bck, 1024)})
+				cache.add(entryFileTy, dtAttrs{id: 11, path: "x", obj: ais.NewObject("x", bck, 1)})
+
+				cache.applyChanges([]cmn.BucketChange{
+					{Type: cmn.ChangeCreated, Name: fpath, Size: 2048},
+					{Type: cmn.ChangeCreated, Name: "d/e", Size: 1},
+					{Type: cmn.ChangeRenamed, Name: "d/e", NewName: "d/f"},
+					{Type: cmn.ChangeDeleted, Name: "x"},
+				})
+
+				exists, res, entry := cache.exists(fpath)
+				Expect(exists).To(BeTrue())
+				Expect(entry.ID()).To(BeEquivalentTo(10))
+				Expect(res.Object.Size).To(BeEquivalentTo(2048))
+
+				exists, _, _ = cache.exists("d/e")
+				Expect(exists).To(BeFalse())
+				exists, _, _ = cache.exists("d/f")
+				Expect(exists).To(BeTrue())
+				exists, _, _ = cache.exists("x")
+				Expect(exists).To(BeFalse())
+			})
+		})
+
 		Describe("listEntries", func() {
 			It("should list no entries", func() {
 				var entries []cacheEntry
@@ -0,0 +1,129 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"errors"
+	"net/http"
+	"syscall"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fuse/ais"
+	"github.com/jacobsa/fuse"
+)
+
+// When no bucket is given, the whole cluster is mounted: the root directory
+// lists all ais buckets plus the `cloud/` directory which, in turn, lists
+// the cloud buckets. Each bucket is a directory that has its own namespace
+// cache; ais buckets are created and destroyed via mkdir and rmdir, respectively.
+
+// lookUpChild returns the inode of a bucket (or `cloud/`) in cluster directory.
+// LOCKS(parent)
+func (fs *aisfs) lookUpChild(parent *DirectoryInode, name string) (inode Inode, err error) {
+	parent.Lock()
+	defer parent.Unlock()
+
+	if id, ok := parent.LookupChild(name); ok {
+		fs.mu.RLock()
+		inode, ok = fs.inodeTable[id]
+		fs.mu.RUnlock()
+		// NOTE: the inode may be being forgotten.
+		if ok {
+			return inode, nil
+		}
+	}
+
+	if parent == fs.root && name == cloudDirName {
+		fs.mu.Lock()
+		inode = fs.createCloudDirInode(fs.nextInodeID(), parent, fs.modeBits.Directory)
+		fs.mu.Unlock()
+		parent.NewChildEntry(name, inode.ID())
+		return inode, nil
+	}
+
+	bck := fs.cluster.Bucket(name, parent.provider)
+	exists, err := bck.Exists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fuse.ENOENT
+	}
+	return fs.newBucketInode(parent, bck)
+}
+
+// REQUIRES_LOCK(parent)
+func (fs *aisfs) newBucketInode(parent *DirectoryInode, bck *ais.Bucket) (inode Inode, err error) {
+	cache, err := fs.nsCacheFor(bck)
+	if err != nil {
+		return nil, err
+	}
+	fs.mu.Lock()
+	inode = fs.createBucketInode(fs.nextInodeID(), parent, cache, fs.modeBits.Directory)
+	fs.mu.Unlock()
+	parent.NewChildEntry(bck.Name(), inode.ID())
+	return inode, nil
+}
+
+// createBucket creates an ais bucket (mkdir in the cluster root directory).
+// LOCKS(parent)
+func (fs *aisfs) createBucket(parent *DirectoryInode, name string) (inode Inode, err error) {
+	if !cmn.IsProviderAIS(parent.provider) || name == cloudDirName {
+		return nil, syscall.EPERM
+	}
+
+	parent.Lock()
+	defer parent.Unlock()
+
+	bck := fs.cluster.Bucket(name, cmn.AIS)
+	if err = bck.Create(); err != nil {
+		var (
+			ioErr   *ais.IOError
+			httpErr *cmn.HTTPError
+		)
+		if errors.As(err, &ioErr) && errors.As(ioErr.Err, &httpErr) && httpErr.Status == http.StatusConflict {
+			return nil, fuse.EEXIST
+		}
+		return nil, err
+	}
+	parent.entries = nil
+	return fs.newBucketInode(parent, bck)
+}
+
+// destroyBucket destroys an empty ais bucket (rmdir in the cluster root directory).
+// LOCKS(parent)
+func (fs *aisfs) destroyBucket(parent *DirectoryInode, name string) (err error) {
+	if !cmn.IsProviderAIS(parent.provider) || name == cloudDirName {
+		return syscall.EPERM
+	}
+
+	bck := fs.cluster.Bucket(name, cmn.AIS)
+	exists, err := bck.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fuse.ENOENT
+	}
+	objs, _, err := bck.ListObjects("", "", 1)
+	if err != nil {
+		return err
+	}
+	if len(objs) > 0 {
+		return fuse.ENOTEMPTY
+	}
+	if err = bck.Destroy(); err != nil {
+		return err
+	}
+
+	fs.dropNsCache(bck)
+	parent.Lock()
+	if id, ok := parent.LookupChild(name); ok {
+		parent.InvalidateInode(name, id, true /*isDir*/)
+	}
+	parent.entries = nil
+	parent.Unlock()
+	return nil
+}
@@ -36,10 +36,13 @@ const (
 
 	rootPath       = ""
 	invalidInodeID = fuseops.InodeID(fuseops.RootInodeID + 1)
+
+	// Name of the directory containing cloud buckets when the whole cluster is mounted.
+	cloudDirName = "cloud"
 )
 
 var (
-	nsCache *namespaceCache // Namespace cache for files and directories.
+	blkCache *blockCache // On-disk cache of file blocks, nil if disabled.
 
 	glMem2 *memsys.Mem2 // Global memory manager
 )
@@ -54,8 +57,9 @@ type (
 		MountPath string
 
 		// Cluster
-		AISURL     string
-		BucketName string
+		AISURL         string
+		BucketName     string // empty if the whole cluster is mounted
+		BucketProvider string // provider of the bucket, determined when mounting
 
 		// Access
 		Owner *Owner
@@ -66,6 +70,11 @@ type (
 		SyncInterval    atomic.Duration
 		MemoryLimit     atomic.Uint64
 		MaxWriteBufSize atomic.Int64
+		SpillDir        string
+
+		// Block cache (disabled if directory is not set)
+		BlockCacheDir   string
+		BlockCacheLimit atomic.Int64
 	}
 
 	// File system implementation.
@@ -88,6 +97,11 @@ type (
 		inodeTable  map[fuseops.InodeID]Inode
 		lastInodeID atomic.Uint64
 
+		// Cluster and namespace caches of its (mounted) buckets
+		cluster    *ais.Cluster
+		nsCaches   map[string]*namespaceCache // provider/bucket => namespace cache
+		nsCachesMu sync.Mutex
+
 		// Handles
 		fileHandles  map[fuseops.HandleID]*fileHandle
 		lastHandleID atomic.Uint64
@@ -126,6 +140,9 @@ func NewAISFileSystemServer(cfg *ServerConfig, errLog *log.Logger) (srv fuse.Ser
 		inodeTable:  make(map[fuseops.InodeID]Inode),
 		lastInodeID: *atomic.NewUint64(uint64(invalidInodeID)),
 
+		// Cluster
+		nsCaches: make(map[string]*namespaceCache),
+
 		// Handles
 		fileHandles:  make(map[fuseops.HandleID]*fileHandle),
 		lastHandleID: *atomic.NewUint64(0),
@@ -140,24 +157,37 @@ func NewAISFileSystemServer(cfg *ServerConfig, errLog *log.Logger) (srv fuse.Ser
 		errLog: errLog,
 	}
 
-	// Create a bucket.
-	apiParams := aisfs.aisAPIParams()
-	bucket := ais.NewBucket(cfg.BucketName, apiParams)
+	aisfs.cluster = ais.NewCluster(aisfs.aisAPIParams())
 
-	// Create the root inode.
-	aisfs.root = NewDirectoryInode(
-		fuseops.RootInodeID,
-		aisfs.dirAttrs(aisfs.modeBits.Directory),
-		rootPath,
-		nil, /* parent */
-		bucket).(*DirectoryInode)
+	// Create the root inode: either the root directory of the bucket or,
+	// if no bucket is given, the cluster directory listing all ais buckets.
+	if cfg.BucketName != "" {
+		cache, err := aisfs.nsCacheFor(aisfs.cluster.Bucket(cfg.BucketName, cfg.BucketProvider))
+		if err != nil {
+			return nil, err
+		}
+		aisfs.root = NewDirectoryInode(
+			fuseops.RootInodeID,
+			aisfs.dirAttrs(aisfs.modeBits.Directory),
+			rootPath,
+			nil, /* parent */
+			cache).(*DirectoryInode)
+	} else {
+		aisfs.root = NewClusterDirectoryInode(
+			fuseops.RootInodeID,
+			aisfs.dirAttrs(aisfs.modeBits.Directory),
+			nil, /* parent */
+			aisfs.cluster,
+			cmn.AIS).(*DirectoryInode)
+	}
 
 	aisfs.root.IncLookupCount()
 	aisfs.inodeTable[fuseops.RootInodeID] = aisfs.root
 
-	nsCache, err = newNsCache(bucket, aisfs.errLog, aisfs.cfg)
-	if err != nil {
-		return nil, err
+	if cfg.BlockCacheDir != "" {
+		if blkCache, err = newBlockCache(cfg); err != nil {
+			return nil, err
+		}
 	}
 	return fuseutil.NewFileSystemServer(aisfs), nil
 }
@@ -209,12 +239,13 @@ func (fs *aisfs) dirAttrs(mode os.FileMode) fuseops.InodeAttributes {
 	}
 }
 
-// REQUIRES_LOCK(fs.mu), READ_LOCKS(file)
+// REQUIRES_LOCK(fs.mu), LOCKS(file)
 func (fs *aisfs) allocateFileHandle(file *FileInode) fuseops.HandleID {
 	id := fs.nextHandleID()
-	file.RLock()
-	fs.fileHandles[id] = newFileHandle(id, file)
-	file.RUnlock()
+	file.Lock()
+	fs.fileHandles[id] = newFileHandle(id, file, fs.cfg.SpillDir)
+	file.openHandle()
+	file.Unlock()
 	return id
 }
 
@@ -268,12 +299,59 @@ func (fs *aisfs) createFileInode(inodeID fuseops.InodeID, parent *DirectoryInode
 func (fs *aisfs) createDirectoryInode(inodeID fuseops.InodeID, parent *DirectoryInode, entryName string, mode os.FileMode) Inode {
 	attrs := fs.dirAttrs(mode)
 	fspath := path.Join(parent.Path(), entryName) + separator
-	bucket := ais.NewBucket(fs.cfg.BucketName, fs.aisAPIParams())
-	inode := NewDirectoryInode(inodeID, attrs, fspath, parent, bucket)
+	inode := NewDirectoryInode(inodeID, attrs, fspath, parent, parent.cache)
 	fs.inodeTable[inodeID] = inode
 	return inode
 }
 
+// createBucketInode creates the root directory of the bucket in cluster directory.
+// REQUIRES_LOCK(fs.mu)
+func (fs *aisfs) createBucketInode(inodeID fuseops.InodeID, parent *DirectoryInode, cache *namespaceCache, mode os.FileMode) Inode {
+	attrs := fs.dirAttrs(mode)
+	inode := NewDirectoryInode(inodeID, attrs, rootPath, parent, cache)
+	inode.(*DirectoryInode).name = cache.bck.Name()
+	fs.inodeTable[inodeID] = inode
+	return inode
+}
+
+// createCloudDirInode creates the directory listing cloud buckets.
+// REQUIRES_LOCK(fs.mu)
+func (fs *aisfs) createCloudDirInode(inodeID fuseops.InodeID, parent *DirectoryInode, mode os.FileMode) Inode {
+	attrs := fs.dirAttrs(mode)
+	inode := NewClusterDirectoryInode(inodeID, attrs, parent, fs.cluster, cmn.Cloud)
+	inode.(*DirectoryInode).name = cloudDirName
+	fs.inodeTable[inodeID] = inode
+	return inode
+}
+
+// nsCacheFor returns the namespace cache of the bucket, creating (and
+// populating) it if needed.
+// LOCKS(fs.nsCachesMu)
+func (fs *aisfs) nsCacheFor(bck *ais.Bucket) (cache *namespaceCache, err error) {
+	key := bck.Provider() + separator + bck.Name()
+	fs.nsCachesMu.Lock()
+	defer fs.nsCachesMu.Unlock()
+	if cache, ok := fs.nsCaches[key]; ok {
+		return cache, nil
+	}
+	if cache, err = newNsCache(bck, fs.errLog, fs.cfg); err != nil {
+		return nil, err
+	}
+	fs.nsCaches[key] = cache
+	return cache, nil
+}
+
+// LOCKS(fs.nsCachesMu)
+func (fs *aisfs) dropNsCache(bck *ais.Bucket) {
+	key := bck.Provider() + separator + bck.Name()
+	fs.nsCachesMu.Lock()
+	if cache, ok := fs.nsCaches[key]; ok {
+		cache.stop()
+		delete(fs.nsCaches, key)
+	}
+	fs.nsCachesMu.Unlock()
+}
+
 ////////////////////////////////
 // FileSystem interface methods
 ////////////////////////////////
@@ -294,6 +372,12 @@ func (fs *aisfs) SetInodeAttributes(ctx context.Context, req *fuseops.SetInodeAt
 	inode := fs.lookupMustExist(req.Inode)
 	fs.mu.RUnlock()
 
+	if req.Size != nil && !inode.IsDir() {
+		if err = fs.truncateFile(inode.(*FileInode), *req.Size); err != nil {
+			return fs.handleIOError(err)
+		}
+	}
+
 	inode.Lock()
 	updReq := &AttrUpdateReq{
 		Mode:  req.Mode,
@@ -306,6 +390,59 @@ func (fs *aisfs) SetInodeAttributes(ctx context.Context, req *fuseops.SetInodeAt
 	return
 }
 
+// truncateFile truncates the file - the truncation applies to all its open
+// handles (the operation does not tell which one, if any, it comes from).
+// Pending appends of the handles are completed (or discarded, when truncating
+// to zero) and the truncated content is staged on the file inode until the
+// next flush of any handle. With no open handles the truncated file is
+// uploaded right away.
+// LOCKS(fs.mu, fh.mu, file)
+func (fs *aisfs) truncateFile(file *FileInode, size uint64) (err error) {
+	var handles []*fileHandle
+
+	file.RLock()
+	unchanged := file.staging == nil && !file.pending && file.Size() == size
+	file.RUnlock()
+
+	fs.mu.RLock()
+	for _, handle := range fs.fileHandles {
+		if handle.file == file {
+			handles = append(handles, handle)
+		}
+	}
+	fs.mu.RUnlock()
+
+	for _, fh := range handles {
+		fh.mu.Lock()
+		if size == 0 {
+			fh.discardAppends()
+		} else if fh.dirty {
+			err = fh.flushAppends()
+			unchanged = false
+		}
+		fh.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	if unchanged {
+		return nil
+	}
+
+	file.Lock()
+	defer file.Unlock()
+	if err = file.truncate(size, fs.cfg.SpillDir); err != nil {
+		return err
+	}
+	if len(handles) == 0 {
+		err = file.upload()
+		if file.handles == 0 {
+			file.dropStaging()
+		}
+	}
+	return
+}
+
 func (fs *aisfs) LookUpInode(ctx context.Context, req *fuseops.LookUpInodeOp) (err error) {
 	var inode Inode
 
@@ -313,6 +450,17 @@ func (fs *aisfs) LookUpInode(ctx context.Context, req *fuseops.LookUpInodeOp) (e
 	parent := fs.lookupDirMustExist(req.Parent)
 	fs.mu.RUnlock()
 
+	if parent.IsClusterDir() {
+		if inode, err = fs.lookUpChild(parent, req.Name); err != nil {
+			return fs.handleIOError(err)
+		}
+		inode.RLock()
+		req.Entry = inode.AsChildEntry()
+		inode.RUnlock()
+		inode.IncLookupCount()
+		return
+	}
+
 	result := parent.LookupEntry(req.Name)
 	if result.NoEntry() {
 		return fuse.ENOENT
@@ -365,8 +513,9 @@ func (fs *aisfs) ForgetInode(ctx context.Context, req *fuseops.ForgetInodeOp) (e
 		// The kernel will never use this inode again, we can destroy it.
 
 		// Acquire locks in the correct order.
-		parent := inode.Parent().(*DirectoryInode)
 		inode.Lock()
+		// NOTE: parent could have changed by rename.
+		parent := inode.Parent().(*DirectoryInode)
 
 		fs.mu.Lock()
 		// Remove it from the inode table.
@@ -375,8 +524,11 @@ func (fs *aisfs) ForgetInode(ctx context.Context, req *fuseops.ForgetInodeOp) (e
 
 		// Remove entryName to inode ID mapping in parent.
 		name := path.Base(inode.Path())
+		if dir, ok := inode.(*DirectoryInode); ok {
+			name = dir.EntryName()
+		}
 		parent.Lock()
-		parent.InvalidateInode(name, inode.IsDir())
+		parent.InvalidateInode(name, req.Inode, inode.IsDir())
 		parent.Unlock()
 
 		// Any future cleanup related to inode goes here.
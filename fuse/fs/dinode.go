@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -20,20 +21,44 @@ import (
 // Ensure interface satisfaction.
 var _ Inode = &DirectoryInode{}
 
+// DirectoryInode is either a directory within a bucket (including the root
+// directory of the bucket) or a cluster directory - the root of the whole
+// cluster mount or its `cloud/` subdirectory - whose entries are buckets.
 type DirectoryInode struct {
 	baseInode
 
 	parent *DirectoryInode
 	bucket *ais.Bucket
+	cache  *namespaceCache
 
 	entries []fuseutil.Dirent
+
+	// Cluster directory only
+	cluster  *ais.Cluster
+	provider string                     // provider of the buckets listed in the directory
+	children map[string]fuseops.InodeID // bucket name (or `cloud`) => inode ID
+
+	// Entry name in the parent cluster directory, set for the root directories
+	// of buckets and for `cloud/` (their paths are empty).
+	name string
 }
 
-func NewDirectoryInode(id fuseops.InodeID, attrs fuseops.InodeAttributes, path string, parent *DirectoryInode, bucket *ais.Bucket) Inode {
+func NewDirectoryInode(id fuseops.InodeID, attrs fuseops.InodeAttributes, path string, parent *DirectoryInode, cache *namespaceCache) Inode {
 	return &DirectoryInode{
 		baseInode: newBaseInode(id, attrs, path),
 		parent:    parent,
-		bucket:    bucket,
+		bucket:    cache.bck,
+		cache:     cache,
+	}
+}
+
+func NewClusterDirectoryInode(id fuseops.InodeID, attrs fuseops.InodeAttributes, parent *DirectoryInode, cluster *ais.Cluster, provider string) Inode {
+	return &DirectoryInode{
+		baseInode: newBaseInode(id, attrs, rootPath),
+		parent:    parent,
+		cluster:   cluster,
+		provider:  provider,
+		children:  make(map[string]fuseops.InodeID),
 	}
 }
 
@@ -41,10 +66,28 @@ func (dir *DirectoryInode) Parent() Inode {
 	return dir.parent
 }
 
+// REQUIRES_LOCK(dir)
+func (dir *DirectoryInode) SetParent(parent *DirectoryInode) {
+	dir.parent = parent
+}
+
 func (dir *DirectoryInode) IsDir() bool {
 	return true
 }
 
+// IsClusterDir returns true if the entries of the directory are buckets.
+func (dir *DirectoryInode) IsClusterDir() bool {
+	return dir.cluster != nil
+}
+
+// EntryName returns the name of the directory in its parent directory.
+func (dir *DirectoryInode) EntryName() string {
+	if dir.name != "" {
+		return dir.name
+	}
+	return path.Base(dir.Path())
+}
+
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) UpdateAttributes(req *AttrUpdateReq) fuseops.InodeAttributes {
 	attrs := dir.Attributes()
@@ -59,7 +102,7 @@ func (dir *DirectoryInode) UpdateAttributes(req *AttrUpdateReq) fuseops.InodeAtt
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) NewFileEntry(entryName string, id fuseops.InodeID, object *ais.Object) {
 	entryName = path.Join(dir.Path(), entryName)
-	nsCache.add(entryFileTy, dtAttrs{id: id, path: entryName, obj: object})
+	dir.cache.add(entryFileTy, dtAttrs{id: id, path: entryName, obj: object})
 
 	// TODO: improve caching entries for `ReadEntries`
 	dir.entries = nil
@@ -68,7 +111,7 @@ func (dir *DirectoryInode) NewFileEntry(entryName string, id fuseops.InodeID, ob
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) ForgetFile(entryName string) {
 	entryName = path.Join(dir.Path(), entryName)
-	nsCache.remove(entryName)
+	dir.cache.remove(entryName)
 
 	// TODO: improve caching entries for `ReadEntries`
 	dir.entries = nil
@@ -77,7 +120,7 @@ func (dir *DirectoryInode) ForgetFile(entryName string) {
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) NewDirEntry(entryName string, id fuseops.InodeID) {
 	entryName = path.Join(dir.Path(), entryName) + separator
-	nsCache.add(entryDirTy, dtAttrs{id: id, path: entryName})
+	dir.cache.add(entryDirTy, dtAttrs{id: id, path: entryName})
 
 	// TODO: improve caching entries for `ReadEntries`
 	dir.entries = nil
@@ -86,24 +129,32 @@ func (dir *DirectoryInode) NewDirEntry(entryName string, id fuseops.InodeID) {
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) ForgetDir(entryName string) {
 	entryName = path.Join(dir.Path(), entryName) + separator
-	nsCache.remove(entryName)
+	dir.cache.remove(entryName)
 
 	// TODO: improve caching entries for `ReadEntries`
 	dir.entries = nil
 }
 
-func (dir *DirectoryInode) InvalidateInode(entryName string, isDir bool) {
+func (dir *DirectoryInode) InvalidateInode(entryName string, id fuseops.InodeID, isDir bool) {
+	if dir.IsClusterDir() {
+		if dir.children[entryName] == id {
+			delete(dir.children, entryName)
+		}
+		return
+	}
+
 	entryName = path.Join(dir.Path(), entryName)
 	ty := entryFileTy
 	if isDir {
 		entryName += separator
 		ty = entryDirTy
 	}
-	exists, _, _ := nsCache.exists(entryName)
-	if !exists {
+	exists, _, entry := dir.cache.exists(entryName)
+	// The entry could have been replaced by another one (eg. by rename).
+	if !exists || entry.ID() != id {
 		return
 	}
-	nsCache.add(ty, dtAttrs{id: invalidInodeID, path: entryName})
+	dir.cache.add(ty, dtAttrs{id: invalidInodeID, path: entryName})
 }
 
 func (dir *DirectoryInode) LinkNewFile(fileName string) (*ais.Object, error) {
@@ -117,8 +168,12 @@ func (dir *DirectoryInode) LinkNewFile(fileName string) (*ais.Object, error) {
 
 // REQUIRES_LOCK(dir)
 func (dir *DirectoryInode) ReadEntries() (entries []fuseutil.Dirent, err error) {
+	if dir.IsClusterDir() {
+		return dir.readBuckets()
+	}
+
 	// Traverse files and subdirectories of dir read from the bucket.
-	exists, _, _ := nsCache.exists(dir.Path())
+	exists, _, _ := dir.cache.exists(dir.Path())
 	if !exists {
 		return nil, fuse.ENOENT
 	}
@@ -128,7 +183,7 @@ func (dir *DirectoryInode) ReadEntries() (entries []fuseutil.Dirent, err error)
 	}
 
 	var offset fuseops.DirOffset = 1
-	nsCache.listEntries(dir.Path(), func(child cacheEntry) {
+	dir.cache.listEntries(dir.Path(), func(child cacheEntry) {
 		dir.entries = append(dir.entries, fuseutil.Dirent{
 			Inode:  child.ID(),
 			Offset: offset,
@@ -140,6 +195,44 @@ func (dir *DirectoryInode) ReadEntries() (entries []fuseutil.Dirent, err error)
 	return dir.entries, nil
 }
 
+// readBuckets lists the buckets - the entries of cluster directory.
+// REQUIRES_LOCK(dir)
+func (dir *DirectoryInode) readBuckets() (entries []fuseutil.Dirent, err error) {
+	names, err := dir.cluster.ListBuckets(dir.provider)
+	if err != nil {
+		return nil, err
+	}
+	if dir.parent == nil && cmn.IsProviderAIS(dir.provider) {
+		names = append(names, cloudDirName)
+	}
+
+	dir.entries = dir.entries[:0]
+	for i, name := range names {
+		id, ok := dir.children[name]
+		if !ok {
+			id = invalidInodeID
+		}
+		dir.entries = append(dir.entries, fuseutil.Dirent{
+			Inode:  id,
+			Offset: fuseops.DirOffset(i + 1),
+			Name:   name,
+			Type:   fuseutil.DT_Directory,
+		})
+	}
+	return dir.entries, nil
+}
+
+// REQUIRES_LOCK(dir)
+func (dir *DirectoryInode) NewChildEntry(entryName string, id fuseops.InodeID) {
+	dir.children[entryName] = id
+}
+
+// REQUIRES_LOCK(dir)
+func (dir *DirectoryInode) LookupChild(entryName string) (id fuseops.InodeID, ok bool) {
+	id, ok = dir.children[entryName]
+	return
+}
+
 // LOCKS(dir)
 func (dir *DirectoryInode) UnlinkEntry(entryName string) error {
 	objName := path.Join(dir.Path(), entryName)
@@ -153,6 +246,78 @@ func (dir *DirectoryInode) UnlinkEntry(entryName string) error {
 	return nil
 }
 
+// LOCKS(dir, newDir)
+func (dir *DirectoryInode) RenameFile(entryName string, newDir *DirectoryInode, newEntryName string) error {
+	var (
+		oldName = path.Join(dir.Path(), entryName)
+		newName = path.Join(newDir.Path(), newEntryName)
+	)
+	if err := dir.bucket.RenameObject(oldName, newName); err != nil {
+		return err
+	}
+
+	lockDirs(dir, newDir)
+	dir.cache.rename(oldName, newName)
+	dir.entries, newDir.entries = nil, nil
+	unlockDirs(dir, newDir)
+	return nil
+}
+
+// RenameDir renames all objects with the directory prefix - the operation is not
+// atomic: in case of error some of the objects may already be renamed.
+// LOCKS(dir, newDir)
+func (dir *DirectoryInode) RenameDir(entryName string, newDir *DirectoryInode, newEntryName string) (err error) {
+	var (
+		objs       []*ais.Object
+		pageMarker string
+		oldPrefix  = path.Join(dir.Path(), entryName) + separator
+		newPrefix  = path.Join(newDir.Path(), newEntryName) + separator
+	)
+	defer func() {
+		lockDirs(dir, newDir)
+		if err == nil {
+			dir.cache.rename(oldPrefix, newPrefix)
+		}
+		dir.entries, newDir.entries = nil, nil
+		unlockDirs(dir, newDir)
+	}()
+	for {
+		if objs, pageMarker, err = dir.bucket.ListObjects(oldPrefix, pageMarker, 0); err != nil {
+			return
+		}
+		for _, obj := range objs {
+			newName := newPrefix + strings.TrimPrefix(obj.Name, oldPrefix)
+			if err = dir.bucket.RenameObject(obj.Name, newName); err != nil {
+				return
+			}
+			dir.cache.rename(obj.Name, newName)
+		}
+		if pageMarker == "" {
+			return
+		}
+	}
+}
+
+// lockDirs locks (distinct) directories in the ascending order of their IDs.
+func lockDirs(dir, other *DirectoryInode) {
+	if dir == other {
+		dir.Lock()
+		return
+	}
+	if dir.ID() > other.ID() {
+		dir, other = other, dir
+	}
+	dir.Lock()
+	other.Lock()
+}
+
+func unlockDirs(dir, other *DirectoryInode) {
+	dir.Unlock()
+	if dir != other {
+		other.Unlock()
+	}
+}
+
 func (dir *DirectoryInode) LookupEntry(entryName string) (res EntryLookupResult) {
 	var (
 		exists       bool
@@ -161,11 +326,11 @@ func (dir *DirectoryInode) LookupEntry(entryName string) (res EntryLookupResult)
 	)
 
 	// First check for directories
-	exists, res, _ = nsCache.exists(dirEntryName)
+	exists, res, _ = dir.cache.exists(dirEntryName)
 	if exists {
 		return res
 	}
 
-	_, res, _ = nsCache.exists(objEntryName)
+	_, res, _ = dir.cache.exists(objEntryName)
 	return res
 }
@@ -6,7 +6,12 @@ package fs
 
 import (
 	"context"
+	"path"
+	"strings"
+	"syscall"
 
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fuse/ais"
 	"github.com/jacobsa/fuse"
 	"github.com/jacobsa/fuse/fuseops"
 	"github.com/jacobsa/fuse/fuseutil"
@@ -54,6 +59,17 @@ func (fs *aisfs) MkDir(ctx context.Context, req *fuseops.MkDirOp) (err error) {
 	parent := fs.lookupDirMustExist(req.Parent)
 	fs.mu.RUnlock()
 
+	if parent.IsClusterDir() {
+		if newDir, err = fs.createBucket(parent, req.Name); err != nil {
+			return fs.handleIOError(err)
+		}
+		newDir.RLock()
+		req.Entry = newDir.AsChildEntry()
+		newDir.RUnlock()
+		newDir.IncLookupCount()
+		return
+	}
+
 	result := parent.LookupEntry(req.Name)
 	// If parent directory already contains an entry with req.Name
 	// it is not possible to create a new directory with the same name.
@@ -85,6 +101,13 @@ func (fs *aisfs) RmDir(ctx context.Context, req *fuseops.RmDirOp) (err error) {
 	parent := fs.lookupDirMustExist(req.Parent)
 	fs.mu.RUnlock()
 
+	if parent.IsClusterDir() {
+		if err = fs.destroyBucket(parent, req.Name); err != nil {
+			return fs.handleIOError(err)
+		}
+		return
+	}
+
 	result := parent.LookupEntry(req.Name)
 	if result.NoEntry() {
 		return fuse.ENOENT
@@ -113,3 +136,112 @@ func (fs *aisfs) RmDir(ctx context.Context, req *fuseops.RmDirOp) (err error) {
 	parent.ForgetDir(req.Name)
 	return
 }
+
+func (fs *aisfs) Rename(ctx context.Context, req *fuseops.RenameOp) (err error) {
+	fs.mu.RLock()
+	oldParent := fs.lookupDirMustExist(req.OldParent)
+	newParent := fs.lookupDirMustExist(req.NewParent)
+	fs.mu.RUnlock()
+
+	// Buckets cannot be renamed, neither objects can be moved between buckets.
+	if oldParent.IsClusterDir() || newParent.IsClusterDir() {
+		return syscall.EPERM
+	}
+	if oldParent.bucket != newParent.bucket {
+		return syscall.EXDEV
+	}
+	// Only objects in ais buckets can be renamed.
+	if !cmn.IsProviderAIS(oldParent.bucket.Provider()) {
+		return syscall.ENOTSUP
+	}
+
+	src := oldParent.LookupEntry(req.OldName)
+	if src.NoEntry() {
+		return fuse.ENOENT
+	}
+
+	var (
+		oldPath = path.Join(oldParent.Path(), req.OldName)
+		newPath = path.Join(newParent.Path(), req.NewName)
+	)
+	if oldPath == newPath {
+		return
+	}
+	if src.IsDir() {
+		oldPath += separator
+		newPath += separator
+		// It is not possible to move directory into its own subdirectory.
+		if strings.HasPrefix(newPath, oldPath) {
+			return syscall.EINVAL
+		}
+	}
+
+	dst := newParent.LookupEntry(req.NewName)
+	if !dst.NoEntry() {
+		if src.IsDir() && !dst.IsDir() {
+			return fuse.ENOTDIR
+		}
+		if !src.IsDir() && dst.IsDir() {
+			return syscall.EISDIR
+		}
+		if dst.IsDir() {
+			empty := true
+			newParent.cache.listEntries(newPath, func(cacheEntry) { empty = false })
+			if !empty {
+				return fuse.ENOTEMPTY
+			}
+			newParent.Lock()
+			newParent.ForgetDir(req.NewName)
+			newParent.Unlock()
+		}
+	}
+
+	if src.IsDir() {
+		err = oldParent.RenameDir(req.OldName, newParent, req.NewName)
+	} else {
+		err = oldParent.RenameFile(req.OldName, newParent, req.NewName)
+	}
+	if err != nil {
+		return fs.handleIOError(err)
+	}
+	fs.renameInodes(src.Entry.Inode, newParent.bucket, oldPath, newPath, newParent)
+	return
+}
+
+// renameInodes updates paths (and parent) of the inodes affected by rename:
+// the renamed inode itself and, in case of directory, all its descendants.
+func (fs *aisfs) renameInodes(id fuseops.InodeID, bck *ais.Bucket, oldPath, newPath string, newParent *DirectoryInode) {
+	var descendants []Inode
+
+	fs.mu.RLock()
+	inode, ok := fs.inodeTable[id]
+	if strings.HasSuffix(oldPath, separator) {
+		for _, in := range fs.inodeTable {
+			if in.ID() != id && inodeBucket(in) == bck && strings.HasPrefix(in.Path(), oldPath) {
+				descendants = append(descendants, in)
+			}
+		}
+	}
+	fs.mu.RUnlock()
+
+	if ok {
+		inode.Lock()
+		inode.SetParent(newParent)
+		inode.SetPath(newPath)
+		inode.Unlock()
+	}
+	for _, in := range descendants {
+		in.Lock()
+		if strings.HasPrefix(in.Path(), oldPath) {
+			in.SetPath(newPath + strings.TrimPrefix(in.Path(), oldPath))
+		}
+		in.Unlock()
+	}
+}
+
+func inodeBucket(inode Inode) *ais.Bucket {
+	if dir, ok := inode.(*DirectoryInode); ok {
+		return dir.bucket
+	}
+	return inode.(*FileInode).parent.bucket
+}
@@ -5,7 +5,6 @@
 package fs
 
 import (
-	"fmt"
 	"io"
 	"sync"
 
@@ -26,20 +25,26 @@ type fileHandle struct {
 
 	// Reading
 	readBuffer *blockBuffer
+	objProps   *cmn.ObjectProps // version and checksum to validate cached blocks
 
-	// Writing
+	// Writing (appending)
 	writeBuffer  *writeBuffer
 	dirty        bool
 	wsize        uint64
 	appendHandle string
+
+	// Random writes and truncation are staged on the file inode, shared by
+	// all its handles (see FileInode.stage)
+	spillDir string
 }
 
 // REQUIRES_READ_LOCK(file)
-func newFileHandle(id fuseops.HandleID, file *FileInode) *fileHandle {
+func newFileHandle(id fuseops.HandleID, file *FileInode, spillDir string) *fileHandle {
 	return &fileHandle{
 		id:       id,
 		file:     file,
 		fileSize: int64(file.Size()),
+		spillDir: spillDir,
 	}
 }
 
@@ -76,16 +81,45 @@ func (fh *fileHandle) ensureReadBuffer() int64 {
 	return blockSize
 }
 
-// LOCKS(fh.mu)
-func (fh *fileHandle) readChunk(dst []byte, offset int64) (n int, err error) {
-	if offset >= fh.fileSize {
-		return 0, io.EOF
+// loadBlock loads the block from the block cache, if enabled, or from the cluster.
+// REQUIRES_LOCK(fh.mu)
+func (fh *fileHandle) loadBlock(w io.Writer, offset int64, length int64) (n int64, err error) {
+	if blkCache == nil {
+		return fh.file.Load(w, offset, length)
+	}
+	if fh.objProps == nil {
+		if fh.objProps, err = fh.file.Head(); err != nil {
+			return 0, err
+		}
 	}
+	// Without version and checksum there is nothing to validate cached blocks against.
+	if fh.objProps.Version == "" && fh.objProps.Checksum == "" {
+		return fh.file.Load(w, offset, length)
+	}
+	key := blkCache.key(&fh.file.object, fh.objProps.Version, fh.objProps.Checksum, offset, length)
+	return blkCache.Load(key, w, func(w io.Writer) (int64, error) {
+		return fh.file.Load(w, offset, length)
+	})
+}
 
+// LOCKS(fh.mu, fh.file)
+func (fh *fileHandle) readChunk(dst []byte, offset int64) (n int, err error) {
 	// Lock the handler in order to read
 	fh.mu.Lock()
 	defer fh.mu.Unlock()
 
+	// Staged file is read back as it has been written so far.
+	fh.file.RLock()
+	if fh.file.staging != nil {
+		n, err = fh.file.staging.ReadAt(dst, offset)
+		fh.file.RUnlock()
+		return
+	}
+	fh.file.RUnlock()
+	if offset >= fh.fileSize {
+		return 0, io.EOF
+	}
+
 	// Ensure that buffer is ready for reading
 	blockSize := fh.ensureReadBuffer()
 	dstLen := len(dst)
@@ -94,7 +128,7 @@ func (fh *fileHandle) readChunk(dst []byte, offset int64) (n int, err error) {
 		blockNo := offset / blockSize
 		blockOffset := offset % blockSize
 
-		err = fh.readBuffer.EnsureBlock(blockNo, fh.file.Load)
+		err = fh.readBuffer.EnsureBlock(blockNo, fh.loadBlock)
 		if err != nil {
 			// In case of error is encountered while loading a block,
 			// return the number of bytes read so far.
@@ -139,6 +173,7 @@ func (fh *fileHandle) readChunk(dst []byte, offset int64) (n int, err error) {
 // WRITING //
 /////////////
 
+// REQUIRES_LOCK(fh.mu)
 func (fh *fileHandle) _writeChunk(data []byte, maxWriteBufSize int64, force bool) (err error) {
 	if fh.writeBuffer == nil {
 		fh.writeBuffer = newWriteBuffer()
@@ -165,46 +200,127 @@ func (fh *fileHandle) _writeChunk(data []byte, maxWriteBufSize int64, force bool
 	return nil
 }
 
+// Sequential writes to an empty (new or truncated) file are appended to the
+// object as they come. Any other write - at an arbitrary offset or over
+// the existing content - stages the entire file locally (see stagingBuffer)
+// on the file inode, so that all its handles see the same content, and the
+// file is uploaded as a whole on flush.
+// LOCKS(fh.mu, fh.file)
 func (fh *fileHandle) writeChunk(data []byte, offset uint64, maxWriteBufSize int64) (err error) {
 	fh.mu.Lock()
 	defer fh.mu.Unlock()
 
-	if offset != fh.wsize {
-		return fmt.Errorf("write file (inode %d): random access write not yet implemented", fh.file.ID())
+	fh.file.RLock()
+	staged := fh.file.staging != nil
+	fh.file.RUnlock()
+	if !staged && offset == fh.wsize && (fh.wsize > 0 || fh.fileSize == 0) {
+		return fh._writeChunk(data, maxWriteBufSize, false /*force*/)
+	}
+
+	// Complete pending appends, if any, so that the object contains all
+	// the data written so far.
+	if fh.dirty {
+		if err = fh.flushAppends(); err != nil {
+			return err
+		}
+	}
+	fh.file.Lock()
+	defer fh.file.Unlock()
+	if err = fh.file.stage(fh.spillDir); err != nil {
+		return err
+	}
+	if _, err = fh.file.staging.WriteAt(data, int64(offset)); err != nil {
+		return err
 	}
+	fh.file.pending = true
+	return nil
+}
 
-	return fh._writeChunk(data, maxWriteBufSize, false /*force*/)
+// discardAppends starts over the sequential writes of the handle as the file
+// has been truncated to zero.
+// REQUIRES_LOCK(fh.mu)
+func (fh *fileHandle) discardAppends() {
+	if fh.writeBuffer != nil {
+		fh.writeBuffer.free()
+		fh.writeBuffer = nil
+	}
+	fh.dirty = false
+	fh.wsize = 0
+	fh.appendHandle = ""
+	fh.fileSize = 0
+	fh.invalidateReadBuffer()
 }
 
 /////////////////////////
 // READING AND WRITING //
 /////////////////////////
 
+// LOCKS(fh.mu, fh.file)
 func (fh *fileHandle) flush() (err error) {
 	fh.mu.Lock()
 	defer fh.mu.Unlock()
+	return fh._flush()
+}
+
+// _flush completes the appends of the handle and uploads the file staged on
+// the inode (by any of its handles), if any.
+// REQUIRES_LOCK(fh.mu), LOCKS(fh.file)
+func (fh *fileHandle) _flush() (err error) {
+	if fh.dirty {
+		if err = fh.flushAppends(); err != nil {
+			return err
+		}
+	}
 
-	if !fh.dirty {
+	fh.file.Lock()
+	defer fh.file.Unlock()
+	if !fh.file.pending {
 		return nil
 	}
+	if err = fh.file.upload(); err != nil {
+		return err
+	}
+	fh.fileSize = int64(fh.file.Size())
+	fh.invalidateReadBuffer()
+	return nil
+}
 
+// REQUIRES_LOCK(fh.mu), LOCKS(fh.file)
+func (fh *fileHandle) flushAppends() (err error) {
 	if fh.writeBuffer != nil && fh.writeBuffer.size() > 0 {
 		err = fh._writeChunk(nil, 0, true /*force*/)
 	}
 
-	if err == nil {
-		err = fh.file.Flush(fh.appendHandle)
-		if err == nil {
-			fh.file.Lock()
+	if err == nil && fh.appendHandle != "" {
+		fh.file.Lock()
+		if err = fh.file.Flush(fh.appendHandle); err == nil {
 			fh.file.SetSize(fh.wsize)
-			fh.file.Unlock()
+			// The appended object replaces the truncated one.
+			if fh.file.staging == nil {
+				fh.file.pending = false
+			}
+		}
+		fh.file.Unlock()
+		if err == nil {
+			fh.fileSize = int64(fh.wsize)
+			fh.invalidateReadBuffer()
 		}
 	}
 
 	fh.dirty = false
 	fh.wsize = 0
 	fh.appendHandle = ""
-	fh.writeBuffer.free()
-	fh.writeBuffer = nil
+	if fh.writeBuffer != nil {
+		fh.writeBuffer.free()
+		fh.writeBuffer = nil
+	}
 	return
 }
+
+// REQUIRES_LOCK(fh.mu)
+func (fh *fileHandle) invalidateReadBuffer() {
+	if fh.readBuffer != nil {
+		fh.readBuffer.Invalidate()
+	}
+	fh.objProps = nil
+}
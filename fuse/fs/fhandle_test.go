@@ -0,0 +1,102 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fuse/ais"
+	"github.com/jacobsa/fuse/fuseops"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Truncate", func() {
+	const maxWriteBufSize = cmn.MiB
+
+	var (
+		dir  string
+		fs   *aisfs
+		file *FileInode
+	)
+
+	openHandle := func() *fileHandle {
+		fs.mu.Lock()
+		defer fs.mu.Unlock()
+		return fs.fileHandles[fs.allocateFileHandle(file)]
+	}
+
+	releaseHandle := func(fh *fileHandle) {
+		fh.destroy()
+		file.Lock()
+		file.releaseHandle()
+		file.Unlock()
+		fs.mu.Lock()
+		delete(fs.fileHandles, fh.id)
+		fs.mu.Unlock()
+	}
+
+	read := func(fh *fileHandle) string {
+		b := make([]byte, 64)
+		n, _ := fh.readChunk(b, 0)
+		return string(b[:n])
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "truncate")
+		Expect(err).NotTo(HaveOccurred())
+		fs = &aisfs{
+			cfg:         &ServerConfig{SpillDir: dir},
+			fileHandles: make(map[fuseops.HandleID]*fileHandle),
+		}
+		obj := ais.NewObject("obj", ais.NewBucket("bucket", cmn.AIS, api.BaseParams{}))
+		file = NewFileInode(2, fuseops.InodeAttributes{}, nil, obj).(*FileInode)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should share staged content and its truncation among the handles", func() {
+		fh1, fh2 := openHandle(), openHandle()
+		Expect(fh1.writeChunk([]byte("world"), 6, maxWriteBufSize)).NotTo(HaveOccurred())
+		Expect(fh2.writeChunk([]byte("hello "), 0, maxWriteBufSize)).NotTo(HaveOccurred())
+		Expect(read(fh1)).To(Equal("hello world"))
+
+		Expect(fs.truncateFile(file, 5)).NotTo(HaveOccurred())
+		Expect(read(fh1)).To(Equal("hello"))
+		Expect(read(fh2)).To(Equal("hello"))
+		Expect(file.pending).To(BeTrue())
+
+		releaseHandle(fh1)
+		Expect(file.staging).NotTo(BeNil())
+		releaseHandle(fh2)
+		Expect(file.staging).To(BeNil())
+		Expect(file.pending).To(BeFalse())
+	})
+
+	It("should discard appends of all the handles when truncating to zero", func() {
+		fh1, fh2 := openHandle(), openHandle()
+		Expect(fh1.writeChunk([]byte("abc"), 0, maxWriteBufSize)).NotTo(HaveOccurred())
+		Expect(fh1.dirty).To(BeTrue())
+
+		Expect(fs.truncateFile(file, 0)).NotTo(HaveOccurred())
+		for _, fh := range []*fileHandle{fh1, fh2} {
+			Expect(fh.dirty).To(BeFalse())
+			Expect(fh.wsize).To(BeZero())
+			Expect(fh.fileSize).To(BeZero())
+		}
+		// The (empty) object has not been changed by the discarded appends.
+		Expect(file.staging).To(BeNil())
+		Expect(file.pending).To(BeFalse())
+
+		releaseHandle(fh1)
+		releaseHandle(fh2)
+	})
+})
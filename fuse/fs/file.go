@@ -36,6 +36,10 @@ func (fs *aisfs) CreateFile(ctx context.Context, req *fuseops.CreateFileOp) (err
 	parent := fs.lookupDirMustExist(req.Parent)
 	fs.mu.RUnlock()
 
+	if parent.IsClusterDir() {
+		return syscall.EPERM
+	}
+
 	fileName := path.Join(parent.Path(), req.Name)
 	object, err := parent.LinkNewFile(fileName)
 	if err != nil {
@@ -123,6 +127,9 @@ func (fs *aisfs) ReleaseFileHandle(ctx context.Context, req *fuseops.ReleaseFile
 	// Lookup and release the handle's resources.
 	fhandle := fs.lookupFhandleMustExist(req.Handle)
 	fhandle.destroy()
+	fhandle.file.Lock()
+	fhandle.file.releaseHandle()
+	fhandle.file.Unlock()
 
 	// Remove the handle from the file handles table.
 	delete(fs.fileHandles, req.Handle)
@@ -136,6 +143,10 @@ func (fs *aisfs) Unlink(ctx context.Context, req *fuseops.UnlinkOp) (err error)
 	parent := fs.lookupDirMustExist(req.Parent)
 	fs.mu.RUnlock()
 
+	if parent.IsClusterDir() {
+		return syscall.EISDIR
+	}
+
 	result := parent.LookupEntry(req.Name)
 	if result.NoEntry() || result.NoInode() {
 		return fuse.ENOENT
@@ -5,7 +5,9 @@
 package fs
 
 import (
+	"bytes"
 	"io"
+	"io/ioutil"
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -24,6 +26,18 @@ type FileInode struct {
 	// Object used by current inode. When possible it should be updated with
 	// newer version.
 	object ais.Object
+
+	// Object properties (see ObjectProps) and the time they were fetched.
+	props     *cmn.ObjectProps
+	propsTime time.Time
+
+	// Content of the file staged by random writes or truncation, shared by
+	// all its handles (see stage). Pending is set when the staged content -
+	// or, with no staging, truncation of the file to zero - has not been
+	// uploaded yet.
+	staging *stagingBuffer
+	pending bool
+	handles int // number of open handles, the staging is freed with the last one
 }
 
 func NewFileInode(id fuseops.InodeID, attrs fuseops.InodeAttributes, parent *DirectoryInode, object *ais.Object) Inode {
@@ -38,10 +52,23 @@ func (file *FileInode) Parent() Inode {
 	return file.parent
 }
 
+// REQUIRES_LOCK(file)
+func (file *FileInode) SetParent(parent *DirectoryInode) {
+	file.parent = parent
+}
+
 func (file *FileInode) IsDir() bool {
 	return false
 }
 
+// SetPath sets a path of the file and, therefore, the name of its backing object.
+// REQUIRES_LOCK(file)
+func (file *FileInode) SetPath(path string) {
+	file.baseInode.SetPath(path)
+	file.object.Name = path
+	file.props = nil
+}
+
 // REQUIRES_READ_LOCK(file)
 func (file *FileInode) Size() uint64 {
 	return file.attrs.Size
@@ -95,12 +122,33 @@ func (file *FileInode) UpdateBackingObject(obj *ais.Object) {
 	}
 	file.UpdateAttributes(updReq)
 	file.object = *obj
+	file.props = nil
 }
 
 /////////////
 // READING //
 /////////////
 
+// REQUIRES_READ_LOCK(file)
+func (file *FileInode) Head() (*cmn.ObjectProps, error) {
+	return file.object.Head()
+}
+
+// ObjectProps returns properties of the backing object, fetching them if
+// the cached ones are older than `expiration`.
+// REQUIRES_LOCK(file)
+func (file *FileInode) ObjectProps(expiration time.Duration) (*cmn.ObjectProps, error) {
+	if file.props != nil && time.Since(file.propsTime) < expiration {
+		return file.props, nil
+	}
+	props, err := file.object.Head()
+	if err != nil {
+		return nil, err
+	}
+	file.props, file.propsTime = props, time.Now()
+	return props, nil
+}
+
 // REQUIRES_READ_LOCK(file)
 func (file *FileInode) Load(w io.Writer, offset int64, length int64) (n int64, err error) {
 	n, err = file.object.GetChunk(w, offset, length)
@@ -127,15 +175,116 @@ func (file *FileInode) Write(r cmn.ReadOpenCloser, handle string, size int64) (s
 	return newHandle, nil
 }
 
+// REQUIRES_LOCK(file)
+func (file *FileInode) Put(r cmn.ReadOpenCloser, size int64) error {
+	if err := file.object.Put(r); err != nil {
+		return err
+	}
+	now := time.Now()
+	file.props = nil
+	file.object.Size = size
+	file.object.Atime = now
+	file.attrs.Atime = now
+	file.attrs.Mtime = now
+	return nil
+}
+
 // REQUIRES_LOCK(file)
 func (file *FileInode) Flush(handle string) error {
 	err := file.object.Flush(handle)
 	if err != nil {
 		return err
 	}
+	file.props = nil
 	now := time.Now()
 	file.object.Atime = now
 	file.attrs.Atime = now
 	file.attrs.Mtime = now
 	return nil
 }
+
+/////////////
+// STAGING //
+/////////////
+
+// stage loads the current content of the file into the staging buffer
+// unless it has been staged already. The file truncated to zero is not loaded.
+// REQUIRES_LOCK(file)
+func (file *FileInode) stage(spillDir string) error {
+	if file.staging != nil {
+		return nil
+	}
+	staging := newStagingBuffer(spillDir)
+	if size := int64(file.Size()); size > 0 && !file.pending {
+		if _, err := file.Load(staging, 0, size); err != nil {
+			staging.free()
+			return err
+		}
+	}
+	file.staging = staging
+	return nil
+}
+
+// truncate truncates the staged content of the file. Truncation to zero does
+// not need the content so the file is only marked to be uploaded as empty,
+// unless it is staged already or appended to before the upload.
+// REQUIRES_LOCK(file)
+func (file *FileInode) truncate(size uint64, spillDir string) error {
+	if size == 0 && file.staging == nil {
+		file.pending = true
+		return nil
+	}
+	if err := file.stage(spillDir); err != nil {
+		return err
+	}
+	if err := file.staging.Truncate(int64(size)); err != nil {
+		return err
+	}
+	file.pending = true
+	return nil
+}
+
+// upload puts the staged file (or the empty one) as a whole. The staging
+// buffer is kept until the last handle is released so that subsequent writes
+// do not need to reload it.
+// REQUIRES_LOCK(file)
+func (file *FileInode) upload() (err error) {
+	var (
+		r    cmn.ReadOpenCloser = cmn.NopOpener(ioutil.NopCloser(bytes.NewReader([]byte{})))
+		size int64
+	)
+	if file.staging != nil {
+		if r, err = file.staging.reader(); err != nil {
+			return err
+		}
+		size = file.staging.Size()
+	}
+	if err = file.Put(r, size); err != nil {
+		return err
+	}
+	file.SetSize(uint64(size))
+	file.pending = false
+	return nil
+}
+
+// REQUIRES_LOCK(file)
+func (file *FileInode) openHandle() {
+	file.handles++
+}
+
+// REQUIRES_LOCK(file)
+func (file *FileInode) releaseHandle() {
+	file.handles--
+	if file.handles == 0 {
+		file.dropStaging()
+	}
+}
+
+// REQUIRES_LOCK(file)
+func (file *FileInode) dropStaging() {
+	if file.staging != nil {
+		file.staging.free()
+		file.staging = nil
+	}
+	file.pending = false
+}
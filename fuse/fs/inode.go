@@ -30,8 +30,10 @@ type Inode interface {
 
 	// General
 	Parent() Inode
+	SetParent(*DirectoryInode)
 	ID() fuseops.InodeID
 	Path() string
+	SetPath(string)
 	IsDir() bool
 	Destroy() error
 
@@ -75,6 +77,12 @@ func (in *baseInode) Path() string {
 	return in.path
 }
 
+// SetPath sets a path that maps to an inode (eg. after rename).
+// REQUIRES_LOCK(in)
+func (in *baseInode) SetPath(path string) {
+	in.path = path
+}
+
 // Attributes returns inode's attributes (mode, size, atime...).
 // REQUIRES_READ_LOCK(in)
 func (in *baseInode) Attributes() (attrs fuseops.InodeAttributes) {
@@ -0,0 +1,148 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+// Object properties (as returned by HEAD) are exposed as read-only extended
+// attributes of files, eg.:
+//
+//   $ getfattr -d mnt/dir/file
+//   user.ais.checksum="0b7c3e6ebcd9b4ac"
+//   user.ais.checksum_type="xxhash"
+//   ...
+//
+// NOTE: AIS objects have no user-defined metadata, therefore setting
+// (or removing) any other attributes is not supported.
+
+const (
+	xattrPrefix = "user.ais."
+
+	xattrChecksum     = xattrPrefix + "checksum"
+	xattrChecksumType = xattrPrefix + "checksum_type"
+	xattrVersion      = xattrPrefix + "version"
+	xattrAtime        = xattrPrefix + "atime"
+	xattrCopies       = xattrPrefix + "copies"
+	xattrCached       = xattrPrefix + "cached"
+	xattrECData       = xattrPrefix + "ec.data_slices"
+	xattrECParity     = xattrPrefix + "ec.parity_slices"
+	xattrECIsCopy     = xattrPrefix + "ec.is_copy"
+
+	// How long object properties are reused for the subsequent xattr requests
+	// (`getfattr -d` issues one request per attribute).
+	objPropsExpiration = 5 * time.Second
+)
+
+type xattr struct {
+	name  string
+	value string
+}
+
+func objectXattrs(props *cmn.ObjectProps) (xattrs []xattr) {
+	add := func(name, value string) {
+		if value != "" {
+			xattrs = append(xattrs, xattr{name: name, value: value})
+		}
+	}
+	add(xattrChecksum, props.Checksum)
+	add(xattrChecksumType, props.ChecksumType)
+	add(xattrVersion, props.Version)
+	if !props.Atime.IsZero() {
+		add(xattrAtime, props.Atime.Format(time.RFC3339))
+	}
+	add(xattrCopies, strconv.Itoa(props.NumCopies))
+	if !props.BckIsAIS {
+		add(xattrCached, strconv.FormatBool(props.Present))
+	}
+	if props.DataSlices > 0 {
+		add(xattrECData, strconv.Itoa(props.DataSlices))
+		add(xattrECParity, strconv.Itoa(props.ParitySlices))
+		add(xattrECIsCopy, strconv.FormatBool(props.IsECCopy))
+	}
+	return
+}
+
+// LOCKS(file)
+func (fs *aisfs) fileXattrs(id fuseops.InodeID) (xattrs []xattr, err error) {
+	fs.mu.RLock()
+	inode := fs.lookupMustExist(id)
+	fs.mu.RUnlock()
+
+	// Directories are virtual - there is nothing to expose.
+	if inode.IsDir() {
+		return nil, nil
+	}
+
+	file := inode.(*FileInode)
+	file.Lock()
+	props, err := file.ObjectProps(objPropsExpiration)
+	file.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return objectXattrs(props), nil
+}
+
+func (fs *aisfs) GetXattr(ctx context.Context, req *fuseops.GetXattrOp) (err error) {
+	xattrs, err := fs.fileXattrs(req.Inode)
+	if err != nil {
+		return fs.handleIOError(err)
+	}
+	for _, attr := range xattrs {
+		if attr.name != req.Name {
+			continue
+		}
+		req.BytesRead = len(attr.value)
+		if len(req.Dst) < len(attr.value) {
+			return syscall.ERANGE
+		}
+		copy(req.Dst, attr.value)
+		return nil
+	}
+	return fuse.ENOATTR
+}
+
+func (fs *aisfs) ListXattr(ctx context.Context, req *fuseops.ListXattrOp) (err error) {
+	xattrs, err := fs.fileXattrs(req.Inode)
+	if err != nil {
+		return fs.handleIOError(err)
+	}
+	dst := req.Dst
+	for _, attr := range xattrs {
+		nameLen := len(attr.name) + 1 // NUL-terminated
+		if err == nil && len(dst) >= nameLen {
+			copy(dst, attr.name)
+			dst = dst[nameLen:]
+		} else {
+			err = syscall.ERANGE
+		}
+		req.BytesRead += nameLen
+	}
+	return
+}
+
+func (fs *aisfs) SetXattr(ctx context.Context, req *fuseops.SetXattrOp) (err error) {
+	if strings.HasPrefix(req.Name, xattrPrefix) {
+		return syscall.EPERM
+	}
+	return syscall.ENOTSUP
+}
+
+func (fs *aisfs) RemoveXattr(ctx context.Context, req *fuseops.RemoveXattrOp) (err error) {
+	if strings.HasPrefix(req.Name, xattrPrefix) {
+		return syscall.EPERM
+	}
+	return syscall.ENOTSUP
+}
@@ -0,0 +1,55 @@
+// Package fs implements an AIStore file system.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Xattr", func() {
+	names := func(xattrs []xattr) []string {
+		var names []string
+		for _, attr := range xattrs {
+			names = append(names, attr.name)
+		}
+		return names
+	}
+
+	It("should expose properties of ais object", func() {
+		atime := time.Date(2019, 11, 21, 10, 32, 8, 0, time.UTC)
+		xattrs := objectXattrs(&cmn.ObjectProps{
+			Checksum:     "abc",
+			ChecksumType: cmn.ChecksumXXHash,
+			Version:      "2",
+			Atime:        atime,
+			NumCopies:    1,
+			BckIsAIS:     true,
+		})
+		Expect(xattrs).To(Equal([]xattr{
+			{name: xattrChecksum, value: "abc"},
+			{name: xattrChecksumType, value: cmn.ChecksumXXHash},
+			{name: xattrVersion, value: "2"},
+			{name: xattrAtime, value: "2019-11-21T10:32:08Z"},
+			{name: xattrCopies, value: "1"},
+		}))
+	})
+
+	It("should expose cached state and EC info", func() {
+		xattrs := objectXattrs(&cmn.ObjectProps{
+			NumCopies:    1,
+			Present:      true,
+			DataSlices:   2,
+			ParitySlices: 1,
+		})
+		Expect(names(xattrs)).To(Equal([]string{
+			xattrCopies, xattrCached, xattrECData, xattrECParity, xattrECIsCopy,
+		}))
+		Expect(xattrs[1].value).To(Equal("true"))
+	})
+})
@@ -32,7 +32,9 @@ const (
 // URL HANDLING
 ////////////////
 
-func determineClusterURL(c *cli.Context, cfg *Config, bucket string) (clusterURL string, err error) {
+// determineClusterURL returns the URL of the cluster and the provider of the
+// bucket (if the bucket is given).
+func determineClusterURL(c *cli.Context, cfg *Config, bucket string) (clusterURL, provider string, err error) {
 	// Determine which cluster URL will be used
 	clusterURL = cfg.Cluster.URL
 	if clusterURL == "" {
@@ -42,13 +44,14 @@ func determineClusterURL(c *cli.Context, cfg *Config, bucket string) (clusterURL
 	// Check if URL is malformed
 	if _, err = url.Parse(clusterURL); err != nil {
 		err = fmt.Errorf("Malformed URL (%q): %v", clusterURL, err)
-		return "", err
+		return "", "", err
 	}
 
 	// Try to access the bucket, possibly catching an early error
-	if ok := tryAccessBucket(clusterURL, bucket); !ok {
+	provider, ok := tryAccessBucket(clusterURL, bucket)
+	if !ok {
 		err = fmt.Errorf("No response from proxy at %q (bucket %q)", clusterURL, bucket)
-		return "", err
+		return "", "", err
 	}
 
 	return
@@ -94,14 +97,37 @@ func discoverClusterURL(c *cli.Context) string {
 	return defaultAISURL
 }
 
-func tryAccessBucket(url string, bucket string) bool {
+// tryAccessBucket returns the provider of the bucket (either cmn.AIS or
+// cmn.Cloud) which is then passed to all the requests of the mount.
+func tryAccessBucket(url string, bucket string) (provider string, ok bool) {
 	baseParams := api.BaseParams{
 		Client: &http.Client{},
 		URL:    url,
 	}
 
-	_, err := api.HeadBucket(baseParams, bucket)
-	return err == nil
+	// The whole cluster is mounted.
+	if bucket == "" {
+		_, err := api.GetBucketNames(baseParams, cmn.AIS)
+		return "", err == nil
+	}
+	// The bucket is looked up by the name only, the same way as the cluster
+	// does when the provider is not specified.
+	props, err := api.HeadBucket(baseParams, bucket)
+	if err != nil {
+		return "", false
+	}
+	if provider, err = cmn.ProviderFromStr(props.CloudProvider); err != nil || provider == "" {
+		return "", false
+	}
+	return provider, true
+}
+
+// mountName returns the name used for config and log files of the mount.
+func mountName(bucket string) string {
+	if bucket == "" {
+		return clusterMountName
+	}
+	return bucket
 }
 
 //////////////////
@@ -0,0 +1,252 @@
+// Package lifecycle applies the per-bucket lifecycle rules (expiration, eviction,
+// deferred mirroring and erasure coding) to the stored objects.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package lifecycle
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// ============================================= Summary ===========================================
+//
+// Each bucket can be configured with lifecycle rules (see cmn.LifecycleConf) that apply to
+// its objects depending on their age:
+//   - expiration: objects of ais buckets older than lifecycle.expire_days get deleted
+//   - eviction: cached objects of cloud buckets not accessed for lifecycle.evict_days get evicted
+//   - mirroring: objects get mirrored (see cmn.MirrorConf) once they are lifecycle.mirror_days old
+//   - erasure coding: objects get erasure coded (see cmn.ECConf) once they are lifecycle.ec_days old
+// The age of an object is the time since its content was written. The rules apply only
+// to the objects whose names start with one of the lifecycle.prefix prefixes, if any.
+//
+// The rules are evaluated by the lifecycle x-action that the target runs periodically. The
+// x-action walks the buckets with the rules enabled on all mountpaths in parallel, one jogger
+// per mountpath, similarly to LRU. Each target applies the rules to the objects it stores.
+//
+// ============================================= Summary ===========================================
+
+const day = 24 * time.Hour
+
+type (
+	// InitLifecycle provides the x-action with the target's primitives to act on the objects
+	InitLifecycle struct {
+		Xact   *Xaction
+		T      cluster.Target
+		Delete func(lom *cluster.LOM) error // deletes the object
+		Evict  func(lom *cluster.LOM) error // evicts the cached object of cloud bucket
+		Mirror func(lom *cluster.LOM)       // creates the local copies of the object
+		Encode func(lom *cluster.LOM) error // erasure codes the object
+	}
+
+	Xaction struct {
+		cmn.MountpathXact
+		cmn.XactBase
+	}
+
+	// jogger applies the rules to the objects stored on a single mountpath
+	jogger struct {
+		ini       *InitLifecycle
+		mpathInfo *fs.MountpathInfo
+		config    *cmn.Config
+		now       time.Time
+		// current bucket
+		provider string
+		conf     *cmn.LifecycleConf
+		// stats
+		objects, bytes int64
+	}
+)
+
+func (xact *Xaction) Description() string {
+	return "apply lifecycle rules (expiration, eviction, deferred mirroring and erasure coding) of the buckets"
+}
+
+// Run applies the lifecycle rules of all buckets; returns when done or aborted.
+func Run(ini *InitLifecycle) {
+	var (
+		wg                 = &sync.WaitGroup{}
+		availablePaths, _  = fs.Mountpaths.Get()
+		joggers            = make([]*jogger, 0, len(availablePaths))
+		config             = cmn.GCO.Get()
+		now                = time.Now()
+		objects, bytesDone int64
+	)
+	glog.Infof("%s started", ini.Xact)
+	for _, mpathInfo := range availablePaths {
+		j := &jogger{ini: ini, mpathInfo: mpathInfo, config: config, now: now}
+		joggers = append(joggers, j)
+		wg.Add(1)
+		go j.jog(wg)
+	}
+	wg.Wait()
+	for _, j := range joggers {
+		objects += j.objects
+		bytesDone += j.bytes
+	}
+	ini.Xact.ObjectsAdd(objects)
+	ini.Xact.BytesAdd(bytesDone)
+	glog.Infof("%s finished: %d object(s) processed", ini.Xact, objects)
+}
+
+func (j *jogger) jog(wg *sync.WaitGroup) {
+	defer wg.Done()
+	bmd := j.ini.T.GetBowner().Get()
+	for _, provider := range []string{cmn.AIS, cmn.Cloud} {
+		bmap := bmd.LBmap
+		if provider == cmn.Cloud {
+			bmap = bmd.CBmap
+		}
+		for bucket, props := range bmap {
+			if !props.Lifecycle.Enabled {
+				continue
+			}
+			j.provider, j.conf = provider, &props.Lifecycle
+			if err := j.walkBucket(bucket); err != nil {
+				glog.Infof("%s: stopping traversal: %v", j.mpathInfo, err)
+				return
+			}
+		}
+	}
+}
+
+func (j *jogger) walkBucket(bucket string) error {
+	dir := j.mpathInfo.MakePathBucket(fs.ObjectType, bucket, j.provider)
+	if _, err := os.Stat(dir); err != nil {
+		if !os.IsNotExist(err) {
+			glog.Errorf("%s: failed to stat %q, err: %v", j.mpathInfo, dir, err)
+		}
+		return nil
+	}
+	opts := &fs.Options{Callback: j.walk, Sorted: false}
+	if err := fs.Walk(dir, opts); err != nil {
+		if j.aborted() {
+			return err
+		}
+		glog.Errorf("%s: failed to traverse, err: %v", dir, err)
+	}
+	return nil
+}
+
+func (j *jogger) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if j.aborted() {
+		return fmt.Errorf("%s aborted, exiting", j.ini.Xact)
+	}
+	runtime.Gosched()
+
+	lom := &cluster.LOM{T: j.ini.T, FQN: fqn}
+	if err := lom.Init("", j.provider, j.config); err != nil {
+		return nil
+	}
+	if !j.conf.Matches(lom.Objname) {
+		return nil
+	}
+	if err := lom.Load(false); err != nil {
+		return nil
+	}
+	// the rules are applied by the "owner" of the object; copies and misplaced
+	// objects are taken care of together with the object, and by rebalance
+	if lom.IsCopy() || !lom.IsHRW() {
+		return nil
+	}
+	wtime, err := writeTime(lom)
+	if err != nil {
+		return nil
+	}
+	if err := j.apply(lom, wtime); err != nil {
+		glog.Errorf("%s: failed to apply lifecycle rules, err: %v", lom, err)
+	}
+	return nil
+}
+
+// writeTime returns the time the object was written as per its metadata - unlike
+// mtime, it is retained when the object gets rebalanced, resilvered, or mirrored.
+// Falls back to mtime for the objects stored prior to persisting the write time.
+func writeTime(lom *cluster.LOM) (time.Time, error) {
+	if wtime := lom.WtimeUnix(); wtime != 0 {
+		return time.Unix(0, wtime), nil
+	}
+	finfo, err := os.Stat(lom.FQN)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return finfo.ModTime(), nil
+}
+
+// apply applies the rules to the object given the time it was written.
+func (j *jogger) apply(lom *cluster.LOM, wtime time.Time) (err error) {
+	var (
+		conf  = j.conf
+		props = lom.Bprops()
+		size  = lom.Size()
+		done  bool
+	)
+	switch {
+	case lom.IsAIS() && due(conf.ExpireDays, wtime, j.now):
+		if err = lom.AllowDELETE(); err == nil {
+			err = j.ini.Delete(lom)
+		}
+		done = true
+	case !lom.IsAIS() && due(conf.EvictDays, lom.Atime(), j.now):
+		err = j.ini.Evict(lom)
+		done = true
+	default:
+		if props.Mirror.Enabled && due(conf.MirrorDays, wtime, j.now) && int64(lom.NumCopies()) < props.Mirror.Copies {
+			j.ini.Mirror(lom)
+			done = true
+		}
+		if props.EC.Enabled && due(conf.ECDays, wtime, j.now) {
+			var encoded bool
+			if encoded, err = isEncoded(lom); err == nil && !encoded {
+				err = j.ini.Encode(lom)
+				done = true
+			}
+		}
+	}
+	if done && err == nil {
+		j.objects++
+		j.bytes += size
+	}
+	return
+}
+
+func (j *jogger) aborted() bool {
+	select {
+	case <-j.ini.Xact.ChanAbort():
+		return true
+	default:
+		return j.ini.Xact.Finished()
+	}
+}
+
+// due returns true if the rule that applies `days` after `since` is due `now`.
+func due(days int64, since, now time.Time) bool {
+	return days > 0 && now.Sub(since) >= time.Duration(days)*day
+}
+
+// isEncoded returns true if the object has already been erasure coded.
+func isEncoded(lom *cluster.LOM) (bool, error) {
+	mdFQN, _, err := cluster.HrwFQN(ec.MetaType, lom.Bck(), lom.Objname)
+	if err != nil {
+		return false, err
+	}
+	if _, err = os.Stat(mdFQN); err == nil {
+		return true, nil
+	} else if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
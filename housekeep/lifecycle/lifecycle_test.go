@@ -0,0 +1,168 @@
+// Package lifecycle applies the per-bucket lifecycle rules (expiration, eviction,
+// deferred mirroring and erasure coding) to the stored objects.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package lifecycle
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestLifecycleMain(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Lifecycle Suite")
+}
+
+const (
+	basePath    = "/tmp/lifecycle-tests/"
+	aisBucket   = "lifecycle-ais"
+	cloudBucket = "lifecycle-cloud"
+)
+
+type actions struct {
+	mtx                        sync.Mutex
+	deleted, evicted, mirrored []string
+}
+
+func (a *actions) add(list *[]string) func(lom *cluster.LOM) error {
+	return func(lom *cluster.LOM) error {
+		a.mtx.Lock()
+		*list = append(*list, lom.Objname)
+		sort.Strings(*list)
+		a.mtx.Unlock()
+		return nil
+	}
+}
+
+func createAndAddMountpath(path string) {
+	cmn.CreateDir(path)
+	fs.InitMountedFS()
+	fs.Mountpaths.Add(path)
+
+	fs.CSM.RegisterFileType(fs.ObjectType, &fs.ObjectContentResolver{})
+	fs.CSM.RegisterFileType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+}
+
+// saveObject creates the object written (wtime and mtime) and accessed (atime) `age` ago
+func saveObject(t cluster.Target, bucket, provider, objName string, age time.Duration) *cluster.LOM {
+	mpaths, _ := fs.Mountpaths.Get()
+	fqn := mpaths[filepath.Clean(basePath)].MakePathBucketObject(fs.ObjectType, bucket, provider, objName)
+	_, err := cmn.SaveReader(fqn, nil, nil, false, 0)
+	Expect(err).NotTo(HaveOccurred())
+	lom := &cluster.LOM{T: t, FQN: fqn}
+	Expect(lom.Init("", provider)).NotTo(HaveOccurred())
+	tm := time.Now().Add(-age)
+	lom.SetAtimeUnix(tm.UnixNano())
+	lom.SetWtimeUnix(tm.UnixNano())
+	Expect(lom.Persist()).NotTo(HaveOccurred())
+	Expect(os.Chtimes(fqn, tm, tm)).NotTo(HaveOccurred())
+	return lom
+}
+
+var _ = Describe("Lifecycle", func() {
+	var (
+		t   *cluster.TargetMock
+		ini *InitLifecycle
+		act *actions
+
+		aisProps   *cmn.BucketProps
+		cloudProps *cmn.BucketProps
+	)
+
+	BeforeEach(func() {
+		createAndAddMountpath(basePath)
+		aisProps = &cmn.BucketProps{Cksum: cmn.CksumConf{Type: cmn.ChecksumNone}}
+		cloudProps = &cmn.BucketProps{Cksum: cmn.CksumConf{Type: cmn.ChecksumNone}}
+		t = cluster.NewTargetMock(cluster.BownerMock{BMD: cluster.BMD{
+			LBmap: map[string]*cmn.BucketProps{aisBucket: aisProps},
+			CBmap: map[string]*cmn.BucketProps{cloudBucket: cloudProps},
+		}})
+		act = &actions{}
+		ini = &InitLifecycle{
+			Xact:   &Xaction{},
+			T:      t,
+			Delete: act.add(&act.deleted),
+			Evict:  act.add(&act.evicted),
+			Mirror: func(lom *cluster.LOM) { act.add(&act.mirrored)(lom) },
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(basePath)
+	})
+
+	It("should not fail when there are no objects", func() {
+		aisProps.Lifecycle = cmn.LifecycleConf{ExpireDays: 1, Enabled: true}
+		Run(ini)
+		Expect(act.deleted).To(BeEmpty())
+	})
+
+	It("should delete expired objects matching the prefix", func() {
+		aisProps.Lifecycle = cmn.LifecycleConf{Prefix: "tmp/, scratch/", ExpireDays: 2, Enabled: true}
+		saveObject(t, aisBucket, cmn.AIS, "tmp/old", 3*day)
+		saveObject(t, aisBucket, cmn.AIS, "scratch/old", 3*day)
+		saveObject(t, aisBucket, cmn.AIS, "tmp/new", day)
+		saveObject(t, aisBucket, cmn.AIS, "keep/old", 3*day)
+
+		Run(ini)
+		Expect(act.deleted).To(Equal([]string{"scratch/old", "tmp/old"}))
+		Expect(ini.Xact.ObjectsCnt()).To(BeEquivalentTo(2))
+	})
+
+	It("should age objects by the write time rather than mtime", func() {
+		aisProps.Lifecycle = cmn.LifecycleConf{ExpireDays: 2, Enabled: true}
+		// migrated: the content is old while the file is new
+		lom := saveObject(t, aisBucket, cmn.AIS, "migrated", 3*day)
+		Expect(os.Chtimes(lom.FQN, time.Now(), time.Now())).NotTo(HaveOccurred())
+		// no write time in metadata: falls back to mtime
+		lom = saveObject(t, aisBucket, cmn.AIS, "legacy", 3*day)
+		lom.SetWtimeUnix(0)
+		Expect(lom.Persist()).NotTo(HaveOccurred())
+		lom = saveObject(t, aisBucket, cmn.AIS, "legacy-new", day)
+		lom.SetWtimeUnix(0)
+		Expect(lom.Persist()).NotTo(HaveOccurred())
+
+		Run(ini)
+		Expect(act.deleted).To(Equal([]string{"legacy", "migrated"}))
+	})
+
+	It("should not apply disabled rules", func() {
+		aisProps.Lifecycle = cmn.LifecycleConf{ExpireDays: 1}
+		saveObject(t, aisBucket, cmn.AIS, "old", 3*day)
+
+		Run(ini)
+		Expect(act.deleted).To(BeEmpty())
+	})
+
+	It("should evict cloud objects that have not been accessed", func() {
+		cloudProps.Lifecycle = cmn.LifecycleConf{EvictDays: 1, Enabled: true}
+		saveObject(t, cloudBucket, cmn.Cloud, "old", 2*day)
+		saveObject(t, cloudBucket, cmn.Cloud, "new", time.Hour)
+
+		Run(ini)
+		Expect(act.evicted).To(Equal([]string{"old"}))
+		Expect(act.deleted).To(BeEmpty())
+	})
+
+	It("should mirror aged objects", func() {
+		aisProps.Mirror = cmn.MirrorConf{Copies: 2, Enabled: true}
+		aisProps.Lifecycle = cmn.LifecycleConf{MirrorDays: 7, Enabled: true}
+		saveObject(t, aisBucket, cmn.AIS, "old", 8*day)
+		saveObject(t, aisBucket, cmn.AIS, "new", 6*day)
+
+		Run(ini)
+		Expect(act.mirrored).To(Equal([]string{"old"}))
+	})
+})
@@ -0,0 +1,394 @@
+// Package notif delivers object events to the webhooks configured per bucket.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package notif
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Theory of operation
+//
+// Each target delivers the events of the objects it stores. The events of
+// a bucket are accumulated in batches - a batch is closed when it reaches
+// notif.batch_size events or when its oldest event has waited notif.batch_time.
+// The closed batch is written to the on-disk spool and POSTed to the webhook
+// (as cmn.ObjectEvents). Failed deliveries are retried with exponential
+// backoff; the batches are delivered in order and removed from the spool only
+// once delivered, so they survive target restarts. Batches rejected by the
+// webhook (4xx other than 429) are dropped, and so are the oldest batches when
+// the spool of the webhook overflows.
+
+const (
+	SpoolDirName = "notif_spool"
+
+	DefaultBatchSize = 100
+	DefaultBatchTime = 5 * time.Second
+
+	maxSpooled           = 10000 // max number of undelivered batches per webhook
+	initialRetryInterval = time.Second
+	maxRetryInterval     = time.Minute
+	sendTimeout          = 30 * time.Second
+
+	urlFname = "url" // webhook URL in the spool directory of the hook
+)
+
+type (
+	notifier struct {
+		daemonID string
+		spoolDir string // empty: batches are kept in memory only
+		client   *http.Client
+		stopCh   chan struct{}
+
+		mtx     sync.Mutex
+		hooks   map[string]*hook // see hookKey
+		stopped bool
+	}
+
+	// hook batches and delivers the events of a single bucket to a single webhook URL
+	hook struct {
+		n   *notifier
+		url string
+		dir string // spool directory
+
+		mtx       sync.Mutex
+		batchSize int
+		batchTime time.Duration
+		pending   []cmn.ObjectEvent
+		timer     *time.Timer
+		queue     []*batch // closed batches, oldest first
+		seq       int64    // sequence number of the last closed batch
+		sending   bool
+	}
+
+	batch struct {
+		fqn  string // spooled batch
+		body []byte // in-memory batch (when spooling has failed)
+	}
+)
+
+var ntf *notifier
+
+// Init starts the notifier and resumes delivery of the batches spooled by
+// the previous run. Spooling is disabled if `spoolDir` is empty.
+func Init(daemonID, spoolDir string) error {
+	ntf = newNotifier(daemonID, spoolDir)
+	return ntf.load()
+}
+
+// Stop stops the delivery; the pending events get spooled.
+func Stop() {
+	if ntf != nil {
+		ntf.stop()
+	}
+}
+
+// Notify sends the event of the object to the webhook of its bucket, if configured.
+func Notify(lom *cluster.LOM, event string, newName ...string) {
+	if ntf == nil || lom.Bprops() == nil {
+		return
+	}
+	conf := &lom.Bprops().Notif
+	if !conf.Enabled || !conf.Wants(event) {
+		return
+	}
+	ev := cmn.ObjectEvent{
+		Type:     event,
+		Bucket:   lom.Bucket(),
+		Provider: lom.Bck().Provider,
+		Name:     lom.Objname,
+		Time:     time.Now().UnixNano(),
+	}
+	if event == cmn.NotifRename {
+		ev.NewName = newName[0]
+	}
+	if event != cmn.NotifDelete {
+		ev.Size, ev.Version = lom.Size(), lom.Version()
+		if cksum := lom.Cksum(); cksum != nil {
+			_, ev.Checksum = cksum.Get()
+		}
+	}
+	ntf.notify(lom.Bck().MakeUname(""), conf, ev)
+}
+
+func newNotifier(daemonID, spoolDir string) *notifier {
+	return &notifier{
+		daemonID: daemonID,
+		spoolDir: spoolDir,
+		client:   cmn.NewClient(cmn.TransportArgs{Timeout: sendTimeout, UseHTTPProxyEnv: true}),
+		stopCh:   make(chan struct{}),
+		hooks:    make(map[string]*hook),
+	}
+}
+
+func hookKey(bckUname, url string) string {
+	h := sha256.Sum256([]byte(bckUname + "\x00" + url))
+	return hex.EncodeToString(h[:16])
+}
+
+// load resumes the delivery of the spooled batches
+func (n *notifier) load() error {
+	if n.spoolDir == "" {
+		return nil
+	}
+	if err := cmn.CreateDir(n.spoolDir); err != nil {
+		err = fmt.Errorf("failed to create notification spool %q: %v", n.spoolDir, err)
+		n.spoolDir = ""
+		return err
+	}
+	dirs, err := ioutil.ReadDir(n.spoolDir)
+	if err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		dir := filepath.Join(n.spoolDir, d.Name())
+		url, err := ioutil.ReadFile(filepath.Join(dir, urlFname))
+		if err != nil {
+			glog.Errorf("%s: invalid notification spool, err: %v", dir, err)
+			continue
+		}
+		h := n.newHook(string(url), dir)
+		names, err := batchNames(dir)
+		if err != nil {
+			glog.Errorf("%s: failed to read notification spool, err: %v", dir, err)
+			continue
+		}
+		for _, name := range names {
+			h.queue = append(h.queue, &batch{fqn: filepath.Join(dir, name)})
+			h.seq, _ = strconv.ParseInt(strings.TrimSuffix(name, ".json"), 10, 64)
+		}
+		n.hooks[d.Name()] = h
+		if len(h.queue) > 0 {
+			glog.Infof("resuming delivery of %d batch(es) of events to %s", len(h.queue), h.url)
+			h.sending = true
+			go h.send()
+		}
+	}
+	return nil
+}
+
+// batchNames returns names of the spooled batches ordered by sequence number.
+func batchNames(dir string) (names []string, err error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), ".json") {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names) // zero-padded
+	return
+}
+
+func (n *notifier) newHook(url, dir string) *hook {
+	return &hook{n: n, url: url, dir: dir, batchSize: DefaultBatchSize, batchTime: DefaultBatchTime}
+}
+
+func (n *notifier) notify(bckUname string, conf *cmn.NotifConf, ev cmn.ObjectEvent) {
+	ev.Target = n.daemonID
+	key := hookKey(bckUname, conf.URL)
+	n.mtx.Lock()
+	if n.stopped {
+		n.mtx.Unlock()
+		return
+	}
+	h, ok := n.hooks[key]
+	if !ok {
+		var dir string
+		if n.spoolDir != "" {
+			dir = filepath.Join(n.spoolDir, key)
+			if err := n.createSpool(dir, conf.URL); err != nil {
+				glog.Errorf("failed to create notification spool, err: %v", err)
+				dir = ""
+			}
+		}
+		h = n.newHook(conf.URL, dir)
+		n.hooks[key] = h
+	}
+	n.mtx.Unlock()
+	h.add(conf, ev)
+}
+
+func (n *notifier) createSpool(dir, url string) error {
+	if err := cmn.CreateDir(dir); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, urlFname), []byte(url), 0644)
+}
+
+func (n *notifier) stop() {
+	n.mtx.Lock()
+	if n.stopped {
+		n.mtx.Unlock()
+		return
+	}
+	n.stopped = true
+	close(n.stopCh)
+	hooks := n.hooks
+	n.mtx.Unlock()
+
+	for _, h := range hooks {
+		h.mtx.Lock()
+		h.close()
+		h.mtx.Unlock()
+	}
+}
+
+//
+// hook
+//
+
+func (h *hook) add(conf *cmn.NotifConf, ev cmn.ObjectEvent) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if conf.BatchSize > 0 {
+		h.batchSize = int(conf.BatchSize)
+	}
+	if d, err := time.ParseDuration(conf.BatchTimeStr); err == nil && d > 0 {
+		h.batchTime = d
+	}
+	h.pending = append(h.pending, ev)
+	if len(h.pending) >= h.batchSize {
+		h.close()
+	} else if h.timer == nil {
+		h.timer = time.AfterFunc(h.batchTime, func() {
+			h.mtx.Lock()
+			h.close()
+			h.mtx.Unlock()
+		})
+	}
+}
+
+// close closes the pending batch, spools it and starts the delivery.
+// REQUIRES_LOCK(h.mtx)
+func (h *hook) close() {
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+	if len(h.pending) == 0 {
+		return
+	}
+	var (
+		b    = &batch{}
+		body = cmn.MustMarshal(cmn.ObjectEvents{Events: h.pending})
+	)
+	h.pending = nil
+	h.seq++
+	if h.dir != "" {
+		fqn := filepath.Join(h.dir, fmt.Sprintf("%016d.json", h.seq))
+		if _, err := cmn.SaveReaderSafe(fqn+".tmp", fqn, bytes.NewReader(body), nil, false); err != nil {
+			glog.Errorf("failed to spool events, err: %v", err)
+		} else {
+			b.fqn = fqn
+		}
+	}
+	if b.fqn == "" {
+		b.body = body
+	}
+
+	if len(h.queue) >= maxSpooled {
+		glog.Errorf("%s: too many undelivered batches of events - dropping the oldest", h.url)
+		h.queue[0].remove()
+		h.queue = h.queue[1:]
+	}
+	h.queue = append(h.queue, b)
+	if !h.sending && !h.stopped() {
+		h.sending = true
+		go h.send()
+	}
+}
+
+func (h *hook) stopped() bool {
+	select {
+	case <-h.n.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// send delivers the queued batches in order
+func (h *hook) send() {
+	retry := initialRetryInterval
+	for {
+		h.mtx.Lock()
+		if len(h.queue) == 0 || h.stopped() {
+			h.sending = false
+			h.mtx.Unlock()
+			return
+		}
+		b := h.queue[0]
+		h.mtx.Unlock()
+
+		if err, retriable := h.post(b); err != nil && retriable {
+			glog.Warningf("%s: failed to deliver events (retrying in %v), err: %v", h.url, retry, err)
+			select {
+			case <-time.After(retry):
+			case <-h.n.stopCh:
+			}
+			retry = cmn.MinDur(2*retry, maxRetryInterval)
+			continue
+		} else if err != nil {
+			glog.Errorf("%s: failed to deliver events (dropping the batch), err: %v", h.url, err)
+		}
+		retry = initialRetryInterval
+
+		h.mtx.Lock()
+		// the batch could have been dropped in the meantime
+		if len(h.queue) > 0 && h.queue[0] == b {
+			h.queue = h.queue[1:]
+			b.remove()
+		}
+		h.mtx.Unlock()
+	}
+}
+
+func (h *hook) post(b *batch) (err error, retriable bool) {
+	body := b.body
+	if body == nil {
+		if body, err = ioutil.ReadFile(b.fqn); err != nil {
+			if os.IsNotExist(err) {
+				return nil, false // dropped
+			}
+			return err, true
+		}
+	}
+	resp, err := h.n.client.Post(h.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err, true
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		err = fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+		retriable = resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+	}
+	return
+}
+
+func (b *batch) remove() {
+	if b.fqn != "" {
+		if err := os.Remove(b.fqn); err != nil && !os.IsNotExist(err) {
+			glog.Errorf("failed to remove spooled events, err: %v", err)
+		}
+	}
+}
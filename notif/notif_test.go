@@ -0,0 +1,157 @@
+// Package notif delivers object events to the webhooks configured per bucket.
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package notif
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+type webhook struct {
+	mtx     sync.Mutex
+	batches []cmn.ObjectEvents
+	fail    bool
+}
+
+func (wh *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wh.mtx.Lock()
+	defer wh.mtx.Unlock()
+	if wh.fail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var events cmn.ObjectEvents
+	if err := jsoniter.NewDecoder(r.Body).Decode(&events); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	wh.batches = append(wh.batches, events)
+}
+
+func (wh *webhook) setFail(fail bool) {
+	wh.mtx.Lock()
+	wh.fail = fail
+	wh.mtx.Unlock()
+}
+
+func (wh *webhook) events() (names []string) {
+	wh.mtx.Lock()
+	defer wh.mtx.Unlock()
+	for _, b := range wh.batches {
+		for _, ev := range b.Events {
+			names = append(names, ev.Name)
+		}
+	}
+	return
+}
+
+func waitEvents(t *testing.T, wh *webhook, cnt int) []string {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if names := wh.events(); len(names) >= cnt {
+			return names
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("expected %d events, got: %v", cnt, wh.events())
+	return nil
+}
+
+func notifyN(n *notifier, conf *cmn.NotifConf, from, to int) {
+	for i := from; i < to; i++ {
+		n.notify("ais/bucket/", conf, cmn.ObjectEvent{Type: cmn.NotifPut, Name: fmt.Sprintf("obj%d", i)})
+	}
+}
+
+func TestNotifyBatching(t *testing.T) {
+	wh := &webhook{}
+	srv := httptest.NewServer(wh)
+	defer srv.Close()
+
+	n := newNotifier("target", "")
+	defer n.stop()
+	conf := &cmn.NotifConf{URL: srv.URL, BatchSize: 3, BatchTimeStr: "100ms", Enabled: true}
+
+	notifyN(n, conf, 0, 4)
+	names := waitEvents(t, wh, 4)
+	wh.mtx.Lock()
+	defer wh.mtx.Unlock()
+	if len(wh.batches) != 2 || len(wh.batches[0].Events) != 3 {
+		t.Fatalf("expected batches of 3 and 1 events, got: %+v", wh.batches)
+	}
+	for i, name := range names {
+		if name != fmt.Sprintf("obj%d", i) {
+			t.Fatalf("events out of order: %v", names)
+		}
+	}
+	if wh.batches[0].Events[0].Target != "target" {
+		t.Errorf("expected target ID to be set, got: %+v", wh.batches[0].Events[0])
+	}
+}
+
+func TestNotifyRetryAndSpool(t *testing.T) {
+	wh := &webhook{fail: true}
+	srv := httptest.NewServer(wh)
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "notif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	conf := &cmn.NotifConf{URL: srv.URL, BatchSize: 2, BatchTimeStr: "1h", Enabled: true}
+	n := newNotifier("target", dir)
+	if err := n.load(); err != nil {
+		t.Fatal(err)
+	}
+	notifyN(n, conf, 0, 3)
+	// stopping spools the pending (incomplete) batch as well
+	n.stop()
+	if names := wh.events(); len(names) != 0 {
+		t.Fatalf("expected no delivered events, got: %v", names)
+	}
+
+	// restart: the spooled batches are delivered once the webhook recovers
+	wh.setFail(false)
+	n = newNotifier("target", dir)
+	if err := n.load(); err != nil {
+		t.Fatal(err)
+	}
+	defer n.stop()
+	notifyN(n, conf, 3, 5)
+	names := waitEvents(t, wh, 5)
+	for i, name := range names {
+		if name != fmt.Sprintf("obj%d", i) {
+			t.Fatalf("events out of order: %v", names)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	for _, h := range n.hooks {
+		if names, _ := batchNames(h.dir); len(names) != 0 {
+			t.Errorf("expected empty spool, got: %v", names)
+		}
+	}
+}
+
+func TestNotifConfWants(t *testing.T) {
+	conf := &cmn.NotifConf{}
+	if !conf.Wants(cmn.NotifPut) {
+		t.Error("expected all events to be delivered by default")
+	}
+	conf.Events = "delete, rename"
+	if conf.Wants(cmn.NotifPut) || !conf.Wants(cmn.NotifRename) {
+		t.Errorf("unexpected events filtering: %q", conf.Events)
+	}
+}
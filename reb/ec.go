@@ -471,6 +471,7 @@ func (s *ecRebalancer) sendFromDisk(slice *ecRebSlice, targets ...*cluster.Snode
 		}
 
 		hdr.ObjAttrs.Atime = lom.AtimeUnix()
+		hdr.ObjAttrs.Wtime = lom.WtimeUnix()
 		hdr.ObjAttrs.Version = lom.Version()
 		if cksum := lom.Cksum(); cksum != nil {
 			hdr.ObjAttrs.CksumType, hdr.ObjAttrs.CksumValue = cksum.Get()
@@ -629,6 +630,9 @@ func (s *ecRebalancer) saveSliceToDisk(data *memsys.SGL, req *pushReq, md *ec.Me
 		if hdr.ObjAttrs.Atime != 0 {
 			lom.SetAtimeUnix(hdr.ObjAttrs.Atime)
 		}
+		if hdr.ObjAttrs.Wtime != 0 {
+			lom.SetWtimeUnix(hdr.ObjAttrs.Wtime)
+		}
 		lom.Lock(true)
 		defer lom.Unlock(true)
 		lom.Uncache()
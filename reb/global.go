@@ -22,6 +22,9 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
+// max number of objects a jogger defers while their destinations are congested
+const maxDeferred = 4096
+
 type (
 	globalJogger struct {
 		joggerBase
@@ -29,6 +32,12 @@ type (
 		sema  chan struct{}
 		errCh chan error
 		ver   int64
+		// objects destined to congested targets - sent upon traversal (see transport.Stream.Congested)
+		deferred []deferredObj
+	}
+	deferredObj struct {
+		lom *cluster.LOM
+		tsi *cluster.Snode
 	}
 	globArgs struct {
 		smap    *cluster.Smap
@@ -497,6 +506,7 @@ func (rj *globalJogger) jog() {
 			glog.Errorf("%s: failed to traverse %s, err: %v", rj.m.t.Snode().Name(), rj.mpath, err)
 		}
 	}
+	rj.sendDeferred()
 	rj.xreb.NotifyDone()
 	rj.wg.Done()
 }
@@ -567,6 +577,15 @@ func (rj *globalJogger) walk(fqn string, de fs.DirEntry) (err error) {
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("%s %s => %s", lom, t.Snode().Name(), tsi.Name())
 	}
+	// deprioritize congested targets so that a single slow node does not stall the rebalance
+	if len(rj.deferred) < maxDeferred && rj.m.streams.Congested(tsi) {
+		rj.deferred = append(rj.deferred, deferredObj{lom: lom, tsi: tsi})
+		return nil
+	}
+	return rj.dispatch(lom, tsi)
+}
+
+func (rj *globalJogger) dispatch(lom *cluster.LOM, tsi *cluster.Snode) (err error) {
 	if rj.sema == nil { // rebalance.multiplier == 1
 		err = rj.send(lom, tsi)
 	} else { // // rebalance.multiplier > 1
@@ -582,6 +601,21 @@ func (rj *globalJogger) walk(fqn string, de fs.DirEntry) (err error) {
 	return
 }
 
+func (rj *globalJogger) sendDeferred() {
+	if len(rj.deferred) > 0 {
+		glog.Infof("%s: sending %d deferred object(s)", rj.xreb, len(rj.deferred))
+	}
+	for _, d := range rj.deferred {
+		if rj.xreb.Aborted() || rj.xreb.Finished() {
+			break
+		}
+		if err := rj.dispatch(d.lom, d.tsi); err != nil {
+			glog.Errorf("%s: failed to send %s => %s, err: %v", rj.m.t.Snode().Name(), d.lom, d.tsi.Name(), err)
+		}
+	}
+	rj.deferred = nil
+}
+
 func (rj *globalJogger) send(lom *cluster.LOM, tsi *cluster.Snode) (err error) {
 	var (
 		file                  *cmn.FileHandle
@@ -612,6 +646,7 @@ func (rj *globalJogger) send(lom *cluster.LOM, tsi *cluster.Snode) (err error) {
 		ObjAttrs: transport.ObjectAttrs{
 			Size:       lom.Size(),
 			Atime:      lom.Atime().UnixNano(),
+			Wtime:      lom.WtimeUnix(),
 			CksumType:  cksumType,
 			CksumValue: cksumValue,
 			Version:    lom.Version(),
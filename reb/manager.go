@@ -342,6 +342,7 @@ func (reb *Manager) recvObj(w http.ResponseWriter, hdr transport.Header, objRead
 		glog.Errorf("%s: early receive from %s %s (stage %s)", reb.t.Snode().Name(), tsid, lom, stages[stage])
 	}
 	lom.SetAtimeUnix(hdr.ObjAttrs.Atime)
+	lom.SetWtimeUnix(hdr.ObjAttrs.Wtime)
 	lom.SetVersion(hdr.ObjAttrs.Version)
 
 	if err := reb.t.PutObject(
@@ -0,0 +1,83 @@
+// Package transport provides streaming object-based transport over http for intra-cluster continuous
+// intra-cluster communications (see README for details and usage example).
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package transport
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Sessions are authenticated as configured by cmn.TLSConf:
+//  * with mutual TLS (net.tls.ca) only the peers that have presented
+//    certificates signed by the CA are accepted;
+//  * with net.tls.hmac_secret the sender signs each session request with
+//    HMAC-SHA256 of the transport name, session ID and timestamp - the
+//    requests with missing, invalid, or stale signatures are rejected.
+//    The signature does not protect against replays within authMaxSkew,
+//    which is why the config requires net.tls.enabled and net.tls.ca (so
+//    that the sender verifies the receiver) along with the secret.
+
+const authMaxSkew = 5 * time.Minute // max difference between the sender's and receiver's clocks
+
+func sessAuth(secret, trname string, sessID int64, now time.Time) string {
+	ts := strconv.FormatInt(now.UnixNano(), 10)
+	return ts + ":" + sessMAC(secret, trname, sessID, ts)
+}
+
+func sessMAC(secret, trname string, sessID int64, ts string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(trname + "\n" + strconv.FormatInt(sessID, 10) + "\n" + ts))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func checkSessAuth(secret, auth, trname string, sessID int64, now time.Time) error {
+	i := strings.IndexByte(auth, ':')
+	if i < 0 {
+		return errors.New("missing or malformed session signature")
+	}
+	ts, sig := auth[:i], auth[i+1:]
+	nano, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid session timestamp %q", ts)
+	}
+	if skew := now.Sub(time.Unix(0, nano)); skew > authMaxSkew || skew < -authMaxSkew {
+		return fmt.Errorf("session timestamp is off by %v", skew)
+	}
+	if !hmac.Equal([]byte(sig), []byte(sessMAC(secret, trname, sessID, ts))) {
+		return errors.New("invalid session signature")
+	}
+	return nil
+}
+
+// setAuth signs the session request if required by the configuration
+func setAuth(request *http.Request, trname string, sessID int64) {
+	if secret := cmn.GCO.Get().Net.TLS.HMACSecret; secret != "" {
+		request.Header.Set(cmn.HeaderSessAuth, sessAuth(secret, trname, sessID, time.Now()))
+	}
+}
+
+// authenticate returns error if the session request must be rejected
+func authenticate(r *http.Request, trname string, sessID int64) error {
+	conf := &cmn.GCO.Get().Net.TLS
+	if conf.Enabled && conf.CA != "" {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			return errors.New("no verified peer certificate")
+		}
+	}
+	if conf.HMACSecret != "" {
+		return checkSessAuth(conf.HMACSecret, r.Header.Get(cmn.HeaderSessAuth), trname, sessID, time.Now())
+	}
+	return nil
+}
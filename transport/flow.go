@@ -0,0 +1,264 @@
+// Package transport provides streaming object-based transport over http for intra-cluster continuous
+// intra-cluster communications (see README for details and usage example).
+/*
+ * Copyright (c) 2019, NVIDIA CORPORATION. All rights reserved.
+ */
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/xoshiro256"
+)
+
+// Flow control: the receiver accounts for the bytes consumed (i.e., delivered to and
+// returned from the Receive callback) by each sending stream and grants the sender
+// a window - the max number of bytes the stream may have in flight. The window
+// shrinks with the receiver's memory pressure or the pressure set via SetPressure,
+// down to a single object at a time when the pressure is extreme.
+//
+// A new stream starts with the max window. The sender checks the window at object
+// boundaries and, when the next object does not fit, polls the receiver (GET on the same endpoint) for updated credits. While
+// waiting the stream reports itself as congested (see Stream.Congested) - the hint
+// that StreamBundle uses to deprioritize the corresponding destinations.
+
+const (
+	flowWindow     = 64 * cmn.MiB // max window, granted when there's no pressure
+	flowPollMin    = time.Millisecond
+	flowPollMax    = 100 * time.Millisecond
+	flowReqTimeout = 10 * time.Second
+)
+
+type (
+	// sending side
+	flowCtrl struct {
+		s        *Stream
+		key      uint64       // unique stream key: the receiver accounts consumed bytes by key
+		consumed atomic.Int64 // bytes consumed by the receiver as per the last grant
+		window   atomic.Int64 // max bytes in flight as per the last grant
+		waiting  atomic.Bool  // true while waiting for credits
+		off      bool         // no flow control (dry-run or the receiver does not support it)
+	}
+	// receiving side
+	flowStats struct {
+		consumed atomic.Int64 // stream offset consumed by the receiver, in bytes
+		atime    atomic.Int64 // last access time, to cleanup
+	}
+)
+
+// SetPressure sets the pressure (memsys.MemPressureLow, etc.) of the given receive
+// endpoint. The flow-control windows granted to its senders are computed off of the
+// max of this value and the current memory pressure.
+func SetPressure(network, trname string, pressure int) (err error) {
+	mu.Lock()
+	h, ok := handlers[network][trname]
+	mu.Unlock()
+	if !ok {
+		return fmt.Errorf("transport endpoint %s is unknown, network %s", trname, network)
+	}
+	h.pressure.Store(int64(pressure))
+	return
+}
+
+// Congested returns true if the stream is waiting for the receiver to consume the
+// data in flight, or if the receiver has shrunk the window due to its pressure.
+func (s *Stream) Congested() bool {
+	return s.flow.waiting.Load() || s.flow.window.Load() < flowWindow
+}
+
+//
+// flowCtrl
+//
+
+func (fc *flowCtrl) init(s *Stream, dryrun bool) {
+	fc.s = s
+	fc.key = xoshiro256.Hash(uint64(time.Now().UnixNano()) ^ uint64(s.sessID))
+	fc.window.Store(flowWindow)
+	fc.off = dryrun
+}
+
+func (fc *flowCtrl) granted(size int64) bool {
+	if fc.off {
+		return true
+	}
+	inflight := fc.s.stats.Offset.Load() - fc.consumed.Load()
+	return inflight <= 0 || inflight+size <= fc.window.Load()
+}
+
+// wait is called at the object boundary and blocks until the receiver grants enough
+// credits to put the next object on the wire; returns true if the stream gets stopped
+func (fc *flowCtrl) wait(size int64) (stopped bool) {
+	sleep := flowPollMin
+	for !fc.granted(size) {
+		if err := fc.refresh(); err != nil {
+			glog.Errorf("%s: %v", fc.s, err) // keep sending - the session will fail if the receiver is gone
+			break
+		}
+		if fc.granted(size) {
+			break
+		}
+		fc.waiting.Store(true)
+		fc.s.time.inSend.Store(true) // not idle
+		select {
+		case <-time.After(sleep):
+		case <-fc.s.stopCh.Listen():
+			stopped = true
+		}
+		if stopped {
+			break
+		}
+		if sleep *= 2; sleep > flowPollMax {
+			sleep = flowPollMax
+		}
+	}
+	fc.waiting.Store(false)
+	return
+}
+
+// start is called at the beginning of each session: all the data sent by the previous
+// sessions has been consumed by the time their requests complete
+func (fc *flowCtrl) start(request *http.Request) {
+	if fc.off {
+		return
+	}
+	offset := fc.s.stats.Offset.Load()
+	fc.consumed.Store(offset)
+	request.Header.Set(cmn.HeaderSessKey, strconv.FormatUint(fc.key, 10))
+	request.Header.Set(cmn.HeaderFlowConsumed, strconv.FormatInt(offset, 10))
+}
+
+// refresh requests the receiver for the bytes consumed and the current window
+func (fc *flowCtrl) refresh() (err error) {
+	var (
+		request  *http.Request
+		response *http.Response
+		s        = fc.s
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), flowReqTimeout)
+	defer cancel()
+	if request, err = http.NewRequest(http.MethodGet, s.toURL, nil); err != nil {
+		return
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set(cmn.HeaderSessID, strconv.FormatInt(s.sessID, 10))
+	request.Header.Set(cmn.HeaderSessKey, strconv.FormatUint(fc.key, 10))
+	setAuth(request, s.trname, s.sessID)
+	if response, err = s.client.Do(request); err != nil {
+		return
+	}
+	ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		glog.Warningf("%s: flow control is not supported by the receiver (%s)", s, response.Status)
+		fc.off = true
+		return
+	}
+	window, err := strconv.ParseInt(response.Header.Get(cmn.HeaderFlowWindow), 10, 64)
+	if err != nil {
+		return
+	}
+	fc.window.Store(window)
+	// NOTE: no consumed bytes until the receiver gets the current session
+	if c := response.Header.Get(cmn.HeaderFlowConsumed); c != "" {
+		var consumed int64
+		if consumed, err = strconv.ParseInt(c, 10, 64); err != nil {
+			return
+		}
+		fc.consumed.Store(consumed)
+	}
+	if glog.FastV(4, glog.SmoduleTransport) {
+		glog.Infof("%s: in-flight %d, window %d", s, s.stats.Offset.Load()-fc.consumed.Load(), window)
+	}
+	return
+}
+
+//
+// handler (receiving side)
+//
+
+// startFlow is called at the beginning of each session; returns nil if the session
+// is sent without flow control
+func (h *handler) startFlow(r *http.Request) (fs *flowStats) {
+	key, err := strconv.ParseUint(r.Header.Get(cmn.HeaderSessKey), 10, 64)
+	if err != nil {
+		return
+	}
+	consumed, err := strconv.ParseInt(r.Header.Get(cmn.HeaderFlowConsumed), 10, 64)
+	if err != nil {
+		return
+	}
+	fsif, _ := h.flows.LoadOrStore(key, &flowStats{})
+	fs = fsif.(*flowStats)
+	fs.consumed.Store(consumed)
+	fs.atime.Store(time.Now().UnixNano())
+	return
+}
+
+// grant responds to the sender's GET with the consumed bytes and the window
+func (h *handler) grant(w http.ResponseWriter, r *http.Request) {
+	sessID, err := strconv.ParseInt(r.Header.Get(cmn.HeaderSessID), 10, 64)
+	if err != nil || sessID == 0 {
+		cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("%s[:%d]: invalid session ID, err %v", h.trname, sessID, err))
+		return
+	}
+	if err := authenticate(r, h.trname, sessID); err != nil {
+		cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("%s[:%d]: unauthenticated session from %s: %v",
+			h.trname, sessID, r.RemoteAddr, err), http.StatusUnauthorized)
+		return
+	}
+	key, err := strconv.ParseUint(r.Header.Get(cmn.HeaderSessKey), 10, 64)
+	if err != nil {
+		cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("%s[:%d]: invalid stream key, err %v", h.trname, sessID, err))
+		return
+	}
+	if fsif, ok := h.flows.Load(key); ok {
+		fs := fsif.(*flowStats)
+		fs.atime.Store(time.Now().UnixNano())
+		w.Header().Set(cmn.HeaderFlowConsumed, strconv.FormatInt(fs.consumed.Load(), 10))
+	}
+	w.Header().Set(cmn.HeaderFlowWindow, strconv.FormatInt(h.window(), 10))
+}
+
+func (h *handler) window() int64 {
+	var (
+		pressure = int(h.pressure.Load())
+		mem      = h.mem
+	)
+	if mem == nil {
+		mem = memsys.GMM()
+	}
+	if p := mem.MemPressure(); p > pressure {
+		pressure = p
+	}
+	if pressure >= memsys.MemPressureExtreme {
+		return 0 // one object at a time
+	}
+	return flowWindow >> (2 * uint(pressure))
+}
+
+func (h *handler) cleanupFlows(now time.Time) {
+	f := func(key, value interface{}) bool {
+		fs := value.(*flowStats)
+		if now.Sub(time.Unix(0, fs.atime.Load())) > cleanupInterval {
+			h.flows.Delete(key)
+		}
+		return true
+	}
+	h.flows.Range(f)
+}
+
+func (fs *flowStats) add(n int64) {
+	if fs != nil {
+		fs.consumed.Add(n)
+		fs.atime.Store(time.Now().UnixNano())
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/3rdparty/golang/mux"
 	"github.com/NVIDIA/aistore/cmn"
@@ -23,6 +24,7 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/xoshiro256"
 	"github.com/OneOfOne/xxhash"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v3"
 )
 
@@ -52,7 +54,9 @@ type (
 		callback    Receive
 		sessions    sync.Map // map[uint64]*Stats
 		oldSessions sync.Map // map[uint64]time.Time
-		hkName      string   // house-keeping name
+		flows       sync.Map // map[uint64]*flowStats by stream key, see flow.go
+		pressure    atomic.Int64
+		hkName      string // house-keeping name
 		mem         *memsys.Mem2
 	}
 	fixedBuffer struct {
@@ -200,7 +204,7 @@ func GetNetworkStats(network string) (netstats map[string]EndpointStats, err err
 //
 
 func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
+	if r.Method != http.MethodPut && r.Method != http.MethodGet {
 		cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("Invalid http method %s", r.Method))
 		return
 	}
@@ -209,35 +213,57 @@ func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
 		cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("Invalid transport handler name %s - expecting %s", trname, h.trname))
 		return
 	}
+	if r.Method == http.MethodGet {
+		h.grant(w, r)
+		return
+	}
 	var (
-		reader    io.Reader = r.Body
-		lz4Reader *lz4.Reader
-		fbuf      *fixedBuffer
-		debug     = bool(glog.FastV(4, glog.SmoduleTransport))
+		reader     io.Reader = r.Body
+		lz4Reader  *lz4.Reader
+		zstdReader *zstd.Decoder
+		fbuf       *fixedBuffer
+		debug      = bool(glog.FastV(4, glog.SmoduleTransport))
 	)
-	// compression
-	if compressionType := r.Header.Get(cmn.HeaderCompress); compressionType != "" {
-		cmn.Assert(compressionType == cmn.LZ4Compression)
-		lz4Reader = lz4.NewReader(r.Body)
-		reader = lz4Reader
-		if extraBuffering {
-			fbuf = newFixedBuffer(h.mem)
-		}
-	}
 	// session
 	sessID, err := strconv.ParseInt(r.Header.Get(cmn.HeaderSessID), 10, 64)
 	if err != nil || sessID == 0 {
 		cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("%s[:%d]: invalid session ID, err %v", trname, sessID, err))
 		return
 	}
+	if err := authenticate(r, trname, sessID); err != nil {
+		cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("%s[:%d]: unauthenticated session from %s: %v",
+			trname, sessID, r.RemoteAddr, err), http.StatusUnauthorized)
+		return
+	}
+	// compression
+	switch compressionType := r.Header.Get(cmn.HeaderCompress); compressionType {
+	case "":
+	case cmn.LZ4Compression:
+		lz4Reader = lz4.NewReader(r.Body)
+		reader = lz4Reader
+	case cmn.ZstdCompression:
+		if zstdReader, err = zstd.NewReader(r.Body, zstd.WithDecoderConcurrency(1)); err != nil {
+			cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("%s[:%d]: %v", trname, sessID, err))
+			return
+		}
+		reader = zstdReader
+	default:
+		cmn.InvalidHandlerDetailed(w, r, fmt.Sprintf("%s[:%d]: unsupported compression %q", trname, sessID, compressionType))
+		return
+	}
+	if reader != r.Body && extraBuffering {
+		fbuf = newFixedBuffer(h.mem)
+	}
 	uid := uniqueID(r, sessID)
 	statsif, loaded := h.sessions.LoadOrStore(uid, &Stats{})
+	h.oldSessions.Delete(uid) // the same uid when the stream reconnects over HTTP/2 (see uniqueID)
 	if !loaded && debug {
 		xxh, id := UID2SessID(uid)
 		cmn.Assert(id == uint64(sessID))
 		glog.Infof("%s[%d:%d]: start-of-stream from %s", trname, xxh, sessID, r.RemoteAddr) // r.RemoteAddr => xxh
 	}
 	stats := statsif.(*Stats)
+	flow := h.startFlow(r)
 
 	// Rx loop
 	it := &iterator{trname: trname, body: reader, fbuf: fbuf, headerBuf: make([]byte, maxHeaderSize)}
@@ -245,6 +271,7 @@ func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
 		objReader, hl64, err := it.next()
 		if hl64 != 0 {
 			_ = stats.Offset.Add(hl64)
+			flow.add(hl64)
 		}
 		if objReader != nil {
 			er := err
@@ -259,6 +286,7 @@ func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
 					siz = stats.Size.Add(hdr.ObjAttrs.Size)
 					off = stats.Offset.Add(hdr.ObjAttrs.Size)
 				)
+				flow.add(hdr.ObjAttrs.Size)
 				if debug {
 					xxh, _ := UID2SessID(uid)
 					glog.Infof("%s[%d:%d]: off=%d, size=%d(%d), num=%d - %s/%s",
@@ -279,6 +307,9 @@ func (h *handler) receive(w http.ResponseWriter, r *http.Request) {
 			if lz4Reader != nil {
 				lz4Reader.Reset(nil)
 			}
+			if zstdReader != nil {
+				zstdReader.Close()
+			}
 			if fbuf != nil {
 				fbuf.Free()
 			}
@@ -299,6 +330,7 @@ func (h *handler) cleanupOldSessions() time.Duration {
 		return true
 	}
 	h.oldSessions.Range(f)
+	h.cleanupFlows(now)
 	return cleanupInterval
 }
 
@@ -445,6 +477,9 @@ func ExtHeader(body []byte, hlen int) (hdr Header) {
 	off, hdr.BckIsAIS = extBool(off, body)
 	off, hdr.Opaque = extByte(off, body)
 	off, hdr.ObjAttrs = extAttrs(off, body)
+	if off < hlen { // optional fields (see insHeader)
+		off, hdr.ObjAttrs.Wtime = extInt64(off, body)
+	}
 	if _, ok := cmn.CheckDebug(pkgName); ok {
 		cmn.AssertMsg(off == hlen, fmt.Sprintf("off %d, hlen %d", off, hlen))
 	}
@@ -490,6 +525,8 @@ func extAttrs(off int, from []byte) (n int, attr ObjectAttrs) {
 //
 // sessID => unique ID
 //
+// uniqueID combines the sender's address and the session ID - the latter makes it unique
+// when multiple streams are multiplexed over the same HTTP/2 connection
 func uniqueID(r *http.Request, sessID int64) uint64 {
 	x := xxhash.ChecksumString64S(r.RemoteAddr, cmn.MLCG32)
 	return (x&math.MaxUint32)<<32 | uint64(sessID)
@@ -27,6 +27,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/xoshiro256"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v3"
 )
 
@@ -38,6 +39,10 @@ const (
 	tickUnit       = time.Second
 	defaultIdleOut = time.Second * 2
 	burstNum       = 32 // default max num objects that can be posted for sending without any back-pressure
+
+	// adaptive compression (see cmprStream.sample)
+	cmprSampleSize = 4 * cmn.MiB         // compression ratio is sampled every so many bytes
+	cmprProbeSize  = 64 * cmprSampleSize // bytes sent uncompressed before trying to compress again
 )
 
 // stream TCP/HTTP session: inactive <=> active transitions
@@ -90,14 +95,15 @@ type (
 			err        error
 			reason     *string
 		}
-		lz4s lz4Stream
+		cmpr cmprStream
+		flow flowCtrl
 	}
 	// advanced usage: additional stream control
 	Extra struct {
 		IdleTimeout time.Duration   // stream idle timeout: causes PUT to terminate (and renew on the next obj send)
 		Ctx         context.Context // presumably, result of context.WithCancel(context.Background()) by the caller
 		Callback    SendCallback    // typical usage: to free SGLs, close files, etc.
-		Compression string          // see CompressAlways, etc. enum
+		Compression string          // see CompressAlways, etc. enum and cmn.ParseCompression
 		Mem2        *memsys.Mem2    // compression-related buffering
 		Config      *cmn.Config
 	}
@@ -117,6 +123,7 @@ type (
 		CksumType  string // checksum type
 		CksumValue string // checksum of the object produced by given checksum type
 		Version    string // version of the object
+		Wtime      int64  // write (PUT) time - nanoseconds since UNIX epoch (optional, see insHeader)
 	}
 
 	// object header
@@ -142,12 +149,27 @@ type (
 
 // internal
 type (
-	lz4Stream struct {
+	cmprStream struct {
 		s             *Stream
-		zw            *lz4.Writer // orig reader => zw
+		zw            compressor  // orig reader => zw
 		sgl           *memsys.SGL // zw => bb => network
+		alg           string      // cmn.LZ4Compression, etc.
+		minRatio      float64     // adaptive: min ratio that warrants compression (zero - always compress)
 		blockMaxSize  int         // *uncompressed* block max size
 		frameChecksum bool        // true: checksum lz4 frames
+		off           bool        // adaptive: the current session is not compressed
+		renew         bool        // adaptive: end the current session at the object boundary and start the next one
+		mark          struct {
+			offset int64 // stream offset at the beginning of the current sample
+			size   int64 // ditto, compressed size
+			raw    int64 // uncompressed session: stream offset accounted for in the compressed size
+		}
+	}
+	// lz4.Writer or zstd.Encoder
+	compressor interface {
+		io.WriteCloser
+		Flush() error
+		Reset(w io.Writer)
 	}
 	obj struct {
 		hdr      Header         // object header
@@ -187,16 +209,34 @@ var (
 	nextSID    = *atomic.NewInt64(100) // unique session IDs starting from 101
 	sc         = &StreamCollector{}
 	gc         *collector // real collector
+	h2client   struct {   // shared by all streams with net.http.http2
+		once   sync.Once
+		client *http.Client
+	}
 )
 
 // default HTTP client used with streams (intra-data network)
 // resulting transport will dial timeout=30s, timeout=no-timeout
+// NOTE: with net.http.http2 all the streams share the same client, and therefore
+// the same (multiplexed) connection to each destination
 func NewIntraDataClient() *http.Client {
 	config := cmn.GCO.Get()
+	if config.Net.HTTP.HTTP2 {
+		h2client.once.Do(func() { h2client.client = newIntraDataClient(config) })
+		return h2client.client
+	}
+	return newIntraDataClient(config)
+}
+
+func newIntraDataClient(config *cmn.Config) *http.Client {
+	tlsConf, err := config.Net.TLS.ClientConfig()
+	cmn.AssertNoErr(err) // validated when loading config
 	return cmn.NewClient(cmn.TransportArgs{
 		SndRcvBufSize:   config.Net.L4.SndRcvBufSize,
 		WriteBufferSize: config.Net.HTTP.WriteBufferSize,
 		ReadBufferSize:  config.Net.HTTP.ReadBufferSize,
+		TLS:             tlsConf,
+		HTTP2:           config.Net.HTTP.HTTP2,
 	})
 }
 
@@ -226,18 +266,25 @@ func NewStream(client *http.Client, toURL string, extra *Extra) (s *Stream) {
 			if config == nil {
 				config = cmn.GCO.Get()
 			}
-			s.lz4s.s = s
-			s.lz4s.blockMaxSize = config.Compression.BlockMaxSize
-			s.lz4s.frameChecksum = config.Compression.Checksum
+			rules, err := cmn.ParseCompression(extra.Compression)
+			if err != nil {
+				glog.Errorf("%v - using %s", err, cmn.LZ4Compression)
+				rules = cmn.CompressionRules{Alg: cmn.LZ4Compression, Enabled: true}
+			}
+			s.cmpr.s = s
+			s.cmpr.alg = rules.Alg
+			s.cmpr.minRatio = rules.MinRatio
+			s.cmpr.blockMaxSize = config.Compression.BlockMaxSize
+			s.cmpr.frameChecksum = config.Compression.Checksum
 			mem := extra.Mem2
 			if mem == nil {
 				mem = memsys.GMM()
 				glog.Warningln("Using global memory manager for streaming inline compression")
 			}
-			if s.lz4s.blockMaxSize >= memsys.MaxSlabSize {
-				s.lz4s.sgl = mem.NewSGL(memsys.MaxSlabSize, memsys.MaxSlabSize)
+			if s.cmpr.blockMaxSize >= memsys.MaxSlabSize {
+				s.cmpr.sgl = mem.NewSGL(memsys.MaxSlabSize, memsys.MaxSlabSize)
 			} else {
-				s.lz4s.sgl = mem.NewSGL(cmn.KiB*64, cmn.KiB*64)
+				s.cmpr.sgl = mem.NewSGL(cmn.KiB*64, cmn.KiB*64)
 			}
 		}
 	}
@@ -250,7 +297,7 @@ func NewStream(client *http.Client, toURL string, extra *Extra) (s *Stream) {
 	if !s.compressed() {
 		s.lid = fmt.Sprintf("%s[%d]", s.trname, s.sessID)
 	} else {
-		s.lid = fmt.Sprintf("%s[%d[%s]]", s.trname, s.sessID, cmn.B2S(int64(s.lz4s.blockMaxSize), 0))
+		s.lid = fmt.Sprintf("%s[%d[%s:%s]]", s.trname, s.sessID, s.cmpr.alg, cmn.B2S(int64(s.cmpr.blockMaxSize), 0))
 	}
 
 	// burst size: the number of objects the caller is permitted to post for sending
@@ -291,6 +338,10 @@ func NewStream(client *http.Client, toURL string, extra *Extra) (s *Stream) {
 		}
 		cmn.Assert(dryrun || client != nil)
 	}
+	if dryrun {
+		s.cmpr.minRatio = 0 // dry-run does not compress, nothing to adapt
+	}
+	s.flow.init(s, dryrun)
 	go s.sendLoop(ctx, dryrun) // handle SQ
 	go s.cmplLoop()            // handle SCQ
 
@@ -298,7 +349,10 @@ func NewStream(client *http.Client, toURL string, extra *Extra) (s *Stream) {
 	return
 }
 
-func (s *Stream) compressed() bool { return s.lz4s.s == s }
+func (s *Stream) compressed() bool { return s.cmpr.s == s }
+
+// renewing returns true if the current session has been ended to start the next one right away
+func (s *Stream) renewing() bool { return s.compressed() && s.cmpr.renew }
 
 // Asynchronously send an object defined by its header and its reader.
 // ---------------------------------------------------------------------------------------
@@ -389,9 +443,9 @@ func (s *Stream) terminate() {
 	gc.remove(s)
 
 	if s.compressed() {
-		s.lz4s.sgl.Free()
-		if s.lz4s.zw != nil {
-			s.lz4s.zw.Reset(nil)
+		s.cmpr.sgl.Free()
+		if s.cmpr.zw != nil {
+			s.cmpr.zw.Reset(nil)
 		}
 	}
 }
@@ -425,7 +479,7 @@ func (hdr *Header) IsHeaderOnly() bool { return hdr.ObjAttrs.Size == 0 || hdr.Is
 
 func (s *Stream) sendLoop(ctx context.Context, dryrun bool) {
 	for {
-		if s.sessST.Load() == active {
+		if s.sessST.Load() == active || s.renewing() {
 			if dryrun {
 				s.dryrun()
 			} else if err := s.doRequest(ctx); err != nil {
@@ -434,6 +488,9 @@ func (s *Stream) sendLoop(ctx context.Context, dryrun bool) {
 				break
 			}
 		}
+		if s.renewing() {
+			continue
+		}
 		if !s.isNextReq(ctx) {
 			break
 		}
@@ -532,18 +589,10 @@ func (s *Stream) doRequest(ctx context.Context) (err error) {
 		response *http.Response
 		body     io.Reader = s
 	)
-	if s.compressed() {
-		s.lz4s.sgl.Reset()
-		if s.lz4s.zw == nil {
-			s.lz4s.zw = lz4.NewWriter(s.lz4s.sgl)
-		} else {
-			s.lz4s.zw.Reset(s.lz4s.sgl)
-		}
-		// lz4 framing spec at http://fastcompression.blogspot.com/2013/04/lz4-streaming-format-final.html
-		s.lz4s.zw.Header.BlockChecksum = false
-		s.lz4s.zw.Header.NoChecksum = !s.lz4s.frameChecksum
-		s.lz4s.zw.Header.BlockMaxSize = s.lz4s.blockMaxSize
-		body = &s.lz4s
+	compressed := s.compressed() && s.cmpr.start()
+	if compressed {
+		s.cmpr.reset()
+		body = &s.cmpr
 	}
 	if request, err = http.NewRequest(http.MethodPut, s.toURL, body); err != nil {
 		return
@@ -555,13 +604,17 @@ func (s *Stream) doRequest(ctx context.Context) (err error) {
 	if glog.FastV(4, glog.SmoduleTransport) {
 		glog.Infof("%s: Do", s)
 	}
-	if s.compressed() {
-		request.Header.Set(cmn.HeaderCompress, cmn.LZ4Compression)
+	if compressed {
+		request.Header.Set(cmn.HeaderCompress, s.cmpr.alg)
 	}
 	request.Header.Set(cmn.HeaderSessID, strconv.FormatInt(s.sessID, 10))
+	setAuth(request, s.trname, s.sessID)
+	s.flow.start(request)
 	response, err = s.client.Do(request)
 	if err == nil {
-		if glog.FastV(4, glog.SmoduleTransport) {
+		if response.StatusCode == http.StatusUnauthorized {
+			glog.Errorf("%s: %s", s, response.Status)
+		} else if glog.FastV(4, glog.SmoduleTransport) {
 			glog.Infof("%s: Done", s)
 		}
 	} else {
@@ -592,6 +645,10 @@ func (s *Stream) Read(b []byte) (n int, err error) {
 			return s.sendHdr(b)
 		}
 	}
+	if s.compressed() && s.cmpr.minRatio > 0 && s.cmpr.sample() {
+		err = io.EOF // end of the current session, see cmprStream.sample
+		return
+	}
 repeat:
 	select {
 	case s.sendoff.obj = <-s.workCh: // next object OR idle tick
@@ -601,6 +658,11 @@ repeat:
 			}
 			return s.deactivate()
 		}
+		if !s.sendoff.obj.hdr.IsHeaderOnly() && s.flow.wait(s.sendoff.obj.hdr.ObjAttrs.Size) {
+			glog.Infof("%s: stopped while waiting for credits", s)
+			err = io.EOF // NOTE: the object will be completed upon termination, see sendLoop
+			return
+		}
 		l := s.insHeader(s.sendoff.obj.hdr)
 		s.header = s.maxheader[:l]
 		return s.sendHdr(b)
@@ -703,6 +765,11 @@ func (s *Stream) insHeader(hdr Header) (l int) {
 	l = insBool(l, s.maxheader, hdr.BckIsAIS)
 	l = insByte(l, s.maxheader, hdr.Opaque)
 	l = insAttrs(l, s.maxheader, hdr.ObjAttrs)
+	// optional fields follow all the others and are written only when set:
+	// receivers that do not know them skip the rest of the header (see hlen)
+	if hdr.ObjAttrs.Wtime != 0 {
+		l = insInt64(l, s.maxheader, hdr.ObjAttrs.Wtime)
+	}
 	hlen := l - cmn.SizeofI64*2
 	insInt64(0, s.maxheader, int64(hlen))
 	checksum := xoshiro256.Hash(uint64(hlen))
@@ -787,43 +854,121 @@ func (r *nopReadCloser) Read([]byte) (n int, err error) { return }
 func (r *nopReadCloser) Close() error                   { return nil }
 
 //
-// lz4Stream ---------------------------
+// cmprStream ---------------------------
 //
 
-func (lz4s *lz4Stream) Read(b []byte) (n int, err error) {
+// start is called at the beginning of each session, returns true if the
+// session is compressed
+func (cs *cmprStream) start() bool {
+	if cs.renew {
+		cs.renew = false
+		cs.off = !cs.off
+		if glog.FastV(4, glog.SmoduleTransport) {
+			glog.Infof("%s: compression off=%t, ratio=%.2f", cs.s, cs.off, cs.s.stats.CompressionRatio())
+		}
+	}
+	cs.mark.offset = cs.s.stats.Offset.Load()
+	cs.mark.size = cs.s.stats.CompressedSize.Load()
+	cs.mark.raw = cs.mark.offset
+	return !cs.off
+}
+
+// Adaptive compression: sample is called at the object boundary and returns true to end
+// the current session and to start the next one right away, so that:
+// - a compressed session is ended when the compression ratio of the last cmprSampleSize
+//   bytes drops below the configured minimum (e.g., when sending JPEGs);
+// - an uncompressed session is ended after cmprProbeSize bytes to try compressing again.
+// The compressed size of uncompressed sessions is the size of the sent data
+// (see Stats.CompressionRatio).
+func (cs *cmprStream) sample() bool {
+	var (
+		stats  = &cs.s.stats
+		offset = stats.Offset.Load()
+		sample = offset - cs.mark.offset
+	)
+	if cs.off {
+		stats.CompressedSize.Add(offset - cs.mark.raw)
+		cs.mark.raw = offset
+		if sample < cmprProbeSize {
+			return false
+		}
+	} else {
+		if sample < cmprSampleSize {
+			return false
+		}
+		size := stats.CompressedSize.Load()
+		if float64(sample) >= cs.minRatio*float64(size-cs.mark.size) {
+			cs.mark.offset, cs.mark.size = offset, size // next sample
+			return false
+		}
+	}
+	cs.renew = true
+	return true
+}
+
+func (cs *cmprStream) reset() {
+	cs.sgl.Reset()
+	if cs.alg == cmn.ZstdCompression {
+		if cs.zw == nil {
+			zw, err := zstd.NewWriter(cs.sgl, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+			cmn.AssertNoErr(err)
+			cs.zw = zw
+		} else {
+			cs.zw.Reset(cs.sgl)
+		}
+		return
+	}
+	var zw *lz4.Writer
+	if cs.zw == nil {
+		zw = lz4.NewWriter(cs.sgl)
+		cs.zw = zw
+	} else {
+		zw = cs.zw.(*lz4.Writer)
+		zw.Reset(cs.sgl)
+	}
+	// lz4 framing spec at http://fastcompression.blogspot.com/2013/04/lz4-streaming-format-final.html
+	zw.Header.BlockChecksum = false
+	zw.Header.NoChecksum = !cs.frameChecksum
+	zw.Header.BlockMaxSize = cs.blockMaxSize
+}
+
+func (cs *cmprStream) Read(b []byte) (n int, err error) {
 	var (
-		sendoff = &lz4s.s.sendoff
+		sendoff = &cs.s.sendoff
 		last    = sendoff.obj.hdr.IsLast()
 		retry   = 64 // insist on returning n > 0
 	)
-	if lz4s.sgl.Len() > 0 {
-		n, err = lz4s.sgl.Read(b)
+	if cs.sgl.Len() > 0 {
+		n, err = cs.sgl.Read(b)
 		if err == io.EOF { // reusing/rewinding this buf multiple times
 			err = nil
 		}
 		goto ex
 	}
 re:
-	n, err = lz4s.s.Read(b)
-	_, _ = lz4s.zw.Write(b[:n])
-	if last {
-		lz4s.zw.Close()
+	n, err = cs.s.Read(b)
+	_, _ = cs.zw.Write(b[:n])
+	if last || cs.renew {
+		cs.zw.Close()
 		retry = 0
-	} else if lz4s.s.sendoff.obj.reader == nil /*eoObj*/ || err != nil {
-		lz4s.zw.Flush()
+	} else if cs.s.sendoff.obj.reader == nil /*eoObj*/ || sendoff.obj.hdr.IsHeaderOnly() || err != nil {
+		// flush at object boundaries: the sender may next block on flow control
+		// waiting for the receiver to consume what's been sent so far
+		cs.zw.Flush()
 		retry = 0
 	}
-	n, _ = lz4s.sgl.Read(b)
+	n, _ = cs.sgl.Read(b)
 	if n == 0 && retry > 0 {
 		runtime.Gosched()
 		retry--
 		goto re
 	}
 ex:
-	lz4s.s.stats.CompressedSize.Add(int64(n))
+	cs.s.stats.CompressedSize.Add(int64(n))
 
-	if lz4s.sgl.Len() == 0 {
-		lz4s.sgl.Reset()
+	last = last || cs.renew
+	if cs.sgl.Len() == 0 {
+		cs.sgl.Reset()
 		if last && err == nil {
 			err = io.EOF
 		}
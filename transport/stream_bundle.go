@@ -240,6 +240,21 @@ func (sb *StreamBundle) apply(action int) {
 	wg.Wait()
 }
 
+// Congested returns true if all the streams to the given destination are congested
+// (see Stream.Congested), so that the caller could send to other destinations first.
+func (sb *StreamBundle) Congested(si *cluster.Snode) bool {
+	robin, ok := sb.get()[si.DaemonID]
+	if !ok {
+		return false
+	}
+	for _, s := range robin.stsdest {
+		if !s.Congested() {
+			return false
+		}
+	}
+	return true
+}
+
 // TODO: collect stats from all (stsdest) STreams to the Same Destination, and possibly
 //       aggregate averages actross them.
 func (sb *StreamBundle) GetStats() BundleStats {
@@ -280,13 +295,30 @@ func (sb *StreamBundle) sendOne(robin *robin, hdr Header, reader cmn.ReadOpenClo
 		}
 	}
 	if sb.multiplier > 1 {
-		i = int(robin.i.Inc()) % len(robin.stsdest)
+		i = robin.next()
 	}
 	s := robin.stsdest[i]
 	err = s.Send(hdr, reader2, cb, cmplPtr, prc)
 	return
 }
 
+// next selects the next stream in a round-robin fashion while skipping
+// the congested ones, unless all of them are congested
+func (robin *robin) next() (i int) {
+	var (
+		l     = len(robin.stsdest)
+		start = int(robin.i.Inc())
+	)
+	for j := 0; j < l; j++ {
+		i = (start + j) % l
+		if !robin.stsdest[i].Congested() {
+			robin.i.Add(int64(j))
+			return
+		}
+	}
+	return start % l
+}
+
 // "Resync" streams asynchronously (is a slowpath); calls stream.Stop()
 func (sb *StreamBundle) Resync() {
 	sb.smaplock.Lock()
@@ -21,6 +21,7 @@ package transport_test
 //
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"flag"
@@ -29,6 +30,7 @@ import (
 	"io/ioutil"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -48,6 +50,7 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/tutils"
+	"golang.org/x/net/http2/h2c"
 )
 
 const (
@@ -123,8 +126,8 @@ func Example_headers() {
 	stream.Fin()
 
 	// Output:
-	// {Bucket:abc Objname:X ObjAttrs:{Atime:663346294 Size:231 CksumType:xxhash CksumValue:hash Version:2} Opaque:[] BckIsAIS:false} (88)
-	// {Bucket:abracadabra Objname:p/q/s ObjAttrs:{Atime:663346294 Size:213 CksumType:xxhash CksumValue:hash Version:2} Opaque:[49 50 51] BckIsAIS:true} (103)
+	// {Bucket:abc Objname:X ObjAttrs:{Atime:663346294 Size:231 CksumType:xxhash CksumValue:hash Version:2 Wtime:0} Opaque:[] BckIsAIS:false} (88)
+	// {Bucket:abracadabra Objname:p/q/s ObjAttrs:{Atime:663346294 Size:213 CksumType:xxhash CksumValue:hash Version:2 Wtime:0} Opaque:[49 50 51] BckIsAIS:true} (103)
 }
 
 func sendText(stream *transport.Stream, txt1, txt2 string) {
@@ -385,6 +388,223 @@ func Test_MultipleNetworks(t *testing.T) {
 	}
 }
 
+func Test_AuthenticatedSessions(t *testing.T) {
+	var (
+		network = "auth"
+		mux     = mux.NewServeMux()
+	)
+	config := cmn.GCO.BeginUpdate()
+	config.Net.TLS.HMACSecret = "secret"
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		config := cmn.GCO.BeginUpdate()
+		config.Net.TLS.HMACSecret = ""
+		cmn.GCO.CommitUpdate(config)
+	}()
+
+	totalRecv, recvFunc := makeRecvFunc(t)
+	transport.SetMux(network, mux)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	path, err := transport.Register(network, "endpoint", recvFunc)
+	tassert.CheckFatal(t, err)
+
+	// signed session
+	httpclient := &http.Client{Transport: &http.Transport{}}
+	stream := transport.NewStream(httpclient, ts.URL+path, nil)
+	hdr, reader := makeRandReader()
+	stream.Send(hdr, reader, nil, nil)
+	stream.Fin()
+	time.Sleep(time.Second) // FIN has been sent but not necessarily received
+	if *totalRecv != hdr.ObjAttrs.Size {
+		t.Fatalf("total received bytes %d is different from expected: %d", *totalRecv, hdr.ObjAttrs.Size)
+	}
+
+	// unsigned and forged sessions
+	for _, auth := range []string{"", strconv.FormatInt(time.Now().UnixNano(), 10) + ":deadbeef"} {
+		req, err := http.NewRequest(http.MethodPut, ts.URL+path, nil)
+		tassert.CheckFatal(t, err)
+		req.Header.Set(cmn.HeaderSessID, "1")
+		if auth != "" {
+			req.Header.Set(cmn.HeaderSessAuth, auth)
+		}
+		resp, err := httpclient.Do(req)
+		tassert.CheckFatal(t, err)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected status %d for session auth %q, got: %d", http.StatusUnauthorized, auth, resp.StatusCode)
+		}
+	}
+}
+
+func Test_CompressionRules(t *testing.T) {
+	tests := []struct {
+		compression    string
+		incompressible bool
+		minRatio       float64
+		maxRatio       float64
+	}{
+		{compression: "zstd", minRatio: 2, maxRatio: math.Inf(1)},
+		{compression: "zstd,ratio=1.5", minRatio: 2, maxRatio: math.Inf(1)},
+		{compression: "zstd,ratio=1.5", incompressible: true, minRatio: 0.9, maxRatio: 1.1},
+		{compression: "ratio=1.5", incompressible: true, minRatio: 0.9, maxRatio: 1.1},
+	}
+	const (
+		objSize = cmn.MiB
+		numObjs = 32
+	)
+	random := newRand(time.Now().UnixNano())
+	for idx, test := range tests {
+		network := fmt.Sprintf("cmpr-rules-%d", idx)
+		mux := mux.NewServeMux()
+		transport.SetMux(network, mux)
+		ts := httptest.NewServer(mux)
+		defer ts.Close()
+		totalRecv, recvFunc := makeRecvFunc(t)
+		path, err := transport.Register(network, "endpoint", recvFunc)
+		tassert.CheckFatal(t, err)
+
+		httpclient := &http.Client{Transport: &http.Transport{}}
+		stream := transport.NewStream(httpclient, ts.URL+path, &transport.Extra{Compression: test.compression, Mem2: Mem2})
+		for i := 0; i < numObjs; i++ {
+			buf := make([]byte, objSize)
+			if test.incompressible {
+				random.Read(buf)
+			} else {
+				for off := 0; off < objSize; off += copy(buf[off:], text) {
+				}
+			}
+			hdr := transport.Header{Bucket: "a", Objname: strconv.Itoa(i), ObjAttrs: transport.ObjectAttrs{Size: objSize}}
+			stream.Send(hdr, ioutil.NopCloser(bytes.NewReader(buf)), nil, nil)
+		}
+		stream.Fin()
+		time.Sleep(time.Second) // FIN has been sent but not necessarily received
+
+		if *totalRecv != objSize*numObjs {
+			t.Fatalf("%q: total received bytes %d is different from expected: %d", test.compression, *totalRecv, objSize*numObjs)
+		}
+		stats := stream.GetStats()
+		if ratio := stats.CompressionRatio(); ratio < test.minRatio || ratio > test.maxRatio {
+			t.Errorf("%q: compression ratio %.2f is out of the expected range [%.2f, %.2f]",
+				test.compression, ratio, test.minRatio, test.maxRatio)
+		}
+	}
+}
+
+func Test_FlowControl(t *testing.T) {
+	const (
+		network = "flow"
+		objSize = cmn.MiB
+		numObjs = 96
+		initObj = 64 // objects that fit the initial (max) window
+	)
+	var (
+		mux       = mux.NewServeMux()
+		stream    *transport.Stream
+		numRecv   int64
+		congested bool
+	)
+	transport.SetMux(network, mux)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	recvFunc := func(w http.ResponseWriter, hdr transport.Header, objReader io.Reader, err error) {
+		cmn.Assert(err == nil)
+		written, _ := io.Copy(ioutil.Discard, objReader)
+		if written != hdr.ObjAttrs.Size {
+			t.Fatalf("size %d != %d", written, hdr.ObjAttrs.Size)
+		}
+		time.Sleep(5 * time.Millisecond) // slow receiver
+		congested = congested || stream.Congested()
+		// extreme pressure: once the sender gets the window, the next object cannot be sent
+		// until this one is consumed
+		stats := stream.GetStats()
+		if num := stats.Num.Load(); numRecv >= initObj && num > numRecv+1 {
+			t.Errorf("sent %d objects while receiving #%d", num, numRecv+1)
+		}
+		numRecv++
+	}
+	path, err := transport.Register(network, "endpoint", recvFunc)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, transport.SetPressure(network, "endpoint", memsys.MemPressureExtreme))
+
+	httpclient := &http.Client{Transport: &http.Transport{}}
+	stream = transport.NewStream(httpclient, ts.URL+path, nil)
+	buf := make([]byte, objSize)
+	for i := 0; i < numObjs; i++ {
+		hdr := transport.Header{Bucket: "a", Objname: strconv.Itoa(i), ObjAttrs: transport.ObjectAttrs{Size: objSize}}
+		stream.Send(hdr, ioutil.NopCloser(bytes.NewReader(buf)), nil, nil)
+	}
+	stream.Fin()
+	time.Sleep(time.Second) // FIN has been sent but not necessarily received
+
+	if numRecv != numObjs {
+		t.Fatalf("received %d objects, expected %d", numRecv, numObjs)
+	}
+	if !congested {
+		t.Errorf("expected the stream to be congested")
+	}
+}
+
+func Test_HTTP2Multiplexing(t *testing.T) {
+	const (
+		network    = "h2c"
+		numStreams = 8
+		numObjs    = 16
+	)
+	var (
+		mux     = mux.NewServeMux()
+		numConn atomic.Int64
+		wg      = &sync.WaitGroup{}
+	)
+	transport.SetMux(network, mux)
+	ts := httptest.NewUnstartedServer(h2c.NewHandler(mux, cmn.NewHTTP2Server()))
+	ts.Config.ConnState = func(_ net.Conn, cs http.ConnState) {
+		if cs == http.StateNew {
+			numConn.Inc()
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+	totalRecv, recvFunc := makeRecvFunc(t)
+	path, err := transport.Register(network, "endpoint", recvFunc)
+	tassert.CheckFatal(t, err)
+
+	httpclient := cmn.NewClient(cmn.TransportArgs{HTTP2: true})
+	streams := make([]*transport.Stream, numStreams)
+	for i := range streams {
+		streams[i] = transport.NewStream(httpclient, ts.URL+path, nil)
+	}
+	var size int64
+	for _, stream := range streams {
+		for j := 0; j < numObjs; j++ {
+			hdr, reader := makeRandReader()
+			size += hdr.ObjAttrs.Size
+			stream.Send(hdr, reader, nil, nil)
+		}
+	}
+	for _, stream := range streams {
+		wg.Add(1)
+		go func(stream *transport.Stream) {
+			stream.Fin()
+			wg.Done()
+		}(stream)
+	}
+	wg.Wait()
+	time.Sleep(time.Second) // FIN has been sent but not necessarily received
+
+	if *totalRecv != size {
+		t.Fatalf("total received bytes %d is different from expected: %d", *totalRecv, size)
+	}
+	if n := numConn.Load(); n != 1 {
+		t.Errorf("expected all streams to be multiplexed over a single connection, got %d connections", n)
+	}
+	netstats, err := transport.GetNetworkStats(network)
+	tassert.CheckFatal(t, err)
+	if eps := netstats["endpoint"]; len(eps) != numStreams {
+		t.Errorf("expected stats of %d streams, got %d", numStreams, len(eps))
+	}
+}
+
 func Test_OnSendCallback(t *testing.T) {
 	var (
 		objectCnt = 10000
@@ -450,6 +670,7 @@ func Test_ObjAttrs(t *testing.T) {
 			CksumType:  cmn.ChecksumXXHash,
 			CksumValue: "120421",
 			Version:    "102.44",
+			Wtime:      math.MaxInt64,
 		},
 		{
 			Size:       0,
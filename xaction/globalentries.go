@@ -10,6 +10,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/housekeep/lifecycle"
 	"github.com/NVIDIA/aistore/housekeep/lru"
 	"github.com/NVIDIA/aistore/stats"
 )
@@ -42,6 +43,24 @@ func (e *lruEntry) Get() cmn.Xact { return e.xact }
 
 func (e *lruEntry) preRenewHook(_ globalEntry) bool { return true }
 
+//
+// lifecycleEntry
+//
+type lifecycleEntry struct {
+	baseGlobalEntry
+	xact *lifecycle.Xaction
+}
+
+func (e *lifecycleEntry) Start(id int64) error {
+	e.xact = &lifecycle.Xaction{XactBase: *cmn.NewXactBase(id, cmn.ActLifecycle)}
+	return nil
+}
+
+func (e *lifecycleEntry) Kind() string  { return cmn.ActLifecycle }
+func (e *lifecycleEntry) Get() cmn.Xact { return e.xact }
+
+func (e *lifecycleEntry) preRenewHook(_ globalEntry) bool { return true }
+
 type prefetchEntry struct {
 	baseGlobalEntry
 	xact *prefetch
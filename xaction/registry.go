@@ -19,6 +19,7 @@ import (
 	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/housekeep/hk"
+	"github.com/NVIDIA/aistore/housekeep/lifecycle"
 	"github.com/NVIDIA/aistore/housekeep/lru"
 	"github.com/NVIDIA/aistore/ios"
 	"github.com/NVIDIA/aistore/objwalk"
@@ -680,6 +681,16 @@ func (r *registry) RenewLRU() *lru.Xaction {
 	return entry.xact
 }
 
+func (r *registry) RenewLifecycle() *lifecycle.Xaction {
+	e := &lifecycleEntry{}
+	ee, keep, _ := r.renewGlobalXaction(e)
+	entry := ee.(*lifecycleEntry)
+	if keep { // previous lifecycle run is still running
+		return nil
+	}
+	return entry.xact
+}
+
 func (r *registry) RenewGlobalReb(smapVersion, globRebID int64, runnerCnt int, statRunner *stats.Trunner) *GlobalReb {
 	e := &globalRebEntry{smapVersion: smapVersion, globRebID: globRebID, runnerCnt: runnerCnt, statRunner: statRunner}
 	ee, keep, _ := r.renewGlobalXaction(e)